@@ -0,0 +1,422 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder"
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/schema"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// runGet function prints the whole configuration or value under the key. Scalars are printed as is, maps are
+// marshaled with output encoder, other values are printed as JSON.
+func runGet(s *streams, args []string) error {
+	flagSet := newFlagSet()
+	encoderType := flagSet.String("encoder", "", "encoder type of the location")
+	outputType := flagSet.String("output", "", "encoder type of the output, the location encoder by default")
+
+	if err := flagSet.Parse(args); err != nil {
+		return usageError("%s", err.Error())
+	}
+
+	if flagSet.NArg() < 1 || flagSet.NArg() > 2 {
+		return usageError("wrong number of arguments")
+	}
+
+	loc, enc, err := locationWithEncoder(flagSet.Arg(0), *encoderType)
+	if err != nil {
+		return err
+	}
+
+	data, _, err := loc.load(s, enc)
+	if err != nil {
+		return err
+	}
+
+	value, err := config.NewFromRaw(data).GetRawByKey(flagSet.Arg(1))
+	if err != nil {
+		return errors.NewFmt("can't get key '%s' (%s)", flagSet.Arg(1), err.Error()).WithCause(err)
+	}
+
+	output := enc
+	if *outputType != "" {
+		if output, err = encoder.ByType(*outputType); err != nil {
+			return usageError("%s", err.Error())
+		}
+	}
+
+	return printValue(s.stdout, value, output)
+}
+
+// runSet function replaces value under the key and writes configuration back into the location.
+func runSet(s *streams, args []string) error {
+	flagSet := newFlagSet()
+	encoderType := flagSet.String("encoder", "", "encoder type of the location")
+	isString := flagSet.Bool("string", false, "treat value as a string")
+
+	if err := flagSet.Parse(args); err != nil {
+		return usageError("%s", err.Error())
+	}
+
+	if flagSet.NArg() != 3 {
+		return usageError("wrong number of arguments")
+	}
+
+	loc, enc, err := locationWithEncoder(flagSet.Arg(0), *encoderType)
+	if err != nil {
+		return err
+	}
+
+	if loc.path == stdio {
+		return usageError("can't set value in standard input")
+	}
+
+	data, revision, err := loc.load(s, enc)
+	if err != nil {
+		return err
+	}
+
+	data, err = config.SetRawByKey(data, flagSet.Arg(1), parseValue(flagSet.Arg(2), *isString))
+	if err != nil {
+		return errors.NewFmt("can't set key '%s' (%s)", flagSet.Arg(1), err.Error()).WithCause(err)
+	}
+
+	dataBytes, err := enc.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	newRevision, err := loc.write(s, dataBytes, revision)
+	if err != nil {
+		return err
+	}
+
+	printRevision(s.stdout, loc, newRevision)
+
+	return nil
+}
+
+// runPush function uploads file into etcd key as is after checking that it can be parsed. Upload is
+// compare-and-swap operation on the revision that the key had right before the upload, or on the provided one.
+func runPush(s *streams, args []string) error {
+	flagSet := newFlagSet()
+	encoderType := flagSet.String("encoder", "", "encoder type of the file")
+	revision := flagSet.Int64("revision", -1, "expected revision of the key, 0 means the key must not exist")
+	force := flagSet.Bool("force", false, "overwrite the key unconditionally")
+
+	if err := flagSet.Parse(args); err != nil {
+		return usageError("%s", err.Error())
+	}
+
+	if flagSet.NArg() != 2 {
+		return usageError("wrong number of arguments")
+	}
+
+	if *force && *revision >= 0 {
+		return usageError("flags -revision and -force are mutually exclusive")
+	}
+
+	src, enc, err := locationWithEncoder(flagSet.Arg(0), *encoderType)
+	if err != nil {
+		return err
+	}
+
+	dst, err := parseLocation(flagSet.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	if !dst.isEtcd() {
+		return usageError("destination '%s' is not etcd location", dst.name)
+	}
+
+	dataBytes, _, err := src.read(s, false)
+	if err != nil {
+		return err
+	}
+
+	var data map[string]interface{}
+
+	if err = enc.Encode(dataBytes, &data); err != nil {
+		return errors.NewFmt("can't parse '%s' (%s)", src.name, err.Error()).WithCause(err)
+	}
+
+	expectedRevision := *revision
+
+	switch {
+	case *force:
+		expectedRevision = -1
+	case expectedRevision < 0:
+		if _, expectedRevision, err = dst.read(s, true); err != nil {
+			return err
+		}
+	}
+
+	newRevision, err := dst.write(s, dataBytes, expectedRevision)
+	if err != nil {
+		return err
+	}
+
+	printRevision(s.stdout, dst, newRevision)
+
+	return nil
+}
+
+// runDiff function prints semantic difference between two configurations, so the order of keys and formatting don't
+// matter. Process exits with code 1 if there are differences.
+func runDiff(s *streams, args []string) error {
+	flagSet := newFlagSet()
+	encoderType := flagSet.String("encoder", "", "encoder type of both locations")
+
+	if err := flagSet.Parse(args); err != nil {
+		return usageError("%s", err.Error())
+	}
+
+	if flagSet.NArg() != 2 {
+		return usageError("wrong number of arguments")
+	}
+
+	datas := make([]map[string]interface{}, 0, 2)
+
+	for _, name := range flagSet.Args() {
+		loc, enc, err := locationWithEncoder(name, *encoderType)
+		if err != nil {
+			return err
+		}
+
+		data, _, err := loc.load(s, enc)
+		if err != nil {
+			return err
+		}
+
+		datas = append(datas, data)
+	}
+
+	lines := diff(nil, datas[0], datas[1], nil)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(s.stdout, strings.Join(lines, "\n"))
+
+	return errDifferent
+}
+
+// runConvert function converts configuration from one format into another one.
+func runConvert(s *streams, args []string) error {
+	flagSet := newFlagSet()
+	fromType := flagSet.String("from", "", "encoder type of the source location")
+	toType := flagSet.String("to", "", "encoder type of the output, inferred from output file name by default")
+
+	if err := flagSet.Parse(args); err != nil {
+		return usageError("%s", err.Error())
+	}
+
+	if flagSet.NArg() < 1 || flagSet.NArg() > 2 {
+		return usageError("wrong number of arguments")
+	}
+
+	src, enc, err := locationWithEncoder(flagSet.Arg(0), *fromType)
+	if err != nil {
+		return err
+	}
+
+	dstName := stdio
+	if flagSet.NArg() == 2 {
+		dstName = flagSet.Arg(1)
+	}
+
+	dst, output, err := locationWithEncoder(dstName, *toType)
+	if err != nil {
+		return err
+	}
+
+	data, _, err := src.load(s, enc)
+	if err != nil {
+		return err
+	}
+
+	dataBytes, err := output.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	newRevision, err := dst.write(s, dataBytes, -1)
+	if err != nil {
+		return err
+	}
+
+	printRevision(s.stdout, dst, newRevision)
+
+	return nil
+}
+
+// runValidate function validates configuration against JSON Schema document, such as the one produced by
+// schema.Generate function. All violations are printed and process exits with code 1 if there are any.
+func runValidate(s *streams, args []string) error {
+	flagSet := newFlagSet()
+	schemaName := flagSet.String("schema", "", "JSON Schema file")
+	encoderType := flagSet.String("encoder", "", "encoder type of the location")
+
+	if err := flagSet.Parse(args); err != nil {
+		return usageError("%s", err.Error())
+	}
+
+	if flagSet.NArg() != 1 {
+		return usageError("wrong number of arguments")
+	}
+
+	if *schemaName == "" {
+		return usageError("schema file must be provided")
+	}
+
+	schemaBytes, err := os.ReadFile(*schemaName)
+	if err != nil {
+		return errors.NewFmt("can't read from file '%s' (%s)", *schemaName, err.Error()).WithCause(err)
+	}
+
+	sch, err := schema.Parse(schemaBytes)
+	if err != nil {
+		return errors.NewFmt("can't parse schema '%s' (%s)", *schemaName, err.Error()).WithCause(err)
+	}
+
+	loc, enc, err := locationWithEncoder(flagSet.Arg(0), *encoderType)
+	if err != nil {
+		return err
+	}
+
+	data, _, err := loc.load(s, enc)
+	if err != nil {
+		return err
+	}
+
+	err = sch.Validate(data)
+	if err == nil {
+		return nil
+	}
+
+	var multiErr *i2s.MultiError
+	if !errors.As(err, &multiErr) {
+		return err
+	}
+
+	for _, violation := range multiErr.Errors {
+		fmt.Fprintln(s.stdout, violation.Error())
+	}
+
+	return errDifferent
+}
+
+// newFlagSet function creates flag set for a subcommand. Errors are reported by the caller.
+func newFlagSet() *flag.FlagSet {
+	flagSet := flag.NewFlagSet("configctl", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	return flagSet
+}
+
+// locationWithEncoder function parses location and retrieves its encoder.
+func locationWithEncoder(name string, encoderType string) (*location, config.Marshaler, error) {
+	loc, err := parseLocation(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	enc, err := loc.encoder(encoderType)
+	if err != nil {
+		if errors.Is(err, encoder.ErrUnknownEncoder) {
+			return nil, nil, usageError("%s", err.Error())
+		}
+
+		return nil, nil, err
+	}
+
+	return loc, enc, nil
+}
+
+// parseValue function parses value of 'set' command as JSON literal falling back to a string. Whole numbers become
+// integers, so integer keys stay integers in encoded data.
+func parseValue(value string, isString bool) interface{} {
+	if isString {
+		return value
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+
+	var result interface{}
+
+	if err := decoder.Decode(&result); err != nil {
+		return value
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return value
+	}
+
+	return convertNumbers(result)
+}
+
+// convertNumbers function replaces JSON numbers inside decoded value with int or float64 values.
+func convertNumbers(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case json.Number:
+		if intValue, err := strconv.ParseInt(typedValue.String(), 10, 0); err == nil {
+			return int(intValue)
+		}
+
+		floatValue, _ := typedValue.Float64()
+
+		return floatValue
+	case map[string]interface{}:
+		for key, elem := range typedValue {
+			typedValue[key] = convertNumbers(elem)
+		}
+
+		return typedValue
+	case []interface{}:
+		for index, elem := range typedValue {
+			typedValue[index] = convertNumbers(elem)
+		}
+
+		return typedValue
+	default:
+		return value
+	}
+}
+
+// printValue function prints configuration value.
+func printValue(out io.Writer, value interface{}, enc config.Marshaler) error {
+	switch typedValue := value.(type) {
+	case string:
+		fmt.Fprintln(out, typedValue)
+	case map[string]interface{}:
+		dataBytes, err := enc.Marshal(typedValue)
+		if err != nil {
+			return err
+		}
+
+		if _, err = out.Write(dataBytes); err != nil {
+			return errors.NewFmt("can't write standard output (%s)", err.Error()).WithCause(err)
+		}
+	default:
+		fmt.Fprintln(out, formatValue(value))
+	}
+
+	return nil
+}
+
+// printRevision function prints new revision of etcd key after write.
+func printRevision(out io.Writer, loc *location, revision int64) {
+	if loc.isEtcd() {
+		fmt.Fprintf(out, "revision %d\n", revision)
+	}
+}
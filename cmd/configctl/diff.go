@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/lightstar/golib/pkg/config"
+)
+
+// diff function recursively compares two values and appends lines describing differences to the list. Maps are
+// compared key by key, lists element by element, and numbers by their values, so '80' in YAML and '80.0' in JSON are
+// equal. Lines look like '- key: value' for removed keys, '+ key: value' for added ones and '~ key: old -> new' for
+// changed values.
+func diff(path []string, oldValue interface{}, newValue interface{}, lines []string) []string {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+
+	if oldIsMap && newIsMap {
+		return diffMaps(path, oldMap, newMap, lines)
+	}
+
+	oldList, oldIsList := toList(oldValue)
+	newList, newIsList := toList(newValue)
+
+	if oldIsList && newIsList {
+		return diffLists(path, oldList, newList, lines)
+	}
+
+	if !equalScalars(oldValue, newValue) {
+		lines = append(lines, fmt.Sprintf("~ %s: %s -> %s", diffKey(path), formatValue(oldValue),
+			formatValue(newValue)))
+	}
+
+	return lines
+}
+
+// diffMaps function compares two maps.
+func diffMaps(path []string, oldMap map[string]interface{}, newMap map[string]interface{}, lines []string) []string {
+	keys := make([]string, 0, len(oldMap)+len(newMap))
+
+	for key := range oldMap {
+		keys = append(keys, key)
+	}
+
+	for key := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := append(path[:len(path):len(path)], key)
+		oldValue, inOld := oldMap[key]
+		newValue, inNew := newMap[key]
+
+		switch {
+		case !inNew:
+			lines = append(lines, fmt.Sprintf("- %s: %s", diffKey(keyPath), formatValue(oldValue)))
+		case !inOld:
+			lines = append(lines, fmt.Sprintf("+ %s: %s", diffKey(keyPath), formatValue(newValue)))
+		default:
+			lines = diff(keyPath, oldValue, newValue, lines)
+		}
+	}
+
+	return lines
+}
+
+// diffLists function compares two lists.
+func diffLists(path []string, oldList []interface{}, newList []interface{}, lines []string) []string {
+	for i := 0; i < len(oldList) || i < len(newList); i++ {
+		keyPath := append(path[:len(path):len(path)], strconv.Itoa(i))
+
+		switch {
+		case i >= len(newList):
+			lines = append(lines, fmt.Sprintf("- %s: %s", diffKey(keyPath), formatValue(oldList[i])))
+		case i >= len(oldList):
+			lines = append(lines, fmt.Sprintf("+ %s: %s", diffKey(keyPath), formatValue(newList[i])))
+		default:
+			lines = diff(keyPath, oldList[i], newList[i], lines)
+		}
+	}
+
+	return lines
+}
+
+// diffKey function retrieves composite key of the path, or '.' for the root.
+func diffKey(path []string) string {
+	if len(path) == 0 {
+		return "."
+	}
+
+	return config.JoinKey(path...)
+}
+
+// toList function converts list of any kind produced by encoders into a list of values.
+func toList(value interface{}) ([]interface{}, bool) {
+	switch typedValue := value.(type) {
+	case []interface{}:
+		return typedValue, true
+	case []map[string]interface{}:
+		list := make([]interface{}, 0, len(typedValue))
+		for _, elem := range typedValue {
+			list = append(list, elem)
+		}
+
+		return list, true
+	default:
+		return nil, false
+	}
+}
+
+// equalScalars function compares two values that are not both maps or lists.
+func equalScalars(oldValue interface{}, newValue interface{}) bool {
+	oldNumber, oldIsNumber := toNumber(oldValue)
+	newNumber, newIsNumber := toNumber(newValue)
+
+	if oldIsNumber && newIsNumber {
+		return oldNumber == newNumber
+	}
+
+	return formatValue(oldValue) == formatValue(newValue) && oldIsNumber == newIsNumber
+}
+
+// toNumber function converts numeric value of any type into float64.
+func toNumber(value interface{}) (float64, bool) {
+	switch typedValue := value.(type) {
+	case int:
+		return float64(typedValue), true
+	case int64:
+		return float64(typedValue), true
+	case uint64:
+		return float64(typedValue), true
+	case float64:
+		return typedValue, true
+	default:
+		return 0, false
+	}
+}
+
+// formatValue function formats value as JSON, falling back to Go syntax for values JSON doesn't support.
+func formatValue(value interface{}) string {
+	dataBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(dataBytes)
+}
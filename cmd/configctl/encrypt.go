@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// runEncrypt function prints value encrypted with the primary key, so it can be put into configuration.
+func runEncrypt(s *streams, args []string) error {
+	flagSet := newFlagSet()
+	keysName := flagSet.String("keys", "", "file with encryption keys, environment variables are used by default")
+
+	if err := flagSet.Parse(args); err != nil {
+		return usageError("%s", err.Error())
+	}
+
+	if flagSet.NArg() != 1 {
+		return usageError("wrong number of arguments")
+	}
+
+	keyring, err := loadKeyring(*keysName)
+	if err != nil {
+		return err
+	}
+
+	value := flagSet.Arg(0)
+	if value == stdio {
+		data, err := io.ReadAll(s.stdin)
+		if err != nil {
+			return errors.NewFmt("can't read standard input (%s)", err.Error()).WithCause(err)
+		}
+
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+
+	encrypted, err := keyring.Encrypt(value)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(s.stdout, encrypted)
+
+	return nil
+}
+
+// runGenKey function prints new random key entry that can be added to the encryption keys.
+func runGenKey(s *streams, args []string) error {
+	if len(args) != 1 || args[0] == "" || strings.Contains(args[0], ":") {
+		return usageError("key id must be provided and can't contain colons")
+	}
+
+	entry, err := config.GenerateKey(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(s.stdout, entry)
+
+	return nil
+}
+
+// loadKeyring function loads encryption keys from the file or from environment variables.
+func loadKeyring(name string) (*config.Keyring, error) {
+	if name == "" {
+		keyring, err := config.KeyringFromEnv()
+		if errors.Is(err, config.ErrNoKeyring) {
+			return nil, usageError("encryption keys must be provided in %s or %s environment variables, or -keys flag",
+				config.KeysEnvVar, config.KeysFileEnvVar)
+		}
+
+		return keyring, err
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, errors.NewFmt("can't read from file '%s' (%s)", name, err.Error()).WithCause(err)
+	}
+
+	return config.ParseKeyring(string(data))
+}
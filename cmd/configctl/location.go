@@ -0,0 +1,158 @@
+package main
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder"
+	"github.com/lightstar/golib/pkg/config/etcd"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+const (
+	etcdScheme = "etcd://"
+	fileScheme = "file://"
+	stdio      = "-"
+	filePerm   = 0o644
+)
+
+// location structure is a place where configuration data is stored: a file, standard input or output, or etcd key.
+type location struct {
+	name      string
+	path      string
+	endpoints []string
+	key       string
+}
+
+// parseLocation function parses location from command-line argument.
+func parseLocation(name string) (*location, error) {
+	switch {
+	case strings.HasPrefix(name, etcdScheme):
+		u, err := url.Parse(name)
+		if err != nil {
+			return nil, usageError("malformed location '%s' (%s)", name, err.Error())
+		}
+
+		if u.Host == "" || u.Path == "" || u.Path == "/" {
+			return nil, usageError("location '%s' must have endpoints and key", name)
+		}
+
+		return &location{name: name, endpoints: strings.Split(u.Host, ","), key: u.Path}, nil
+	case strings.HasPrefix(name, fileScheme):
+		return &location{name: name, path: strings.TrimPrefix(name, fileScheme)}, nil
+	case name == "":
+		return nil, usageError("empty location")
+	default:
+		return &location{name: name, path: name}, nil
+	}
+}
+
+// isEtcd method checks if location is etcd key.
+func (loc *location) isEtcd() bool {
+	return loc.endpoints != nil
+}
+
+// encoder method retrieves encoder of provided type, or infers it from file name or etcd key.
+func (loc *location) encoder(encoderType string) (config.Marshaler, error) {
+	if encoderType != "" {
+		return encoder.ByType(encoderType)
+	}
+
+	name := loc.path
+	if loc.isEtcd() {
+		name = loc.key
+	}
+
+	if name == stdio {
+		return nil, usageError("encoder of standard input or output must be provided")
+	}
+
+	enc, err := encoder.ByFileName(name)
+	if err != nil {
+		return nil, usageError("%s, provide encoder type explicitly", err.Error())
+	}
+
+	return enc, nil
+}
+
+// read method reads raw data from the location. For etcd key its modification revision is retrieved too, or 0 if
+// there is no such key and missing key is allowed.
+func (loc *location) read(s *streams, allowMissing bool) ([]byte, int64, error) {
+	switch {
+	case loc.isEtcd():
+		data, revision, err := etcd.Load(loc.endpoints, loc.key)
+		if allowMissing && errors.Is(err, etcd.ErrNoData) {
+			return nil, 0, nil
+		}
+
+		if err != nil {
+			return nil, 0, errors.NewFmt("can't read '%s' (%s)", loc.name, err.Error()).WithCause(err)
+		}
+
+		return data, revision, nil
+	case loc.path == stdio:
+		data, err := io.ReadAll(s.stdin)
+		if err != nil {
+			return nil, 0, errors.NewFmt("can't read standard input (%s)", err.Error()).WithCause(err)
+		}
+
+		return data, 0, nil
+	default:
+		data, err := os.ReadFile(loc.path)
+		if err != nil {
+			return nil, 0, errors.NewFmt("can't read from file '%s' (%s)", loc.path, err.Error()).WithCause(err)
+		}
+
+		return data, 0, nil
+	}
+}
+
+// load method reads configuration data from the location and parses it with the encoder.
+func (loc *location) load(s *streams, enc config.Encoder) (map[string]interface{}, int64, error) {
+	dataBytes, revision, err := loc.read(s, false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var data map[string]interface{}
+
+	if err = enc.Encode(dataBytes, &data); err != nil {
+		return nil, 0, errors.NewFmt("can't parse '%s' (%s)", loc.name, err.Error()).WithCause(err)
+	}
+
+	return data, revision, nil
+}
+
+// write method writes raw data into the location. For etcd key it is compare-and-swap operation on provided revision,
+// see etcd.Store function, and new revision is retrieved.
+func (loc *location) write(s *streams, data []byte, revision int64) (int64, error) {
+	switch {
+	case loc.isEtcd():
+		newRevision, err := etcd.Store(loc.endpoints, loc.key, data, revision)
+		if err != nil {
+			return 0, errors.NewFmt("can't write '%s' (%s)", loc.name, err.Error()).WithCause(err)
+		}
+
+		return newRevision, nil
+	case loc.path == stdio:
+		if _, err := s.stdout.Write(data); err != nil {
+			return 0, errors.NewFmt("can't write standard output (%s)", err.Error()).WithCause(err)
+		}
+
+		return 0, nil
+	default:
+		perm := os.FileMode(filePerm)
+		if fileInfo, err := os.Stat(loc.path); err == nil {
+			perm = fileInfo.Mode().Perm()
+		}
+
+		if err := os.WriteFile(loc.path, data, perm); err != nil {
+			return 0, errors.NewFmt("can't write to file '%s' (%s)", loc.path, err.Error()).WithCause(err)
+		}
+
+		return 0, nil
+	}
+}
@@ -0,0 +1,162 @@
+// Command configctl manages configuration stored in files and etcd using config packages.
+//
+// Usage:
+//
+//	configctl get [-encoder type] [-output type] <location> [key]
+//	configctl set [-encoder type] [-string] <location> <key> <value>
+//	configctl push [-encoder type] [-revision n | -force] <file> <etcd location>
+//	configctl diff [-encoder type] <location> <location>
+//	configctl convert [-from type] [-to type] <location> [file]
+//	configctl validate -schema <file> [-encoder type] <location>
+//	configctl encrypt [-keys file] <value>
+//	configctl genkey <id>
+//
+// Location is a file name, '-' for standard input, or etcd key in form 'etcd://host1:2379,host2:2379/app/config'.
+// Encoder type is one of json, yaml, toml, dotenv, ini or properties. If it is not provided, it is inferred from the
+// extension of the file name or etcd key.
+//
+// Keys are composite keys like 'http.address' or 'servers.0.port', see config.ParseKey for the full grammar. Values
+// of 'set' command are JSON literals like '8080', 'true' or '["a","b"]', anything else is a string. Use '-string' to
+// always treat value as a string.
+//
+// Command 'encrypt' prints value encrypted with the primary key from environment variable CONFIG_KEYS or the file
+// named in CONFIG_KEYS_FILE or '-keys' flag, so it can be put into configuration, see config.NewWithDecryption. Value
+// '-' means standard input. Command 'genkey' prints new random key entry to add to the keys.
+//
+// Writes to etcd are compare-and-swap operations on the key revision, so concurrent modifications are not lost.
+// Note that files and etcd keys are rewritten from the decoded data, so comments and formatting are not preserved.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// errDifferent error is returned by commands that succeeded but found differences or violations, so the process
+// exits with non-zero code without printing the error.
+var errDifferent = errors.New("different")
+
+// errUsage error is returned when command arguments are wrong.
+var errUsage = errors.New("wrong usage")
+
+// command structure describes a subcommand.
+type command struct {
+	usage string
+	run   func(s *streams, args []string) error
+}
+
+// streams structure holds standard streams of the process.
+type streams struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+//nolint:gochecknoglobals // it's read-only table of subcommands.
+var commands = map[string]command{
+	"get": {
+		usage: "get [-encoder type] [-output type] <location> [key]",
+		run:   runGet,
+	},
+	"set": {
+		usage: "set [-encoder type] [-string] <location> <key> <value>",
+		run:   runSet,
+	},
+	"push": {
+		usage: "push [-encoder type] [-revision n | -force] <file> <etcd location>",
+		run:   runPush,
+	},
+	"diff": {
+		usage: "diff [-encoder type] <location> <location>",
+		run:   runDiff,
+	},
+	"convert": {
+		usage: "convert [-from type] [-to type] <location> [file]",
+		run:   runConvert,
+	},
+	"validate": {
+		usage: "validate -schema <file> [-encoder type] <location>",
+		run:   runValidate,
+	},
+	"encrypt": {
+		usage: "encrypt [-keys file] <value>",
+		run:   runEncrypt,
+	},
+	"genkey": {
+		usage: "genkey <id>",
+		run:   runGenKey,
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run function runs subcommand defined by arguments and retrieves process exit code.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	s := &streams{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		if args[0] != "help" && args[0] != "-h" && args[0] != "--help" {
+			fmt.Fprintf(stderr, "configctl: unknown command '%s'\n", args[0])
+		}
+
+		printUsage(stderr)
+
+		return exitUsage
+	}
+
+	err := cmd.run(s, args[1:])
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errDifferent):
+		return exitError
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(stderr, "configctl: %s\nusage: configctl %s\n", err.Error(), cmd.usage)
+		return exitUsage
+	default:
+		fmt.Fprintf(stderr, "configctl: %s\n", err.Error())
+		return exitError
+	}
+}
+
+// printUsage function prints usage of all subcommands.
+func printUsage(out io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, "  configctl "+commands[name].usage)
+	}
+
+	fmt.Fprintf(out, "usage:\n%s\n", strings.Join(lines, "\n"))
+}
+
+// usageError function creates error about wrong command arguments.
+func usageError(format string, args ...interface{}) error {
+	return errors.NewFmt(format, args...).WithCause(errUsage)
+}
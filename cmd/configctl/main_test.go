@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/internal/test/iotest"
+	"github.com/lightstar/golib/pkg/config"
+)
+
+const etcdCtlKey = "/sample_configctl.json"
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestGet(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	iotest.WriteFile(t, name, []byte("name: app\nhttp:\n  port: 8080\ntags: [a, b]\n"))
+
+	code, stdout, _ := runCommand("", "get", name, "name")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "app\n", stdout)
+
+	code, stdout, _ = runCommand("", "get", name, "http.port")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "8080\n", stdout)
+
+	code, stdout, _ = runCommand("", "get", name, "tags")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "[\"a\",\"b\"]\n", stdout)
+
+	code, stdout, _ = runCommand("", "get", "-output", "json", name, "http")
+	require.Equal(t, exitOK, code)
+	require.JSONEq(t, `{"port": 8080}`, stdout)
+
+	code, stdout, _ = runCommand(`{"name": "stdin"}`, "get", "-encoder", "json", "-", "name")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "stdin\n", stdout)
+
+	code, _, stderr := runCommand("", "get", name, "unknown")
+	require.Equal(t, exitError, code)
+	require.Contains(t, stderr, "can't get key 'unknown'")
+
+	code, _, stderr = runCommand("", "get", "-", "name")
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "usage: configctl get")
+}
+
+func TestSet(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.json")
+	iotest.WriteFile(t, name, []byte(`{"name": "app", "servers": [{"port": 80}]}`))
+
+	for _, args := range [][]string{
+		{"set", name, "servers.0.port", "81"},
+		{"set", name, "http.enabled", "true"},
+		{"set", name, "http.address", "0.0.0.0"},
+		{"set", "-string", name, "version", "2"},
+	} {
+		code, _, stderr := runCommand("", args...)
+		require.Equal(t, exitOK, code, stderr)
+	}
+
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"name": "app",
+		"version": "2",
+		"servers": [{"port": 81}],
+		"http": {"enabled": true, "address": "0.0.0.0"}
+	}`, string(data))
+
+	code, _, stderr := runCommand("", "set", name, "servers.1.port", "82")
+	require.Equal(t, exitError, code)
+	require.Contains(t, stderr, "can't set key 'servers.1.port'")
+
+	name = filepath.Join(t.TempDir(), "config.toml")
+	iotest.WriteFile(t, name, []byte("port = 80\nratio = 0.5\n"))
+
+	for _, args := range [][]string{
+		{"set", name, "port", "8080"},
+		{"set", name, "ratio", "1.5"},
+		{"set", name, "limits", `{"cpu": 2, "memory": 0.5}`},
+		{"set", name, "version", "1 2"},
+	} {
+		code, _, stderr = runCommand("", args...)
+		require.Equal(t, exitOK, code, stderr)
+	}
+
+	code, stdout, stderr := runCommand("", "get", "-output", "json", name)
+	require.Equal(t, exitOK, code, stderr)
+	require.JSONEq(t, `{"port": 8080, "ratio": 1.5, "limits": {"cpu": 2, "memory": 0.5}, "version": "1 2"}`, stdout)
+
+	data, err = os.ReadFile(name)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "port = 8080\n")
+	require.Contains(t, string(data), "cpu = 2\n")
+}
+
+func TestDiff(t *testing.T) {
+	path := t.TempDir()
+
+	iotest.WriteFile(t, filepath.Join(path, "old.yaml"), []byte(`
+name: app
+port: 80
+tags: [a, b]
+http:
+  timeout: 3
+  address: 127.0.0.1
+`))
+	iotest.WriteFile(t, filepath.Join(path, "new.json"), []byte(`{
+		"http": {"address": "0.0.0.0", "timeout": 3.0},
+		"name": "app",
+		"port": 80,
+		"tags": ["a"],
+		"debug": true
+	}`))
+	iotest.WriteFile(t, filepath.Join(path, "same.toml"), []byte(`
+name = "app"
+port = 80
+tags = ["a", "b"]
+
+[http]
+timeout = 3
+address = "127.0.0.1"
+`))
+
+	code, stdout, _ := runCommand("", "diff", filepath.Join(path, "old.yaml"), filepath.Join(path, "new.json"))
+	require.Equal(t, exitError, code)
+	require.Equal(t, `+ debug: true
+~ http.address: "127.0.0.1" -> "0.0.0.0"
+- tags.1: "b"
+`, stdout)
+
+	code, stdout, _ = runCommand("", "diff", filepath.Join(path, "old.yaml"), filepath.Join(path, "same.toml"))
+	require.Equal(t, exitOK, code)
+	require.Empty(t, stdout)
+}
+
+func TestConvert(t *testing.T) {
+	path := t.TempDir()
+
+	iotest.WriteFile(t, filepath.Join(path, "config.yaml"), []byte("name: app\nhttp:\n  port: 8080\n"))
+
+	code, _, stderr := runCommand("", "convert", filepath.Join(path, "config.yaml"), filepath.Join(path, "config.toml"))
+	require.Equal(t, exitOK, code, stderr)
+
+	code, stdout, stderr := runCommand("", "convert", "-to", "json", filepath.Join(path, "config.toml"))
+	require.Equal(t, exitOK, code, stderr)
+	require.JSONEq(t, `{"name": "app", "http": {"port": 8080}}`, stdout)
+
+	code, _, stderr = runCommand("", "convert", filepath.Join(path, "config.yaml"), filepath.Join(path, "config.txt"))
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "provide encoder type explicitly")
+
+	code, _, stderr = runCommand("", "convert", "-to", "xml", filepath.Join(path, "config.yaml"))
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "unknown encoder")
+}
+
+func TestValidate(t *testing.T) {
+	path := t.TempDir()
+
+	iotest.WriteFile(t, filepath.Join(path, "schema.json"), []byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"port": {"type": "integer", "minimum": 1}
+		},
+		"required": ["name"]
+	}`))
+	iotest.WriteFile(t, filepath.Join(path, "good.yaml"), []byte("name: app\nport: 80\n"))
+	iotest.WriteFile(t, filepath.Join(path, "bad.yaml"), []byte("port: 0\n"))
+
+	code, stdout, stderr := runCommand("", "validate", "-schema", filepath.Join(path, "schema.json"),
+		filepath.Join(path, "good.yaml"))
+	require.Equal(t, exitOK, code, stderr)
+	require.Empty(t, stdout)
+
+	code, stdout, _ = runCommand("", "validate", "-schema", filepath.Join(path, "schema.json"),
+		filepath.Join(path, "bad.yaml"))
+	require.Equal(t, exitError, code)
+	require.Len(t, strings.Split(strings.TrimSpace(stdout), "\n"), 2)
+	require.Contains(t, stdout, "name")
+	require.Contains(t, stdout, "port")
+
+	code, _, _ = runCommand("", "validate", filepath.Join(path, "good.yaml"))
+	require.Equal(t, exitUsage, code)
+}
+
+func TestUsage(t *testing.T) {
+	code, _, stderr := runCommand("")
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "configctl validate")
+
+	code, _, stderr = runCommand("", "unknown")
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "unknown command 'unknown'")
+
+	code, _, stderr = runCommand("", "push", "config.json", "config.yaml")
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "is not etcd location")
+}
+
+func TestPush(t *testing.T) {
+	endpoints := os.Getenv("TEST_CONFIG_ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Log("provide 'TEST_CONFIG_ETCD_ENDPOINTS' environment variable to test etcd source")
+		return
+	}
+
+	defer configtest.CleanEtcd(t, etcdCtlKey)
+
+	location := "etcd://" + endpoints + etcdCtlKey
+	name := filepath.Join(t.TempDir(), "config.json")
+	iotest.WriteFile(t, name, []byte(`{"name": "app"}`))
+
+	code, stdout, stderr := runCommand("", "push", name, location)
+	require.Equal(t, exitOK, code, stderr)
+	require.Contains(t, stdout, "revision")
+
+	code, _, stderr = runCommand("", "push", "-revision", "0", name, location)
+	require.Equal(t, exitError, code)
+	require.Contains(t, stderr, "revision")
+
+	code, _, stderr = runCommand("", "set", location, "name", "other")
+	require.Equal(t, exitOK, code, stderr)
+
+	code, stdout, _ = runCommand("", "get", location, "name")
+	require.Equal(t, exitOK, code)
+	require.Equal(t, "other\n", stdout)
+
+	code, _, _ = runCommand("", "diff", name, location)
+	require.Equal(t, exitError, code)
+
+	code, _, stderr = runCommand("", "push", "-force", name, location)
+	require.Equal(t, exitOK, code, stderr)
+
+	code, _, _ = runCommand("", "diff", name, location)
+	require.Equal(t, exitOK, code)
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Setenv("CONFIG_KEYS", "")
+	t.Setenv("CONFIG_KEYS_FILE", "")
+
+	code, _, stderr := runCommand("", "encrypt", "secret")
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "encryption keys must be provided")
+
+	code, entry, stderr := runCommand("", "genkey", "main")
+	require.Equal(t, exitOK, code, stderr)
+	require.True(t, strings.HasPrefix(entry, "main:"))
+
+	keysName := filepath.Join(t.TempDir(), "keys")
+	iotest.WriteFile(t, keysName, []byte(entry))
+
+	code, encrypted, stderr := runCommand("mongo-secret\n", "encrypt", "-keys", keysName, "-")
+	require.Equal(t, exitOK, code, stderr)
+	require.True(t, strings.HasPrefix(encrypted, "enc:v1:main:"))
+
+	keyring, err := config.ParseKeyring(entry)
+	require.NoError(t, err)
+
+	value, err := keyring.DecryptValue(strings.TrimSpace(encrypted))
+	require.NoError(t, err)
+	require.Equal(t, "mongo-secret", value)
+
+	t.Setenv("CONFIG_KEYS", entry)
+
+	code, encrypted, stderr = runCommand("", "encrypt", "redis-secret")
+	require.Equal(t, exitOK, code, stderr)
+
+	value, err = keyring.DecryptValue(strings.TrimSpace(encrypted))
+	require.NoError(t, err)
+	require.Equal(t, "redis-secret", value)
+
+	code, _, _ = runCommand("", "genkey", "a:b")
+	require.Equal(t, exitUsage, code)
+}
@@ -32,6 +32,7 @@ func (config *Config) Get(out interface{}) error {
 // GetByKey method fills structure that 'out' parameter points to with predefined data under the provided key.
 // It will return an error if 'out' is not a pointer or predefined data is nil or there is no such key in that data.
 // If corresponding key contains an error that error will be returned.
+// Predefined data must be of the type convertible to the output one, so structures that differ only in tags are fine.
 func (config *Config) GetByKey(key string, out interface{}) error {
 	if config.data == nil {
 		return ErrNoData
@@ -53,7 +54,7 @@ func (config *Config) GetByKey(key string, out interface{}) error {
 		return ErrOutputNotPointer
 	}
 
-	outValue.Elem().Set(reflect.ValueOf(value))
+	outValue.Elem().Set(reflect.ValueOf(value).Convert(outValue.Elem().Type()))
 
 	return nil
 }
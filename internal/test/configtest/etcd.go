@@ -40,6 +40,20 @@ func SetupEtcdWrong(t *testing.T, key string) {
 	require.NoError(t, err)
 }
 
+// PutEtcd function sets provided key in etcd to provided data.
+func PutEtcd(t *testing.T, key string, data []byte) {
+	t.Helper()
+
+	client := etcdClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := client.Do(ctx, clientv3.OpPut(key, string(data)))
+	require.NoError(t, err)
+}
+
 // CleanEtcd function clears provided key to restore etcd to its original state.
 func CleanEtcd(t *testing.T, key string) {
 	t.Helper()
@@ -54,6 +68,37 @@ func CleanEtcd(t *testing.T, key string) {
 	require.NoError(t, err)
 }
 
+// CleanEtcdPrefix function clears all keys under provided prefix to restore etcd to its original state.
+func CleanEtcdPrefix(t *testing.T, prefix string) {
+	t.Helper()
+
+	client := etcdClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := client.Do(ctx, clientv3.OpDelete(prefix, clientv3.WithPrefix()))
+	require.NoError(t, err)
+}
+
+// CompactEtcd function compacts etcd history up to the current revision, so watchers of older revisions are broken.
+func CompactEtcd(t *testing.T) {
+	t.Helper()
+
+	client := etcdClient(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	resp, err := client.Get(ctx, "/")
+	require.NoError(t, err)
+
+	_, err = client.Compact(ctx, resp.Header.Revision)
+	require.NoError(t, err)
+}
+
 func etcdClient(t *testing.T) *clientv3.Client {
 	t.Helper()
 
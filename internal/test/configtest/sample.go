@@ -85,6 +85,67 @@ weight = 12.2
 age = 12
 `)
 
+// SampleConfigDataDotenv variable contains sample configuration as raw .env data.
+//
+//nolint:gochecknoglobals // it's ok for sample test data to be global.
+var SampleConfigDataDotenv = []byte(`
+# Sample configuration
+NAME=Peter
+PROFILE__SEX='m'
+PROFILE__AGE=32
+export PROFILE__MARRIED=true
+
+PROFILE__CHILDREN__0__NAME="George"
+PROFILE__CHILDREN__0__WEIGHT=5.4
+PROFILE__CHILDREN__0__AGE=5 # years
+PROFILE__CHILDREN__1__NAME=Olivia
+PROFILE__CHILDREN__1__WEIGHT=12.2
+PROFILE__CHILDREN__1__AGE=12
+`)
+
+// SampleConfigDataINI variable contains sample configuration as raw INI data.
+//
+//nolint:gochecknoglobals // it's ok for sample test data to be global.
+var SampleConfigDataINI = []byte(`
+; Sample configuration
+name = Peter
+
+[profile]
+sex = "m"
+age = 32
+married: true
+
+[profile.children.0]
+name = 'George'
+weight = 5.4
+age = 5 ; years
+
+[profile.children.1]
+name = Olivia
+weight = 12.2
+age = 12
+`)
+
+// SampleConfigDataProperties variable contains sample configuration as raw properties data.
+//
+//nolint:gochecknoglobals // it's ok for sample test data to be global.
+var SampleConfigDataProperties = []byte(`
+# Sample configuration
+name=Peter
+profile.sex: m
+profile.age 32
+profile.married = true
+
+! Children
+profile.children.0.name=Geo\
+    rge
+profile.children.0.weight=5.4
+profile.children.0.age=5
+profile.children.1.name=\u004flivia
+profile.children.1.weight=12.2
+profile.children.1.age=12
+`)
+
 // SampleConfigDataWrongJSON variable contains sample wrong JSON data.
 //
 //nolint:gochecknoglobals // it's ok for sample test data to be global.
@@ -106,6 +167,27 @@ var SampleConfigDataWrongTOML = []byte(`
 name = Peter
 `)
 
+// SampleConfigDataWrongDotenv variable contains sample wrong .env data.
+//
+//nolint:gochecknoglobals // it's ok for sample test data to be global.
+var SampleConfigDataWrongDotenv = []byte(`
+NAME
+`)
+
+// SampleConfigDataWrongINI variable contains sample wrong INI data.
+//
+//nolint:gochecknoglobals // it's ok for sample test data to be global.
+var SampleConfigDataWrongINI = []byte(`
+[profile
+`)
+
+// SampleConfigDataWrongProperties variable contains sample wrong properties data.
+//
+//nolint:gochecknoglobals // it's ok for sample test data to be global.
+var SampleConfigDataWrongProperties = []byte(`
+name=\u12
+`)
+
 // ExpectedSampleConfig variable contains expected result of configuration parsing.
 //
 //nolint:gochecknoglobals,gomnd // it's ok for sample test data to be global and use raw numbers.
@@ -170,6 +252,23 @@ var ExpectedSampleRawDataTOML = map[string]interface{}{
 	},
 }
 
+// ExpectedSampleRawDataFlat variable contains expected raw result of configuration parsing by encoders of flat
+// formats: .env, INI and properties.
+//
+//nolint:gochecknoglobals,gomnd // it's ok for sample test data to be global and use raw numbers.
+var ExpectedSampleRawDataFlat = map[string]interface{}{
+	"name": "Peter",
+	"profile": map[string]interface{}{
+		"sex":     "m",
+		"age":     32,
+		"married": true,
+		"children": []interface{}{
+			map[string]interface{}{"name": "George", "weight": 5.4, "age": 5},
+			map[string]interface{}{"name": "Olivia", "weight": 12.2, "age": 12},
+		},
+	},
+}
+
 // TestSampleConfig function can be used to test provided configuration against expected raw data.
 //
 //nolint:forcetypeassert // we know what is inside expectedRawData structure. if not - test will fail anyway.
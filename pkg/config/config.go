@@ -15,8 +15,13 @@
 //	    panic(err)
 //	}
 //
+// Single values can be retrieved without declaring a structure:
+//
+//	maxIdle := config.MustValue(cfg, "redis.maxIdle", 10)
+//
 // Argument 'data' is raw configuration bytes in some format that provided encoder can parse.
-// Argument 'encoder' can be one of predefined ones - json.Encoder, yaml.Encoder, toml.Encoder, or some custom one.
+// Argument 'encoder' can be one of predefined ones - json.Encoder, yaml.Encoder, toml.Encoder, dotenv.Encoder,
+// ini.Encoder, properties.Encoder, or some custom one.
 //
 // Variable 'myStructure' is your custom structure designed to hold configuration data.
 // For example if JSON configuration data is '{"myKey":{"age":20, "name":"Peter"}}', then myStructure could be of type
@@ -24,12 +29,28 @@
 //
 // More likely you will use some of more specialized packages that work with specific sources of configuration data,
 // like file on disk or etcd service.
+//
+// Several configuration services can be layered with Merge function, where later layers override earlier ones:
+//
+//	cfg := config.Merge(baseCfg, envCfg, etcdCfg)
+//
+// Sections of named profiles under 'profiles' key can be merged on top of the base data with NewWithProfiles function.
+//
+// References like '${env:NAME}', '${file:/path}' or '${key:other.path}' inside string values can be resolved with
+// NewWithInterpolation function.
+//
+// Encrypted string values like 'enc:v1:<key id>:<data>' can be decrypted with NewWithDecryption function, so
+// configuration with credentials can be stored in version control. Use Keyring.Encrypt method to encrypt values.
+//
+// Configuration services created by these functions follow updates of the provided ones. Updates that can't be applied
+// are skipped and reported to the function set with WithErrorFunc method.
 package config
 
 import (
-	"strings"
+	"sync"
 
 	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/validate"
 	"github.com/lightstar/golib/pkg/errors"
 )
 
@@ -39,10 +60,24 @@ type Encoder interface {
 	Encode([]byte, *map[string]interface{}) error
 }
 
+// Marshaler is an encoder that can also convert structured representation of configuration back into bytes.
+// All predefined encoders implement it.
+type Marshaler interface {
+	Encoder
+	Marshal(map[string]interface{}) ([]byte, error)
+}
+
 // Config structure that provides configuration service. Don't create it manually, use the functions down below instead.
 type Config struct {
-	data map[string]interface{}
-	i2s  *i2s.Convertor
+	mu          sync.RWMutex
+	updateMu    sync.Mutex
+	data        map[string]interface{}
+	i2s         *i2s.Convertor
+	subscribers []*subscription
+	profiles    []string
+	errorFn     func(error)
+	derived     []*Config
+	detachFns   []func()
 }
 
 // NewFromBytes function creates new configuration service using source bytes and chosen encoder.
@@ -67,6 +102,20 @@ func NewFromRaw(data map[string]interface{}) *Config {
 	}
 }
 
+// NewFromStruct function creates new configuration service using data of structure or pointer to it, see
+// i2s.Convertor.ToMap method for details. It is handy to express defaults as a structure and merge configuration on top
+// of them:
+//
+//	cfg := config.Merge(config.Must(config.NewFromStruct(defaults)), userCfg)
+func NewFromStruct(in interface{}) (*Config, error) {
+	data, err := i2s.Instance().ToMap(in)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFromRaw(data), nil
+}
+
 // Must function panics on any error that can rise after creating configuration service.
 // Use this like that:
 //
@@ -79,59 +128,65 @@ func Must(config *Config, err error) *Config {
 	return config
 }
 
+// WithConvertor method replaces convertor used to fill structures with configuration data and returns the same
+// configuration service. Use it when you need non-default conversion behavior, such as collecting all errors at once:
+//
+//	cfg := config.Must(config.NewFromBytes(...)).WithConvertor(i2s.New(i2s.WithCollectErrors()))
+//
+// Or matching snake_case keys and skipping unknown ones:
+//
+//	cfg.WithConvertor(i2s.New(i2s.WithNameMapping(i2s.SnakeCaseMapping), i2s.WithIgnoreUnknownFields()))
+//
+// Configuration services derived from this one afterwards, such as with NewInner, Merge, NewWithProfiles or
+// NewWithEnvOverrides functions, use the same convertor.
+func (config *Config) WithConvertor(convertor *i2s.Convertor) *Config {
+	config.mu.Lock()
+	defer config.mu.Unlock()
+
+	config.i2s = convertor
+
+	return config
+}
+
 // GetRaw method retrieves raw representation of configuration data.
 // It should be used only internally or in very special cases.
 func (config *Config) GetRaw() map[string]interface{} {
+	config.mu.RLock()
+	defer config.mu.RUnlock()
+
 	return config.data
 }
 
 // GetRawByKey method retrieves raw representation of data that lies inside configuration under some key.
 // You can use empty key to retrieve all data or use a composite key like 'key1.key2.key3' to retrieve some deep data.
+// Numeric segments address slice elements, quoted segments can contain dots, and '*' segment matches all elements, in
+// which case all matching values are retrieved as a slice. See ParseKey function for the full key grammar.
 // It should be used only internally or in very special cases.
 func (config *Config) GetRawByKey(key string) (interface{}, error) {
-	if key == "" {
-		return config.data, nil
-	}
-
-	var value interface{}
-	var ok bool
-
-	valueElem := config.data
-	keySlice := strings.Split(key, ".")
-
-	for index, keyElem := range keySlice {
-		if keyElem == "" {
-			value = valueElem
-		} else if value, ok = valueElem[keyElem]; !ok {
-			return nil, ErrNoSuchKey
-		}
-
-		if index+1 < len(keySlice) {
-			if valueAsMap, ok := value.(map[string]interface{}); ok {
-				valueElem = valueAsMap
-			} else {
-				return nil, ErrNoSuchKey
-			}
-		}
-	}
-
-	return value, nil
+	return getByKey(config.GetRaw(), key)
 }
 
 // Get method fills structure that 'out' parameter points to with all configuration data.
 // It will return an error if that structure doesn't have some field, or it is not of an appropriate type.
+// Such errors are of i2s.FieldError type and contain full key path of the problematic data. They wrap errors of i2s
+// package like i2s.ErrMismatchedTypes, so check them with errors.Is instead of comparing directly.
+// After conversion the structure is validated according to its 'validate' tags and Validate method, see validate
+// package for details.
 func (config *Config) Get(out interface{}) error {
-	err := config.i2s.Convert(config.data, out)
+	convertor := config.convertor()
+
+	err := convertor.Convert(config.GetRaw(), out)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return validate.CheckWithMapping("", out, convertor.NameMapping())
 }
 
 // GetByKey method fills structure that 'out' parameter points to with configuration data lying under some key.
 // Rules are the same as for Get method.
 // You can use empty key to retrieve all data or use a composite key like 'key1.key2.key3' to retrieve some deep data.
+// Key grammar is the same as for GetRawByKey method, so 'servers.*.address' key can fill a slice of strings.
 func (config *Config) GetByKey(key string, out interface{}) error {
 	var data interface{}
 	var err error
@@ -141,12 +196,37 @@ func (config *Config) GetByKey(key string, out interface{}) error {
 		return err
 	}
 
-	err = config.i2s.Convert(data, out)
+	path := keyPath(key)
+	convertor := config.convertor()
+
+	err = convertor.ConvertWithPath(path, data, out)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return validate.CheckWithMapping(path, out, convertor.NameMapping())
+}
+
+// convertor method retrieves convertor used to fill structures with configuration data.
+func (config *Config) convertor() *i2s.Convertor {
+	config.mu.RLock()
+	defer config.mu.RUnlock()
+
+	return config.i2s
+}
+
+// inherit method makes this configuration service keep settings of the ones it is derived from: active profiles of
+// all of them and non-default convertor of the last one that has it. It returns the same configuration service.
+func (config *Config) inherit(cfgs ...*Config) *Config {
+	for _, cfg := range cfgs {
+		config.addProfiles(cfg.Profiles()...)
+
+		if convertor := cfg.convertor(); convertor != i2s.Instance() {
+			config.WithConvertor(convertor)
+		}
+	}
+
+	return config
 }
 
 // IsNoSuchKeyError checks if provided error is 'NoSuchKey' one.
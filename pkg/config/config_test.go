@@ -9,6 +9,7 @@ import (
 	"github.com/lightstar/golib/pkg/config"
 	"github.com/lightstar/golib/pkg/config/encoder/json"
 	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/validate"
 	"github.com/lightstar/golib/pkg/errors"
 )
 
@@ -19,6 +20,31 @@ func TestConfig(t *testing.T) {
 	configtest.TestSampleConfig(t, cfg, configtest.ExpectedSampleRawDataJSON)
 }
 
+func TestNewFromStruct(t *testing.T) {
+	cfg, err := config.NewFromStruct(configtest.ExpectedSampleConfig)
+	require.NoError(t, err)
+
+	var data configtest.SampleConfigType
+
+	require.NoError(t, cfg.Get(&data))
+	require.Equal(t, configtest.ExpectedSampleConfig, data)
+
+	defaults := configtest.SampleConfigType{Name: "Nobody", Profile: configtest.UserProfile{Sex: "f", Age: 18}}
+
+	cfg = config.Merge(config.Must(config.NewFromStruct(&defaults)), config.NewFromRaw(map[string]interface{}{
+		"profile": map[string]interface{}{"age": 32},
+	}))
+
+	var merged configtest.SampleConfigType
+
+	require.NoError(t, cfg.Get(&merged))
+	require.Equal(t, configtest.SampleConfigType{Name: "Nobody", Profile: configtest.UserProfile{Sex: "f", Age: 32}},
+		merged)
+
+	_, err = config.NewFromStruct("name")
+	require.ErrorIs(t, err, i2s.ErrMismatchedTypes)
+}
+
 func TestErrors(t *testing.T) {
 	cfg, err := config.NewFromBytes(configtest.SampleConfigDataJSON, json.Encoder)
 	require.NoError(t, err)
@@ -58,3 +84,102 @@ func TestNoSuchKey(t *testing.T) {
 	require.Equal(t, true, cfg.IsNoSuchKeyError(config.ErrNoSuchKey))
 	require.Equal(t, false, cfg.IsNoSuchKeyError(config.ErrNotMap))
 }
+
+func TestFieldErrors(t *testing.T) {
+	cfg, err := config.NewFromBytes(configtest.SampleConfigDataJSON, json.Encoder)
+	require.NoError(t, err)
+
+	var profile struct {
+		Sex      int
+		Age      int
+		Married  bool
+		Children []configtest.ChildProfile
+	}
+
+	var fieldErr *i2s.FieldError
+
+	err = cfg.GetByKey(".profile", &profile)
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, "profile.sex", fieldErr.Path)
+
+	err = cfg.WithConvertor(i2s.New(i2s.WithCollectErrors())).GetByKey("", &struct{ Name int }{})
+
+	var multiErr *i2s.MultiError
+
+	require.ErrorAs(t, err, &multiErr)
+	require.Len(t, multiErr.Errors, 2)
+	require.ErrorContains(t, err, "'name'")
+	require.ErrorContains(t, err, "'profile'")
+}
+
+func TestValidation(t *testing.T) {
+	cfg, err := config.NewFromBytes(configtest.SampleConfigDataJSON, json.Encoder)
+	require.NoError(t, err)
+
+	var profile struct {
+		Sex      string `validate:"oneof=f"`
+		Age      int    `validate:"max=30"`
+		Married  bool
+		Children []configtest.ChildProfile
+	}
+
+	err = cfg.GetByKey("profile", &profile)
+	require.ErrorIs(t, err, validate.ErrInvalidValue)
+	require.ErrorContains(t, err, "'profile.sex'")
+	require.ErrorContains(t, err, "'profile.age'")
+}
+
+func TestValidationNameMapping(t *testing.T) {
+	cfg := config.NewFromRaw(map[string]interface{}{
+		"http": map[string]interface{}{"max_conns": 100},
+	}).WithConvertor(i2s.New(i2s.WithNameMapping(i2s.SnakeCaseMapping)))
+
+	var http struct {
+		MaxConns int `validate:"max=10"`
+	}
+
+	err := cfg.GetByKey("http", &http)
+	require.ErrorIs(t, err, validate.ErrInvalidValue)
+	require.ErrorContains(t, err, "'http.max_conns'")
+}
+
+func TestConvertorInheritance(t *testing.T) {
+	base := config.NewFromRaw(map[string]interface{}{
+		"http":     map[string]interface{}{"max_conns": 10},
+		"profiles": map[string]interface{}{"prod": map[string]interface{}{"http": map[string]interface{}{"max_conns": 20}}},
+	}).WithConvertor(i2s.New(i2s.WithNameMapping(i2s.SnakeCaseMapping)))
+
+	var http struct {
+		MaxConns int
+	}
+
+	inner, err := config.NewInner("http", base)
+	require.NoError(t, err)
+	require.NoError(t, inner.Get(&http))
+	require.Equal(t, 10, http.MaxConns)
+
+	profiled, err := config.NewWithProfiles(base, "prod")
+	require.NoError(t, err)
+	require.NoError(t, profiled.GetByKey("http", &http))
+	require.Equal(t, 20, http.MaxConns)
+
+	t.Setenv("APP__HTTP__MAX_CONNS", "30")
+
+	overridden, err := config.NewWithEnvOverrides(profiled, "APP", "")
+	require.NoError(t, err)
+	require.NoError(t, overridden.GetByKey("http", &http))
+	require.Equal(t, 30, http.MaxConns)
+
+	merged := config.Merge(overridden, config.NewFromRaw(map[string]interface{}{
+		"http": map[string]interface{}{"max_conns": 40},
+	}))
+	require.NoError(t, merged.GetByKey("http", &http))
+	require.Equal(t, 40, http.MaxConns)
+
+	interpolated, err := config.NewWithInterpolation(merged, nil)
+	require.NoError(t, err)
+	require.NoError(t, interpolated.GetByKey("http", &http))
+	require.Equal(t, 40, http.MaxConns)
+
+	require.Error(t, config.NewFromRaw(merged.GetRaw()).GetByKey("http", &http))
+}
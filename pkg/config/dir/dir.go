@@ -0,0 +1,114 @@
+// Package dir is a wrapper around config package and allows reading configuration from all files in some directory
+// on disk, such as '/etc/app/conf.d', where each file holds some fragment of configuration.
+//
+// Typical usage:
+//
+//	cfg := config.Must(dir.NewConfig("/etc/app/conf.d", yaml.Encoder))
+//
+//	err = cfg.Get(&myStructure)
+//	if err != nil {
+//	    panic(err)
+//	}
+//
+// Files are loaded in lexical order of their names and deep-merged, so later files override earlier ones. Name files
+// like '10-base.yaml', '20-feature.yaml' to control the order. Include directives inside files are processed, see
+// file.Load function.
+//
+// See config package for more details.
+package dir
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder"
+	"github.com/lightstar/golib/pkg/config/file"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// NewConfig function creates new configuration service using all files in some directory that match chosen encoder,
+// see Extensions function. Subdirectories and hidden files are skipped.
+func NewConfig(path string, encoder config.Encoder) (*config.Config, error) {
+	return NewConfigWithPattern(path, "", encoder)
+}
+
+// NewConfigWithPattern function does the same as NewConfig, but uses files with names matching provided pattern, see
+// filepath.Match for its syntax. Empty pattern means files matching the encoder.
+func NewConfigWithPattern(path string, pattern string, encoder config.Encoder) (*config.Config, error) {
+	names, err := listFiles(path, pattern, encoder)
+	if err != nil {
+		return nil, err
+	}
+
+	layers := make([]map[string]interface{}, 0, len(names))
+
+	for _, name := range names {
+		data, err := file.Load(name, encoder)
+		if err != nil {
+			return nil, err
+		}
+
+		layers = append(layers, data)
+	}
+
+	return config.NewFromRaw(config.MergeRaw(config.SliceReplace, layers...)), nil
+}
+
+// Extensions function retrieves file extensions used by the encoder, see encoder.Extensions function.
+func Extensions(enc config.Encoder) []string {
+	return encoder.Extensions(enc.Type())
+}
+
+// listFiles function retrieves sorted names of files in the directory matching pattern or encoder.
+func listFiles(path string, pattern string, encoder config.Encoder) ([]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, errors.NewFmt("can't read directory '%s' (%s)", path, err.Error()).WithCause(err)
+	}
+
+	extensions := Extensions(encoder)
+	names := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		if pattern != "" {
+			matched, err := filepath.Match(pattern, name)
+			if err != nil {
+				return nil, errors.NewFmt("malformed pattern '%s' (%s)", pattern, err.Error()).WithCause(err)
+			}
+
+			if !matched {
+				continue
+			}
+		} else if !hasExtension(name, extensions) {
+			continue
+		}
+
+		names = append(names, filepath.Join(path, name))
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// hasExtension function checks if file name has one of the extensions.
+func hasExtension(name string, extensions []string) bool {
+	ext := filepath.Ext(name)
+
+	for _, extension := range extensions {
+		if strings.EqualFold(ext, extension) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,86 @@
+package dir_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/iotest"
+	"github.com/lightstar/golib/pkg/config/dir"
+	"github.com/lightstar/golib/pkg/config/encoder/json"
+	"github.com/lightstar/golib/pkg/config/encoder/yaml"
+)
+
+func TestDir(t *testing.T) {
+	path := t.TempDir()
+
+	iotest.WriteFile(t, filepath.Join(path, "10-base.yaml"), []byte(`
+name: app
+http:
+  address: 127.0.0.1:8080
+  timeout: 3
+tags: [a, b]
+`))
+	iotest.WriteFile(t, filepath.Join(path, "20-http.yml"), []byte(`
+http:
+  address: 0.0.0.0:80
+tags: [c]
+`))
+	iotest.WriteFile(t, filepath.Join(path, "30-redis.yaml"), []byte(`
+$include: ../shared/redis.yaml
+redis:
+  maxIdle: 5
+`))
+	iotest.WriteFile(t, filepath.Join(path, "../shared/redis.yaml"), []byte("redis:\n  address: localhost:6379\n"))
+	iotest.WriteFile(t, filepath.Join(path, "05-ignored.json"), []byte(`{"name": "json"}`))
+	iotest.WriteFile(t, filepath.Join(path, ".99-hidden.yaml"), []byte("name: hidden\n"))
+	iotest.WriteFile(t, filepath.Join(path, "sub/99-nested.yaml"), []byte("name: nested\n"))
+
+	cfg, err := dir.NewConfig(path, yaml.Encoder)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"name": "app",
+		"http": map[string]interface{}{
+			"address": "0.0.0.0:80",
+			"timeout": 3,
+		},
+		"tags": []interface{}{"c"},
+		"redis": map[string]interface{}{
+			"address": "localhost:6379",
+			"maxIdle": 5,
+		},
+	}, cfg.GetRaw())
+
+	cfg, err = dir.NewConfigWithPattern(path, "*-ignored.*", json.Encoder)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"name": "json"}, cfg.GetRaw())
+
+	cfg, err = dir.NewConfig(filepath.Join(path, "sub"), json.Encoder)
+	require.NoError(t, err)
+	require.Empty(t, cfg.GetRaw())
+}
+
+func TestDirErrors(t *testing.T) {
+	path := t.TempDir()
+
+	_, err := dir.NewConfig(filepath.Join(path, "unknown"), yaml.Encoder)
+	require.ErrorContains(t, err, "can't read directory")
+
+	_, err = dir.NewConfigWithPattern(path, "[", yaml.Encoder)
+	require.NoError(t, err)
+
+	iotest.WriteFile(t, filepath.Join(path, "wrong.yaml"), []byte("name: [\n"))
+
+	_, err = dir.NewConfigWithPattern(path, "[", yaml.Encoder)
+	require.ErrorContains(t, err, "malformed pattern")
+
+	_, err = dir.NewConfig(path, yaml.Encoder)
+	require.ErrorContains(t, err, "yaml error")
+}
+
+func TestExtensions(t *testing.T) {
+	require.Equal(t, []string{".yaml", ".yml"}, dir.Extensions(yaml.Encoder))
+	require.Equal(t, []string{".json"}, dir.Extensions(json.Encoder))
+}
@@ -0,0 +1,340 @@
+// Package dotenv provides .env encoder to use with config package. See its documentation for more details.
+//
+// Each line of .env data is 'KEY=VALUE', optionally prefixed with 'export'. Lines starting with '#' are comments.
+// Keys are split into segments by '__' or '.', and each segment in upper snake case is converted to camel case,
+// so 'REDIS__MAX_IDLE=5' becomes key 'redis.maxIdle'. Segments that are numbers turn maps into lists, so
+// 'SERVERS__0__ADDRESS' becomes the first element of 'servers' list.
+//
+// Unquoted values are type-inferred: int, float, bool or string, and can have inline comments after ' #'.
+// Values in single quotes are literal strings. Values in double quotes are strings that can span several lines and
+// contain escape sequences '\n', '\r', '\t', '\"', '\\' and '\$'.
+//
+// References '${NAME}' and '${NAME:-default}' inside unquoted and double-quoted values are expanded with values of
+// keys defined earlier in the same data or environment variables. References that are not in this form, such as
+// '${env:NAME}', are left intact, so they can be resolved by config.Interpolate function later.
+package dotenv
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/lightstar/golib/pkg/config/internal/flat"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+const (
+	// segmentSeparator is the separator of key segments.
+	segmentSeparator = "__"
+	// exportPrefix is the optional prefix of lines.
+	exportPrefix = "export "
+)
+
+//nolint:gochecknoglobals // these are actually read-only, so it's ok to use them.
+var (
+	keyRegexp       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+	referenceRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?}`)
+)
+
+type encoder struct{}
+
+// Encoder is a .env encoder to use with config package.
+//
+//nolint:gochecknoglobals // it's intended read-only exported pre-defined variable.
+var Encoder = encoder{}
+
+// Type method returns string type of the encoder.
+func (encoder) Type() string {
+	return "dotenv"
+}
+
+// Encode method treats configuration input data as .env file and parses it into provided map.
+func (encoder) Encode(in []byte, out *map[string]interface{}) error {
+	data := make(map[string]interface{})
+	defined := make(map[string]string)
+
+	lines := strings.Split(strings.ReplaceAll(string(in), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, exportPrefix))
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return syntaxError(lineNum, "expected 'KEY=VALUE'")
+		}
+
+		key = strings.TrimSpace(key)
+		if !keyRegexp.MatchString(key) {
+			return syntaxError(lineNum, "invalid key '"+key+"'")
+		}
+
+		value = strings.TrimSpace(value)
+
+		var parsed interface{}
+		var raw string
+		var err error
+
+		switch {
+		case strings.HasPrefix(value, "'"):
+			raw, err = parseSingleQuoted(value)
+			parsed = raw
+		case strings.HasPrefix(value, `"`):
+			for !hasClosingQuote(value) && i+1 < len(lines) {
+				i++
+				value += "\n" + lines[i]
+			}
+
+			raw, err = parseDoubleQuoted(value)
+			raw = expand(raw, defined)
+			parsed = raw
+		default:
+			if index := strings.Index(value, " #"); index >= 0 {
+				value = strings.TrimSpace(value[:index])
+			}
+
+			raw = expand(value, defined)
+			parsed = flat.Infer(raw)
+		}
+
+		if err != nil {
+			return syntaxError(lineNum, err.Error())
+		}
+
+		defined[key] = raw
+
+		if err = flat.Set(data, keyPath(key), parsed); err != nil {
+			return syntaxError(lineNum, err.Error())
+		}
+	}
+
+	flat.NormalizeMap(data)
+	*out = data
+
+	return nil
+}
+
+// Marshal method converts structured representation of configuration back into .env format. Strings are always
+// double-quoted, so they are not type-inferred when parsed back.
+func (encoder) Marshal(in map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for _, entry := range flat.Flatten(in) {
+		segments := make([]string, 0, len(entry.Path))
+		for _, segment := range entry.Path {
+			segments = append(segments, toUpperSnake(segment))
+		}
+
+		buf.WriteString(strings.Join(segments, segmentSeparator))
+		buf.WriteString("=")
+
+		if str, ok := entry.Value.(string); ok {
+			buf.WriteString(quote(str))
+		} else {
+			buf.WriteString(flat.Format(entry.Value))
+		}
+
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// keyPath function splits key into segments converting them to camel case.
+func keyPath(key string) []string {
+	var path []string
+
+	for _, part := range strings.Split(key, segmentSeparator) {
+		for _, segment := range strings.Split(part, ".") {
+			path = append(path, toCamel(segment))
+		}
+	}
+
+	return path
+}
+
+// toCamel function converts segment in upper snake case into camel case. Segments that have lower case letters
+// are left intact.
+func toCamel(segment string) string {
+	if strings.IndexFunc(segment, unicode.IsLower) >= 0 {
+		return segment
+	}
+
+	var builder strings.Builder
+
+	upperNext := false
+
+	for _, r := range strings.ToLower(segment) {
+		switch {
+		case r == '_':
+			upperNext = builder.Len() > 0
+		case upperNext:
+			builder.WriteRune(unicode.ToUpper(r))
+			upperNext = false
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+// toUpperSnake function converts segment in camel case into upper snake case.
+func toUpperSnake(segment string) string {
+	var builder strings.Builder
+
+	for i, r := range segment {
+		if i > 0 && unicode.IsUpper(r) {
+			builder.WriteRune('_')
+		}
+
+		builder.WriteRune(unicode.ToUpper(r))
+	}
+
+	return builder.String()
+}
+
+// parseSingleQuoted function retrieves literal string inside single quotes.
+func parseSingleQuoted(value string) (string, error) {
+	end := strings.IndexByte(value[1:], '\'')
+	if end < 0 {
+		return "", errors.New("unclosed single quote")
+	}
+
+	if err := checkRest(value[end+2:]); err != nil {
+		return "", err
+	}
+
+	return value[1 : end+1], nil
+}
+
+// parseDoubleQuoted function retrieves string inside double quotes processing escape sequences.
+func parseDoubleQuoted(value string) (string, error) {
+	var builder strings.Builder
+
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '"':
+			if err := checkRest(value[i+1:]); err != nil {
+				return "", err
+			}
+
+			return builder.String(), nil
+		case '\\':
+			if i+1 == len(value) {
+				return "", errors.New("unclosed double quote")
+			}
+
+			i++
+
+			switch value[i] {
+			case 'n':
+				builder.WriteByte('\n')
+			case 'r':
+				builder.WriteByte('\r')
+			case 't':
+				builder.WriteByte('\t')
+			case '$':
+				// Escaped '$' is kept escaped until references are expanded.
+				builder.WriteString(`\$`)
+			default:
+				builder.WriteByte(value[i])
+			}
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+
+	return "", errors.New("unclosed double quote")
+}
+
+// hasClosingQuote function checks if value starting with double quote has closing one.
+func hasClosingQuote(value string) bool {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkRest function checks that there is nothing but comment after the closing quote.
+func checkRest(rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return errors.NewFmt("unexpected '%s' after closing quote", rest)
+	}
+
+	return nil
+}
+
+// expand function expands references to keys defined earlier or environment variables, and unescapes '\$'.
+func expand(value string, defined map[string]string) string {
+	if !strings.Contains(value, "$") {
+		return value
+	}
+
+	parts := strings.Split(value, `\$`)
+
+	for i, part := range parts {
+		parts[i] = referenceRegexp.ReplaceAllStringFunc(part, func(reference string) string {
+			match := referenceRegexp.FindStringSubmatch(reference)
+
+			if definedValue, ok := defined[match[1]]; ok {
+				return definedValue
+			}
+
+			if envValue, ok := os.LookupEnv(match[1]); ok {
+				return envValue
+			}
+
+			return strings.TrimPrefix(match[2], ":-")
+		})
+	}
+
+	return strings.Join(parts, "$")
+}
+
+// quote function puts string into double quotes escaping special characters.
+func quote(value string) string {
+	var builder strings.Builder
+
+	builder.WriteByte('"')
+
+	for _, r := range value {
+		switch r {
+		case '"', '\\', '$':
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	builder.WriteByte('"')
+
+	return builder.String()
+}
+
+// syntaxError function creates error about malformed line.
+func syntaxError(lineNum int, msg string) error {
+	return errors.NewFmt("dotenv error (line %d: %s)", lineNum, msg)
+}
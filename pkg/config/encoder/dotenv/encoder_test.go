@@ -0,0 +1,96 @@
+package dotenv_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config/encoder/dotenv"
+)
+
+func TestDotenvEncoder(t *testing.T) {
+	var result map[string]interface{}
+
+	err := dotenv.Encoder.Encode(configtest.SampleConfigDataDotenv, &result)
+	require.NoError(t, err)
+
+	require.Equal(t, configtest.ExpectedSampleRawDataFlat, result)
+}
+
+func TestDotenvEncoderError(t *testing.T) {
+	var result map[string]interface{}
+
+	err := dotenv.Encoder.Encode(configtest.SampleConfigDataWrongDotenv, &result)
+	require.ErrorContains(t, err, "dotenv error (line 2:")
+}
+
+func TestDotenvEncoderMarshal(t *testing.T) {
+	out, err := dotenv.Encoder.Marshal(configtest.ExpectedSampleRawDataFlat)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+
+	err = dotenv.Encoder.Encode(out, &result)
+	require.NoError(t, err)
+
+	require.Equal(t, configtest.ExpectedSampleRawDataFlat, result)
+}
+
+func TestDotenvEncoderValues(t *testing.T) {
+	t.Setenv("TEST_DOTENV_HOST", "db.local")
+
+	var result map[string]interface{}
+
+	err := dotenv.Encoder.Encode([]byte(`
+DB_PORT=5432
+DB__URL="postgres://${TEST_DOTENV_HOST}:${DB_PORT}/${DB_NAME:-app}"
+DB__PASSWORD='${NOT_EXPANDED}'
+DB__SECRET=${env:DB_SECRET}
+DB__PRICE="\$5"
+DB__NOTE="first
+second\tthird"
+DB__CODE="007"
+http.maxIdle=3
+`), &result)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"dbPort": 5432,
+		"db": map[string]interface{}{
+			"url":      "postgres://db.local:5432/app",
+			"password": "${NOT_EXPANDED}",
+			"secret":   "${env:DB_SECRET}",
+			"price":    "$5",
+			"note":     "first\nsecond\tthird",
+			"code":     "007",
+		},
+		"http": map[string]interface{}{"maxIdle": 3},
+	}, result)
+
+	out, err := dotenv.Encoder.Marshal(result)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "HTTP__MAX_IDLE=3\n")
+	require.Contains(t, string(out), `DB__PRICE="\$5"`)
+
+	var marshaled map[string]interface{}
+
+	require.NoError(t, dotenv.Encoder.Encode(out, &marshaled))
+	require.Equal(t, result, marshaled)
+}
+
+func TestDotenvEncoderErrors(t *testing.T) {
+	for _, data := range []string{
+		"1KEY=value",
+		`KEY="unclosed`,
+		"KEY='unclosed",
+		`KEY="value" rest`,
+		"KEY=1\nKEY__INNER=2",
+		"KEY__INNER=1\nKEY=2",
+	} {
+		var result map[string]interface{}
+
+		err := dotenv.Encoder.Encode([]byte(data), &result)
+		require.ErrorContains(t, err, "dotenv error", data)
+	}
+}
@@ -0,0 +1,96 @@
+// Package encoder provides lookup of predefined encoders by their type or by file name extension, so encoders can be
+// chosen at runtime, for example from environment variables or command-line arguments.
+//
+// Typical usage:
+//
+//	enc, err := encoder.ByFileName("configs/config.yaml")
+//	if err != nil {
+//	    panic(err)
+//	}
+//
+//	cfg := config.Must(file.NewConfig("configs/config.yaml", enc))
+package encoder
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder/dotenv"
+	"github.com/lightstar/golib/pkg/config/encoder/ini"
+	"github.com/lightstar/golib/pkg/config/encoder/json"
+	"github.com/lightstar/golib/pkg/config/encoder/properties"
+	"github.com/lightstar/golib/pkg/config/encoder/toml"
+	"github.com/lightstar/golib/pkg/config/encoder/yaml"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// ErrUnknownEncoder error is returned when there is no predefined encoder of requested type or for requested file.
+// It is wrapped with the details of the request, so check for it with errors.Is rather than comparing directly.
+var ErrUnknownEncoder = errors.New("unknown encoder")
+
+// entry structure describes predefined encoder and file name extensions it is used for.
+type entry struct {
+	encoder    config.Marshaler
+	extensions []string
+}
+
+//nolint:gochecknoglobals // it's read-only table of predefined encoders.
+var entries = []entry{
+	{encoder: json.Encoder, extensions: []string{".json"}},
+	{encoder: yaml.Encoder, extensions: []string{".yaml", ".yml"}},
+	{encoder: toml.Encoder, extensions: []string{".toml"}},
+	{encoder: dotenv.Encoder, extensions: []string{".env"}},
+	{encoder: ini.Encoder, extensions: []string{".ini", ".conf"}},
+	{encoder: properties.Encoder, extensions: []string{".properties"}},
+}
+
+// Types function retrieves types of all predefined encoders: json, yaml, toml, dotenv, ini and properties.
+func Types() []string {
+	types := make([]string, 0, len(entries))
+
+	for _, e := range entries {
+		types = append(types, e.encoder.Type())
+	}
+
+	return types
+}
+
+// ByType function retrieves predefined encoder of provided type, such as 'yaml'.
+func ByType(encoderType string) (config.Marshaler, error) {
+	for _, e := range entries {
+		if e.encoder.Type() == encoderType {
+			return e.encoder, nil
+		}
+	}
+
+	return nil, errors.NewFmt("unknown encoder '%s'", encoderType).WithCause(ErrUnknownEncoder)
+}
+
+// ByFileName function retrieves predefined encoder by extension of provided file name, such as '.yml'.
+// Extensions are matched case-insensitively.
+func ByFileName(name string) (config.Marshaler, error) {
+	ext := filepath.Ext(name)
+
+	for _, e := range entries {
+		for _, extension := range e.extensions {
+			if strings.EqualFold(ext, extension) {
+				return e.encoder, nil
+			}
+		}
+	}
+
+	return nil, errors.NewFmt("unknown encoder for file '%s'", name).WithCause(ErrUnknownEncoder)
+}
+
+// Extensions function retrieves file name extensions used by encoder of provided type. Encoders that are not
+// predefined use their type as extension.
+func Extensions(encoderType string) []string {
+	for _, e := range entries {
+		if e.encoder.Type() == encoderType {
+			return append([]string(nil), e.extensions...)
+		}
+	}
+
+	return []string{"." + encoderType}
+}
@@ -0,0 +1,45 @@
+package encoder_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config/encoder"
+	"github.com/lightstar/golib/pkg/config/encoder/ini"
+	"github.com/lightstar/golib/pkg/config/encoder/toml"
+	"github.com/lightstar/golib/pkg/config/encoder/yaml"
+)
+
+func TestByType(t *testing.T) {
+	for _, encoderType := range encoder.Types() {
+		enc, err := encoder.ByType(encoderType)
+		require.NoError(t, err)
+		require.Equal(t, encoderType, enc.Type())
+	}
+
+	_, err := encoder.ByType("xml")
+	require.ErrorIs(t, err, encoder.ErrUnknownEncoder)
+}
+
+func TestByFileName(t *testing.T) {
+	enc, err := encoder.ByFileName("configs/config.YML")
+	require.NoError(t, err)
+	require.Equal(t, yaml.Encoder, enc)
+
+	enc, err = encoder.ByFileName("/etc/app/config.toml")
+	require.NoError(t, err)
+	require.Equal(t, toml.Encoder, enc)
+
+	enc, err = encoder.ByFileName("app.conf")
+	require.NoError(t, err)
+	require.Equal(t, ini.Encoder, enc)
+
+	_, err = encoder.ByFileName("config")
+	require.ErrorIs(t, err, encoder.ErrUnknownEncoder)
+}
+
+func TestExtensions(t *testing.T) {
+	require.Equal(t, []string{".yaml", ".yml"}, encoder.Extensions("yaml"))
+	require.Equal(t, []string{".xml"}, encoder.Extensions("xml"))
+}
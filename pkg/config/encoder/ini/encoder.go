@@ -0,0 +1,181 @@
+// Package ini provides INI encoder to use with config package. See its documentation for more details.
+//
+// Each line of INI data is a section header '[section]', a key-value pair 'key = value' or 'key: value', or a comment
+// starting with ';' or '#'. Keys before the first section are top-level ones. Section names and keys are split into
+// segments by '.', so 'maxIdle' key in '[storage.redis]' section becomes key 'storage.redis.maxIdle'. Segments that
+// are numbers turn maps into lists, so section '[servers.0]' becomes the first element of 'servers' list.
+//
+// Unquoted values are type-inferred: int, float, bool or string, and can have inline comments after ' ;' or ' #'.
+// Values in double quotes are strings with Go escape sequences, values in single quotes are literal strings.
+package ini
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config/internal/flat"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+type encoder struct{}
+
+// Encoder is an INI encoder to use with config package.
+//
+//nolint:gochecknoglobals // it's intended read-only exported pre-defined variable.
+var Encoder = encoder{}
+
+// Type method returns string type of the encoder.
+func (encoder) Type() string {
+	return "ini"
+}
+
+// Encode method treats configuration input data as INI file and parses it into provided map.
+func (encoder) Encode(in []byte, out *map[string]interface{}) error {
+	data := make(map[string]interface{})
+	section := data
+
+	for i, line := range strings.Split(strings.ReplaceAll(string(in), "\r\n", "\n"), "\n") {
+		lineNum := i + 1
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return syntaxError(lineNum, "unclosed section header")
+			}
+
+			var err error
+
+			section, err = flat.Section(data, strings.Split(strings.TrimSpace(line[1:len(line)-1]), "."))
+			if err != nil {
+				return syntaxError(lineNum, err.Error())
+			}
+
+			continue
+		}
+
+		index := strings.IndexAny(line, "=:")
+		if index < 0 {
+			return syntaxError(lineNum, "expected 'key = value'")
+		}
+
+		key := strings.TrimSpace(line[:index])
+
+		value, err := parseValue(strings.TrimSpace(line[index+1:]))
+		if err != nil {
+			return syntaxError(lineNum, err.Error())
+		}
+
+		if err = flat.Set(section, strings.Split(key, "."), value); err != nil {
+			return syntaxError(lineNum, err.Error())
+		}
+	}
+
+	flat.NormalizeMap(data)
+	*out = data
+
+	return nil
+}
+
+// Marshal method converts structured representation of configuration back into INI format. Top-level scalars are
+// written first, then sections for all nested maps. Strings are always double-quoted, so they are not type-inferred
+// when parsed back.
+func (encoder) Marshal(in map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	sections := make(map[string][]flat.Entry)
+
+	for _, entry := range flat.Flatten(in) {
+		last := len(entry.Path) - 1
+		name := strings.Join(entry.Path[:last], ".")
+
+		sections[name] = append(sections[name], flat.Entry{Path: entry.Path[last:], Value: entry.Value})
+	}
+
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name != "" {
+			if buf.Len() > 0 {
+				buf.WriteString("\n")
+			}
+
+			buf.WriteString("[" + name + "]\n")
+		}
+
+		for _, entry := range sections[name] {
+			buf.WriteString(entry.Path[0] + " = ")
+
+			if str, ok := entry.Value.(string); ok {
+				buf.WriteString(strconv.Quote(str))
+			} else {
+				buf.WriteString(flat.Format(entry.Value))
+			}
+
+			buf.WriteString("\n")
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// parseValue function parses quoted or unquoted value.
+func parseValue(value string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		prefix, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return nil, errors.New("malformed double-quoted value")
+		}
+
+		if err = checkRest(value[len(prefix):]); err != nil {
+			return nil, err
+		}
+
+		return strconv.Unquote(prefix)
+	case strings.HasPrefix(value, "'"):
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return nil, errors.New("unclosed single quote")
+		}
+
+		if err := checkRest(value[end+2:]); err != nil {
+			return nil, err
+		}
+
+		return value[1 : end+1], nil
+	default:
+		for _, comment := range []string{" ;", " #"} {
+			if index := strings.Index(value, comment); index >= 0 {
+				value = strings.TrimSpace(value[:index])
+			}
+		}
+
+		return flat.Infer(value), nil
+	}
+}
+
+// checkRest function checks that there is nothing but comment after the closing quote.
+func checkRest(rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest != "" && !strings.HasPrefix(rest, ";") && !strings.HasPrefix(rest, "#") {
+		return errors.NewFmt("unexpected '%s' after closing quote", rest)
+	}
+
+	return nil
+}
+
+// syntaxError function creates error about malformed line.
+func syntaxError(lineNum int, msg string) error {
+	return errors.NewFmt("ini error (line %d: %s)", lineNum, msg)
+}
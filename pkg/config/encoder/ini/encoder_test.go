@@ -0,0 +1,96 @@
+package ini_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config/encoder/ini"
+)
+
+func TestINIEncoder(t *testing.T) {
+	var result map[string]interface{}
+
+	err := ini.Encoder.Encode(configtest.SampleConfigDataINI, &result)
+	require.NoError(t, err)
+
+	require.Equal(t, configtest.ExpectedSampleRawDataFlat, result)
+}
+
+func TestINIEncoderError(t *testing.T) {
+	var result map[string]interface{}
+
+	err := ini.Encoder.Encode(configtest.SampleConfigDataWrongINI, &result)
+	require.ErrorContains(t, err, "ini error (line 2:")
+}
+
+func TestINIEncoderMarshal(t *testing.T) {
+	out, err := ini.Encoder.Marshal(configtest.ExpectedSampleRawDataFlat)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+
+	err = ini.Encoder.Encode(out, &result)
+	require.NoError(t, err)
+
+	require.Equal(t, configtest.ExpectedSampleRawDataFlat, result)
+}
+
+func TestINIEncoderValues(t *testing.T) {
+	var result map[string]interface{}
+
+	err := ini.Encoder.Encode([]byte(`
+debug = false
+[storage.redis]
+address = "127.0.0.1:6379" ; local
+password = 'p;a#ss'
+maxIdle = 3
+url = http://example.com/#anchor
+[storage]
+mongo.address = "127.0.0.1:27017"
+empty =
+`), &result)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"debug": false,
+		"storage": map[string]interface{}{
+			"redis": map[string]interface{}{
+				"address":  "127.0.0.1:6379",
+				"password": "p;a#ss",
+				"maxIdle":  3,
+				"url":      "http://example.com/#anchor",
+			},
+			"mongo": map[string]interface{}{"address": "127.0.0.1:27017"},
+			"empty": "",
+		},
+	}, result)
+
+	out, err := ini.Encoder.Marshal(result)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "debug = false\n\n[storage]\n")
+	require.Contains(t, string(out), "[storage.redis]\naddress = \"127.0.0.1:6379\"\n")
+
+	var marshaled map[string]interface{}
+
+	require.NoError(t, ini.Encoder.Encode(out, &marshaled))
+	require.Equal(t, result, marshaled)
+}
+
+func TestINIEncoderErrors(t *testing.T) {
+	for _, data := range []string{
+		"key",
+		`key = "unclosed`,
+		"key = 'unclosed",
+		`key = "value" rest`,
+		"key = 1\n[key]",
+		"[a..b]",
+		"= value",
+	} {
+		var result map[string]interface{}
+
+		err := ini.Encoder.Encode([]byte(data), &result)
+		require.ErrorContains(t, err, "ini error", data)
+	}
+}
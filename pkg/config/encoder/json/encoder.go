@@ -28,3 +28,13 @@ func (encoder) Encode(in []byte, out *map[string]interface{}) error {
 
 	return nil
 }
+
+// Marshal method converts structured representation of configuration back into indented JSON.
+func (encoder) Marshal(in map[string]interface{}) ([]byte, error) {
+	out, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return nil, errors.NewFmt("json error (%s)", err.Error()).WithCause(err)
+	}
+
+	return out, nil
+}
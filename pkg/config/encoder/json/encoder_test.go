@@ -24,3 +24,15 @@ func TestJSONEncoderError(t *testing.T) {
 	err := json.Encoder.Encode(configtest.SampleConfigDataWrongJSON, &result)
 	require.ErrorContains(t, err, "json error")
 }
+
+func TestJSONEncoderMarshal(t *testing.T) {
+	out, err := json.Encoder.Marshal(configtest.ExpectedSampleRawDataJSON)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+
+	err = json.Encoder.Encode(out, &result)
+	require.NoError(t, err)
+
+	require.Equal(t, configtest.ExpectedSampleRawDataJSON, result)
+}
@@ -0,0 +1,214 @@
+// Package properties provides Java-style properties encoder to use with config package. See its documentation for
+// more details.
+//
+// Each logical line of properties data is a key-value pair 'key=value', 'key: value' or 'key value', or a comment
+// starting with '#' or '!'. Lines ending with backslash are continued on the next line. Keys and values can contain
+// escape sequences '\t', '\n', '\r', '\f', '\uXXXX', and any other escaped character stands for itself.
+//
+// Keys are split into segments by '.', so 'storage.redis.maxIdle' key becomes nested one. Segments that are numbers
+// turn maps into lists, so 'servers.0.address' becomes 'address' of the first element of 'servers' list.
+// Values are type-inferred: int, float, bool or string.
+package properties
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config/internal/flat"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+type encoder struct{}
+
+// Encoder is a Java-style properties encoder to use with config package.
+//
+//nolint:gochecknoglobals // it's intended read-only exported pre-defined variable.
+var Encoder = encoder{}
+
+// Type method returns string type of the encoder.
+func (encoder) Type() string {
+	return "properties"
+}
+
+// Encode method treats configuration input data as properties file and parses it into provided map.
+func (encoder) Encode(in []byte, out *map[string]interface{}) error {
+	data := make(map[string]interface{})
+	lines := strings.Split(strings.ReplaceAll(string(in), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		for isContinued(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+
+		key, value, err := splitLine(line)
+		if err != nil {
+			return syntaxError(lineNum, err.Error())
+		}
+
+		if err = flat.Set(data, strings.Split(key, "."), flat.Infer(value)); err != nil {
+			return syntaxError(lineNum, err.Error())
+		}
+	}
+
+	flat.NormalizeMap(data)
+	*out = data
+
+	return nil
+}
+
+// Marshal method converts structured representation of configuration back into properties format.
+func (encoder) Marshal(in map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for _, entry := range flat.Flatten(in) {
+		segments := make([]string, 0, len(entry.Path))
+		for _, segment := range entry.Path {
+			segments = append(segments, escape(segment, true))
+		}
+
+		buf.WriteString(strings.Join(segments, "."))
+		buf.WriteString("=")
+		buf.WriteString(escape(flat.Format(entry.Value), false))
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// isContinued function checks if line ends with odd number of backslashes, so it is continued on the next line.
+func isContinued(line string) bool {
+	count := 0
+
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+
+	return count%2 == 1
+}
+
+// splitLine function splits logical line into unescaped key and value.
+func splitLine(line string) (string, string, error) {
+	end := len(line)
+
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+
+	key, err := unescape(line[:end])
+	if err != nil {
+		return "", "", err
+	}
+
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	value, err := unescape(rest)
+	if err != nil {
+		return "", "", err
+	}
+
+	return key, value, nil
+}
+
+// unescape function processes escape sequences.
+func unescape(value string) (string, error) {
+	if !strings.Contains(value, `\`) {
+		return value, nil
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			builder.WriteByte(value[i])
+			continue
+		}
+
+		i++
+
+		if i == len(value) {
+			break
+		}
+
+		switch value[i] {
+		case 't':
+			builder.WriteByte('\t')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			if i+5 > len(value) {
+				return "", errors.New("malformed \\u escape sequence")
+			}
+
+			code, err := strconv.ParseUint(value[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", errors.New("malformed \\u escape sequence")
+			}
+
+			builder.WriteRune(rune(code))
+
+			i += 4
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// escape function escapes special characters of key segment or value.
+func escape(value string, isKey bool) string {
+	var builder strings.Builder
+
+	for i, r := range value {
+		switch {
+		case r == '\\':
+			builder.WriteString(`\\`)
+		case r == '\t':
+			builder.WriteString(`\t`)
+		case r == '\n':
+			builder.WriteString(`\n`)
+		case r == '\r':
+			builder.WriteString(`\r`)
+		case r == '\f':
+			builder.WriteString(`\f`)
+		case r == ' ' && (isKey || i == 0):
+			builder.WriteString(`\ `)
+		case strings.ContainsRune("=:", r) && isKey, strings.ContainsRune("#!", r) && i == 0:
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+// syntaxError function creates error about malformed line.
+func syntaxError(lineNum int, msg string) error {
+	return errors.NewFmt("properties error (line %d: %s)", lineNum, msg)
+}
@@ -0,0 +1,85 @@
+package properties_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config/encoder/properties"
+)
+
+func TestPropertiesEncoder(t *testing.T) {
+	var result map[string]interface{}
+
+	err := properties.Encoder.Encode(configtest.SampleConfigDataProperties, &result)
+	require.NoError(t, err)
+
+	require.Equal(t, configtest.ExpectedSampleRawDataFlat, result)
+}
+
+func TestPropertiesEncoderError(t *testing.T) {
+	var result map[string]interface{}
+
+	err := properties.Encoder.Encode(configtest.SampleConfigDataWrongProperties, &result)
+	require.ErrorContains(t, err, "properties error (line 2:")
+}
+
+func TestPropertiesEncoderMarshal(t *testing.T) {
+	out, err := properties.Encoder.Marshal(configtest.ExpectedSampleRawDataFlat)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+
+	err = properties.Encoder.Encode(out, &result)
+	require.NoError(t, err)
+
+	require.Equal(t, configtest.ExpectedSampleRawDataFlat, result)
+}
+
+func TestPropertiesEncoderValues(t *testing.T) {
+	var result map[string]interface{}
+
+	err := properties.Encoder.Encode([]byte(`
+http.address=127.0.0.1\:8080
+http.path\ name = /api/v1
+http.message = Hello,\n\tworld\\
+http.comment = #not a comment
+servers.0 = alpha
+servers.1 = beta
+servers.3 = delta
+`), &result)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"http": map[string]interface{}{
+			"address":   "127.0.0.1:8080",
+			"path name": "/api/v1",
+			"message":   "Hello,\n\tworld\\",
+			"comment":   "#not a comment",
+		},
+		"servers": map[string]interface{}{"0": "alpha", "1": "beta", "3": "delta"},
+	}, result)
+
+	out, err := properties.Encoder.Marshal(result)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "http.path\\ name=/api/v1\n")
+
+	var marshaled map[string]interface{}
+
+	require.NoError(t, properties.Encoder.Encode(out, &marshaled))
+	require.Equal(t, result, marshaled)
+}
+
+func TestPropertiesEncoderErrors(t *testing.T) {
+	for _, data := range []string{
+		`key=\uXYZW`,
+		"key=1\nkey.inner=2",
+		"a..b=1",
+	} {
+		var result map[string]interface{}
+
+		err := properties.Encoder.Encode([]byte(data), &result)
+		require.ErrorContains(t, err, "properties error", data)
+	}
+}
@@ -28,3 +28,18 @@ func (encoder) Encode(in []byte, out *map[string]interface{}) error {
 
 	return nil
 }
+
+// Marshal method converts structured representation of configuration back into TOML.
+func (encoder) Marshal(in map[string]interface{}) ([]byte, error) {
+	tree, err := toml.TreeFromMap(in)
+	if err != nil {
+		return nil, errors.NewFmt("toml error (%s)", err.Error()).WithCause(err)
+	}
+
+	out, err := tree.Marshal()
+	if err != nil {
+		return nil, errors.NewFmt("toml error (%s)", err.Error()).WithCause(err)
+	}
+
+	return out, nil
+}
@@ -2,11 +2,13 @@ package toml_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/lightstar/golib/internal/test/configtest"
 	"github.com/lightstar/golib/pkg/config/encoder/toml"
+	"github.com/lightstar/golib/pkg/config/i2s"
 )
 
 func TestTOMLEncoder(t *testing.T) {
@@ -24,3 +26,49 @@ func TestTOMLEncoderError(t *testing.T) {
 	err := toml.Encoder.Encode(configtest.SampleConfigDataWrongTOML, &result)
 	require.ErrorContains(t, err, "toml error")
 }
+
+func TestTOMLEncoderMarshal(t *testing.T) {
+	out, err := toml.Encoder.Marshal(configtest.ExpectedSampleRawDataTOML)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+
+	err = toml.Encoder.Encode(out, &result)
+	require.NoError(t, err)
+
+	require.Equal(t, configtest.ExpectedSampleRawDataTOML, result)
+}
+
+func TestTOMLEncoderDatetime(t *testing.T) {
+	type datetimeConfig struct {
+		T       time.Time
+		Timeout time.Duration
+	}
+
+	var result map[string]interface{}
+
+	require.NoError(t, toml.Encoder.Encode([]byte("t = 2020-01-01T00:00:00Z\ntimeout = \"5s\"\n"), &result))
+
+	var data datetimeConfig
+
+	require.NoError(t, i2s.Instance().Convert(result, &data))
+	require.Equal(t, datetimeConfig{T: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Timeout: 5 * time.Second}, data)
+
+	raw, err := i2s.Instance().ToMap(data)
+	require.NoError(t, err)
+
+	out, err := toml.Encoder.Marshal(raw)
+	require.NoError(t, err)
+
+	result = nil
+
+	require.NoError(t, toml.Encoder.Encode(out, &result))
+
+	var roundTrip datetimeConfig
+
+	require.NoError(t, i2s.Instance().Convert(result, &roundTrip))
+	require.Equal(t, data, roundTrip)
+
+	require.NoError(t, toml.Encoder.Encode([]byte("timeout = 5\n"), &result))
+	require.ErrorIs(t, i2s.Instance().Convert(result, &data), i2s.ErrMismatchedTypes)
+}
@@ -28,3 +28,13 @@ func (encoder) Encode(in []byte, out *map[string]interface{}) error {
 
 	return nil
 }
+
+// Marshal method converts structured representation of configuration back into YAML.
+func (encoder) Marshal(in map[string]interface{}) ([]byte, error) {
+	out, err := yaml.Marshal(in)
+	if err != nil {
+		return nil, errors.NewFmt("yaml error (%s)", err.Error()).WithCause(err)
+	}
+
+	return out, nil
+}
@@ -24,3 +24,15 @@ func TestTOMLEncoderError(t *testing.T) {
 	err := yaml.Encoder.Encode(configtest.SampleConfigDataWrongYAML, &result)
 	require.ErrorContains(t, err, "yaml error")
 }
+
+func TestYAMLEncoderMarshal(t *testing.T) {
+	out, err := yaml.Encoder.Marshal(configtest.ExpectedSampleRawDataYAML)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+
+	err = yaml.Encoder.Encode(out, &result)
+	require.NoError(t, err)
+
+	require.Equal(t, configtest.ExpectedSampleRawDataYAML, result)
+}
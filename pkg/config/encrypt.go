@@ -0,0 +1,271 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+const (
+	// EncryptedPrefix is the prefix of encrypted string values: 'enc:v1:<key id>:<base64 of nonce and ciphertext>'.
+	EncryptedPrefix = "enc:v1:"
+	// KeysEnvVar is the name of environment variable holding encryption keys, see ParseKeyring function.
+	KeysEnvVar = "CONFIG_KEYS"
+	// KeysFileEnvVar is the name of environment variable holding name of the file with encryption keys.
+	KeysFileEnvVar = "CONFIG_KEYS_FILE"
+	// KeySize is the size of generated encryption keys in bytes, so AES-256 is used.
+	KeySize = 32
+)
+
+// Keyring structure holds encryption keys by their ids. Values are encrypted with AES-GCM using the primary key, and
+// decrypted using the key with id stored inside the value, so keys can be rotated: add the new key as primary and keep
+// the old ones until all values are re-encrypted. Don't create it manually, use the functions down below instead.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring function creates new keyring with provided keys by their ids and the primary key id used for encryption.
+// Keys must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256. Ids can't be empty or contain colons.
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	keyring := &Keyring{primary: primary, keys: make(map[string]cipher.AEAD, len(keys))}
+
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, errors.NewFmt("invalid key id '%s'", id).WithCause(ErrInvalidKeyring)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, errors.NewFmt("invalid key '%s' (%s)", id, err.Error()).WithCause(ErrInvalidKeyring)
+		}
+
+		keyring.keys[id], err = cipher.NewGCM(block)
+		if err != nil {
+			return nil, errors.NewFmt("invalid key '%s' (%s)", id, err.Error()).WithCause(ErrInvalidKeyring)
+		}
+	}
+
+	if _, ok := keyring.keys[primary]; !ok {
+		return nil, errors.NewFmt("no primary key '%s'", primary).WithCause(ErrInvalidKeyring)
+	}
+
+	return keyring, nil
+}
+
+// ParseKeyring function parses keyring from the list of 'id:base64key' entries separated by commas or line breaks,
+// such as value of environment variable or contents of key file. The first entry is the primary key. Empty lines and
+// lines starting with '#' are ignored.
+func ParseKeyring(value string) (*Keyring, error) {
+	var primary string
+
+	keys := make(map[string][]byte)
+
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for _, entry := range strings.Split(line, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+
+			id, encodedKey, ok := strings.Cut(entry, ":")
+			if !ok {
+				return nil, errors.NewFmt("malformed key entry '%s'", entry).WithCause(ErrInvalidKeyring)
+			}
+
+			key, err := base64.StdEncoding.DecodeString(encodedKey)
+			if err != nil {
+				return nil, errors.NewFmt("malformed key '%s' (%s)", id, err.Error()).WithCause(ErrInvalidKeyring)
+			}
+
+			if _, ok = keys[id]; ok {
+				return nil, errors.NewFmt("duplicate key '%s'", id).WithCause(ErrInvalidKeyring)
+			}
+
+			if primary == "" {
+				primary = id
+			}
+
+			keys[id] = key
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no keys").WithCause(ErrInvalidKeyring)
+	}
+
+	return NewKeyring(primary, keys)
+}
+
+// KeyringFromEnv function creates keyring from environment variable CONFIG_KEYS, or from the file named in
+// environment variable CONFIG_KEYS_FILE. See ParseKeyring function for the format. If none of these variables is set,
+// ErrNoKeyring is returned.
+func KeyringFromEnv() (*Keyring, error) {
+	if value := os.Getenv(KeysEnvVar); value != "" {
+		return ParseKeyring(value)
+	}
+
+	name := os.Getenv(KeysFileEnvVar)
+	if name == "" {
+		return nil, ErrNoKeyring
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, errors.NewFmt("can't read from file '%s' (%s)", name, err.Error()).WithCause(err)
+	}
+
+	return ParseKeyring(string(data))
+}
+
+// GenerateKey function generates new random encryption key and retrieves it as the keyring entry 'id:base64key'.
+func GenerateKey(id string) (string, error) {
+	key := make([]byte, KeySize)
+
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", errors.NewFmt("can't generate key (%s)", err.Error()).WithCause(err)
+	}
+
+	return id + ":" + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// IsEncrypted function checks if value is encrypted, i.e. has EncryptedPrefix.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EncryptedPrefix)
+}
+
+// Encrypt method encrypts value with the primary key and retrieves it in the form 'enc:v1:<key id>:<base64>' that can
+// be put into configuration data.
+func (keyring *Keyring) Encrypt(value string) (string, error) {
+	aead := keyring.keys[keyring.primary]
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.NewFmt("can't generate nonce (%s)", err.Error()).WithCause(err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(keyring.primary))
+
+	return EncryptedPrefix + keyring.primary + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptValue method decrypts value produced by Encrypt method. Values without EncryptedPrefix are retrieved as is.
+func (keyring *Keyring) DecryptValue(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	id, encoded, ok := strings.Cut(value[len(EncryptedPrefix):], ":")
+	if !ok {
+		return "", errors.New("no key id").WithCause(ErrDecryption)
+	}
+
+	aead, ok := keyring.keys[id]
+	if !ok {
+		return "", errors.NewFmt("unknown key '%s'", id).WithCause(ErrDecryption)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.NewFmt("malformed value (%s)", err.Error()).WithCause(ErrDecryption)
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("value is too short").WithCause(ErrDecryption)
+	}
+
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", errors.NewFmt("wrong key '%s' or corrupted value", id).WithCause(ErrDecryption)
+	}
+
+	return string(plain), nil
+}
+
+// NewWithDecryption function creates new configuration service with data of provided one, where encrypted string
+// values are decrypted. See Decrypt function for details.
+//
+// Created configuration service follows updates of the provided one. Updates that can't be decrypted are ignored and
+// reported to the error function, see WithErrorFunc method.
+func NewWithDecryption(config *Config, keyring *Keyring) (*Config, error) {
+	data, err := Decrypt(config.GetRaw(), keyring)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted := NewFromRaw(data).inherit(config)
+
+	config.follow(decrypted, func(data map[string]interface{}) (map[string]interface{}, error) {
+		return Decrypt(data, keyring)
+	})
+
+	return decrypted, nil
+}
+
+// Decrypt function decrypts all string values with EncryptedPrefix inside provided data and retrieves decrypted copy
+// of it. Provided data itself is not modified. Decrypted values are always strings.
+//
+// All values that can't be decrypted are reported at once inside i2s.MultiError, each with the key path of the value.
+func Decrypt(data map[string]interface{}, keyring *Keyring) (map[string]interface{}, error) {
+	var errs []error
+
+	result := decryptValue("", data, keyring, &errs).(map[string]interface{})
+
+	if len(errs) > 0 {
+		return nil, &i2s.MultiError{Errors: errs}
+	}
+
+	return result, nil
+}
+
+// decryptValue function recursively decrypts value collecting errors instead of stopping on them. Map keys are walked
+// in sorted order, so errors are always reported in the same order.
+func decryptValue(path string, value interface{}, keyring *Keyring, errs *[]error) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typedValue))
+		for _, key := range sortedKeys(typedValue) {
+			result[key] = decryptValue(joinKey(path, key), typedValue[key], keyring, errs)
+		}
+
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(typedValue))
+		for index, elem := range typedValue {
+			result = append(result, decryptValue(joinKey(path, index), elem, keyring, errs))
+		}
+
+		return result
+	case []map[string]interface{}:
+		result := make([]map[string]interface{}, 0, len(typedValue))
+		for index, elem := range typedValue {
+			result = append(result, decryptValue(joinKey(path, index), elem, keyring, errs).(map[string]interface{}))
+		}
+
+		return result
+	case string:
+		result, err := keyring.DecryptValue(typedValue)
+		if err != nil {
+			*errs = append(*errs, errors.NewFmt("can't decrypt value of key '%s' (%s)", path,
+				err.Error()).WithCause(err))
+
+			return typedValue
+		}
+
+		return result
+	default:
+		return value
+	}
+}
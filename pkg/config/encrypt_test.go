@@ -0,0 +1,181 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/i2s"
+)
+
+func TestKeyringEncrypt(t *testing.T) {
+	oldEntry, err := config.GenerateKey("old")
+	require.NoError(t, err)
+
+	newEntry, err := config.GenerateKey("new")
+	require.NoError(t, err)
+
+	oldKeyring, err := config.ParseKeyring(oldEntry)
+	require.NoError(t, err)
+
+	encryptedOld, err := oldKeyring.Encrypt("mongo-secret")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encryptedOld, "enc:v1:old:"))
+	require.True(t, config.IsEncrypted(encryptedOld))
+
+	keyring, err := config.ParseKeyring("# rotated keys\n" + newEntry + ",\n" + oldEntry + "\n")
+	require.NoError(t, err)
+
+	encryptedNew, err := keyring.Encrypt("mongo-secret")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encryptedNew, "enc:v1:new:"))
+
+	encryptedAgain, err := keyring.Encrypt("mongo-secret")
+	require.NoError(t, err)
+	require.NotEqual(t, encryptedNew, encryptedAgain)
+
+	for _, encrypted := range []string{encryptedOld, encryptedNew, encryptedAgain} {
+		value, err := keyring.DecryptValue(encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "mongo-secret", value)
+	}
+
+	value, err := keyring.DecryptValue("plain")
+	require.NoError(t, err)
+	require.Equal(t, "plain", value)
+
+	_, err = oldKeyring.DecryptValue(encryptedNew)
+	require.ErrorIs(t, err, config.ErrDecryption)
+	require.Contains(t, err.Error(), "unknown key 'new'")
+
+	tampered := encryptedNew[:len(encryptedNew)-4] + "AAA="
+	for _, encrypted := range []string{tampered, "enc:v1:new", "enc:v1:new:%%%", "enc:v1:new:AAAA"} {
+		_, err = keyring.DecryptValue(encrypted)
+		require.ErrorIs(t, err, config.ErrDecryption, encrypted)
+	}
+
+	forged := strings.Replace(encryptedOld, "enc:v1:old:", "enc:v1:new:", 1)
+	_, err = keyring.DecryptValue(forged)
+	require.ErrorIs(t, err, config.ErrDecryption)
+}
+
+func TestParseKeyringErrors(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"# comment only",
+		"nokey",
+		"id:not-base64!",
+		"id:c2hvcnQ=",
+		":MDEyMzQ1Njc4OWFiY2RlZg==",
+		"a:MDEyMzQ1Njc4OWFiY2RlZg==,a:MDEyMzQ1Njc4OWFiY2RlZg==",
+	} {
+		_, err := config.ParseKeyring(value)
+		require.ErrorIs(t, err, config.ErrInvalidKeyring, value)
+	}
+
+	_, err := config.NewKeyring("missing", map[string][]byte{"a": []byte("0123456789abcdef")})
+	require.ErrorIs(t, err, config.ErrInvalidKeyring)
+}
+
+func TestKeyringFromEnv(t *testing.T) {
+	entry, err := config.GenerateKey("main")
+	require.NoError(t, err)
+
+	t.Setenv("CONFIG_KEYS", "")
+	t.Setenv("CONFIG_KEYS_FILE", "")
+
+	_, err = config.KeyringFromEnv()
+	require.Same(t, config.ErrNoKeyring, err)
+
+	keysPath := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(keysPath, []byte(entry+"\n"), 0o600))
+
+	t.Setenv("CONFIG_KEYS_FILE", keysPath)
+
+	keyring, err := config.KeyringFromEnv()
+	require.NoError(t, err)
+
+	encrypted, err := keyring.Encrypt("value")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encrypted, "enc:v1:main:"))
+
+	t.Setenv("CONFIG_KEYS_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	_, err = config.KeyringFromEnv()
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	t.Setenv("CONFIG_KEYS", "malformed")
+
+	_, err = config.KeyringFromEnv()
+	require.ErrorIs(t, err, config.ErrInvalidKeyring)
+}
+
+func TestNewWithDecryption(t *testing.T) {
+	entry, err := config.GenerateKey("main")
+	require.NoError(t, err)
+
+	keyring, err := config.ParseKeyring(entry)
+	require.NoError(t, err)
+
+	mongoPassword, err := keyring.Encrypt("mongo-secret")
+	require.NoError(t, err)
+
+	redisPassword, err := keyring.Encrypt("redis-secret")
+	require.NoError(t, err)
+
+	base := config.NewFromRaw(map[string]interface{}{
+		"mongo":   map[string]interface{}{"password": mongoPassword, "port": 27017},
+		"servers": []interface{}{map[string]interface{}{"password": redisPassword}},
+		"name":    "app",
+	})
+
+	cfg, err := config.NewWithDecryption(base, keyring)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"mongo":   map[string]interface{}{"password": "mongo-secret", "port": 27017},
+		"servers": []interface{}{map[string]interface{}{"password": "redis-secret"}},
+		"name":    "app",
+	}, cfg.GetRaw())
+
+	value, err := base.GetRawByKey("mongo.password")
+	require.NoError(t, err)
+	require.Equal(t, mongoPassword, value)
+
+	base.Update(map[string]interface{}{"mongo": map[string]interface{}{"password": redisPassword}})
+
+	value, err = cfg.GetRawByKey("mongo.password")
+	require.NoError(t, err)
+	require.Equal(t, "redis-secret", value)
+
+	base.Update(map[string]interface{}{"mongo": map[string]interface{}{"password": "enc:v1:other:AAAA"}})
+
+	value, err = cfg.GetRawByKey("mongo.password")
+	require.NoError(t, err)
+	require.Equal(t, "redis-secret", value)
+}
+
+func TestDecryptErrors(t *testing.T) {
+	entry, err := config.GenerateKey("main")
+	require.NoError(t, err)
+
+	keyring, err := config.ParseKeyring(entry)
+	require.NoError(t, err)
+
+	_, err = config.Decrypt(map[string]interface{}{
+		"mongo": map[string]interface{}{"password": "enc:v1:other:AAAA"},
+		"redis": []interface{}{"enc:v1:main:AAAA"},
+	}, keyring)
+	require.ErrorIs(t, err, config.ErrDecryption)
+
+	var multiErr *i2s.MultiError
+
+	require.ErrorAs(t, err, &multiErr)
+	require.Len(t, multiErr.Errors, 2)
+	require.Contains(t, multiErr.Errors[0].Error(), "'mongo.password'")
+	require.Contains(t, multiErr.Errors[1].Error(), "'redis.0'")
+}
@@ -2,10 +2,37 @@
 // with some encoder, both defined in environment variables.
 //
 // Used environment variables:
-// CONFIG_FILE - configuration file name. Default is 'configs/config.<encoder>'.
+// CONFIG_SOURCES - source URIs applied in order, such as 'file://configs/base.yaml file:///etc/app/override.toml'.
+// Default is none, then CONFIG_FILE or CONFIG_ETCD_ENDPOINTS and CONFIG_ETCD_KEY are used. See NewSourcesConfig.
+// CONFIG_FILE - configuration file name. Default is 'configs/config' with extension of the encoder, such as
+// 'configs/config.yaml' or 'configs/config.env'.
 // CONFIG_ETCD_ENDPOINTS - etcd endpoints separated with comma. Such as '127.0.0.1:2379'.
 // CONFIG_ETCD_KEY - key in etcd server where configuration data is stored.
-// CONFIG_ENCODER - one of the supported encoders: json, yaml or toml. Default is 'yaml'.
+// CONFIG_ENCODER - one of the supported encoders: json, yaml, toml, dotenv, ini or properties. Default is 'yaml'.
+// With CONFIG_SOURCES it is used only for sources without file extension or 'encoder' query parameter.
+// CONFIG_PROFILE - comma-separated list of profiles applied in order, such as 'prod' or 'prod,eu'. Default is none.
+// CONFIG_ENV_PREFIX - prefix of environment variables that override configuration values. Default is none.
+// CONFIG_ENV_SEPARATOR - separator of key segments inside overriding environment variable names. Default is '__'.
+// CONFIG_INTERPOLATE - 'true' to resolve references inside string values. Default is 'false'.
+// CONFIG_KEYS - encryption keys 'id:base64key' separated with comma, the first one is primary. Default is none.
+// CONFIG_KEYS_FILE - file with encryption keys, one per line, used if CONFIG_KEYS is not set. Default is none.
+//
+// Profiles are sections under 'profiles' key deep-merged on top of the base data, see config.NewWithProfiles for
+// details. Active profiles can be retrieved with Profiles method of the created configuration service. If no profile
+// is selected, data is left as is, including 'profiles' key.
+//
+// For example with CONFIG_ENV_PREFIX set to 'APP', variable 'APP__HTTP__ADDRESS' overrides key 'http.address'.
+// See config.NewWithEnvOverrides for details.
+//
+// Use NewConfigWithArgs to override values with command-line flags like '--http.address=0.0.0.0:80' as well.
+//
+// With CONFIG_INTERPOLATE set to 'true', references inside string values like '${env:REDIS_PASSWORD}',
+// '${file:/run/secrets/mongo}', '${key:other.path}' or '${env:PORT:-8080}' are resolved after overrides are applied.
+// Use '$${' to keep literal '${' then. It is disabled by default, so existing values containing '${' are kept as is.
+// See config.Interpolate for details.
+//
+// If encryption keys are provided, values like 'enc:v1:<key id>:<data>' are decrypted last of all, so they can come
+// from any source including references. See config.Decrypt for details.
 //
 // Typical usage:
 //
@@ -21,14 +48,14 @@ package env
 
 import (
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/lightstar/golib/pkg/config"
-	"github.com/lightstar/golib/pkg/config/encoder/json"
-	"github.com/lightstar/golib/pkg/config/encoder/toml"
-	"github.com/lightstar/golib/pkg/config/encoder/yaml"
+	"github.com/lightstar/golib/pkg/config/encoder"
 	"github.com/lightstar/golib/pkg/config/etcd"
 	"github.com/lightstar/golib/pkg/config/file"
+	"github.com/lightstar/golib/pkg/config/flags"
 	"github.com/lightstar/golib/pkg/errors"
 )
 
@@ -37,33 +64,102 @@ const (
 	configEtcdEndpointsEnvVar = "CONFIG_ETCD_ENDPOINTS"
 	configEtcdKeyEnvVar       = "CONFIG_ETCD_KEY"
 	configEncoderEnvVar       = "CONFIG_ENCODER"
+	configProfileEnvVar       = "CONFIG_PROFILE"
+	configEnvPrefixEnvVar     = "CONFIG_ENV_PREFIX"
+	configEnvSeparatorEnvVar  = "CONFIG_ENV_SEPARATOR"
+	configInterpolateEnvVar   = "CONFIG_INTERPOLATE"
 	configEncoderNameDef      = "yaml"
-	configFileDefPrefix       = "configs/config."
+	configFileDefName         = "configs/config"
 )
 
 // ErrUnknownEncoder error is returned when encoder defined in environment variable CONFIG_ENCODER is unsupported.
-var ErrUnknownEncoder = errors.New("unknown encoder")
+// It is the same error as encoder.ErrUnknownEncoder and is returned as is, so it can be compared directly. Errors of
+// CONFIG_SOURCES wrap it, so use errors.Is to check for it in general.
+var ErrUnknownEncoder = encoder.ErrUnknownEncoder
 
 // NewConfig function creates new configuration service using source and encoder defined in environment variables.
-// Use CONFIG_FILE to define configuration file. Default is 'configs/config.<encoder>'.
+// Use CONFIG_SOURCES to define several sources with their own encoders, see NewSourcesConfig for details.
+// Use CONFIG_FILE to define configuration file. Default is 'configs/config' with extension of the encoder.
 // Use CONFIG_ETCD_ENDPOINTS and CONFIG_ETCD_KEY to define etcd deployment as a source.
-// Use CONFIG_ENCODER to define one of the supported encoders: json, yaml or toml. Default is 'yaml'.
+// Use CONFIG_ENCODER to define one of the supported encoders: json, yaml, toml, dotenv, ini or properties.
+// Default is 'yaml'.
+// Use CONFIG_PROFILE to select profiles applied on top of the base data.
+// Use CONFIG_ENV_PREFIX and optionally CONFIG_ENV_SEPARATOR to allow overriding values by environment variables.
+// Use CONFIG_INTERPOLATE to resolve references inside string values with default resolvers.
+// Use CONFIG_KEYS or CONFIG_KEYS_FILE to decrypt encrypted values.
 func NewConfig() (*config.Config, error) {
-	var configEncoder config.Encoder
+	return NewConfigWithArgs(nil, nil)
+}
+
+// NewConfigWithArgs function does the same as NewConfig, but also overrides values with command-line flags from
+// provided arguments, usually os.Args[1:]. Flags have the highest precedence. Description is the structure
+// describing all configuration keys or nil to accept any key, see flags.NewConfig for details.
+func NewConfigWithArgs(args []string, description interface{}) (*config.Config, error) {
+	cfg, err := newSourceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if profiles := config.ParseProfiles(os.Getenv(configProfileEnvVar)); len(profiles) > 0 {
+		cfg, err = config.NewWithProfiles(cfg, profiles...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	configEnvPrefix := os.Getenv(configEnvPrefixEnvVar)
+	if configEnvPrefix != "" {
+		cfg, err = config.NewWithEnvOverrides(cfg, configEnvPrefix, os.Getenv(configEnvSeparatorEnvVar))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if args != nil || description != nil {
+		cfg, err = flags.NewConfig(cfg, args, description)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	interpolate, err := boolEnv(configInterpolateEnvVar)
+	if err != nil {
+		return nil, err
+	}
+
+	if interpolate {
+		cfg, err = config.NewWithInterpolation(cfg, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keyring, err := config.KeyringFromEnv()
+	if errors.Is(err, config.ErrNoKeyring) {
+		return cfg, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return config.NewWithDecryption(cfg, keyring)
+}
+
+// newSourceConfig function creates new configuration service using source and encoder defined in environment
+// variables.
+func newSourceConfig() (*config.Config, error) {
+	if configSources := os.Getenv(configSourcesEnvVar); configSources != "" {
+		return NewSourcesConfig(configSources)
+	}
 
 	configEncoderName := os.Getenv(configEncoderEnvVar)
 	if configEncoderName == "" {
 		configEncoderName = configEncoderNameDef
 	}
 
-	switch configEncoderName {
-	case "json":
-		configEncoder = json.Encoder
-	case "yaml":
-		configEncoder = yaml.Encoder
-	case "toml":
-		configEncoder = toml.Encoder
-	default:
+	configEncoder, err := encoder.ByType(configEncoderName)
+	if err != nil {
 		return nil, ErrUnknownEncoder
 	}
 
@@ -76,8 +172,23 @@ func NewConfig() (*config.Config, error) {
 
 	configFile := os.Getenv(configFileEnvVar)
 	if configFile == "" {
-		configFile = configFileDefPrefix + configEncoderName
+		configFile = configFileDefName + encoder.Extensions(configEncoderName)[0]
 	}
 
 	return file.NewConfig(configFile, configEncoder)
 }
+
+// boolEnv function retrieves bool value of environment variable, empty or missing variable means false.
+func boolEnv(name string) (bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.NewFmt("invalid value '%s' of %s, expected bool", value, name)
+	}
+
+	return result, nil
+}
@@ -8,7 +8,10 @@ import (
 
 	"github.com/lightstar/golib/internal/test/configtest"
 	"github.com/lightstar/golib/internal/test/iotest"
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder"
 	"github.com/lightstar/golib/pkg/config/env"
+	"github.com/lightstar/golib/pkg/config/flags"
 )
 
 const (
@@ -55,6 +58,42 @@ func TestEnvTOML(t *testing.T) {
 	configtest.TestSampleConfig(t, cfg, configtest.ExpectedSampleRawDataTOML)
 }
 
+func TestEnvFlatEncoders(t *testing.T) {
+	for encoderName, data := range map[string][]byte{
+		"dotenv":     configtest.SampleConfigDataDotenv,
+		"ini":        configtest.SampleConfigDataINI,
+		"properties": configtest.SampleConfigDataProperties,
+	} {
+		iotest.WriteFile(t, testConfigPath, data)
+
+		t.Setenv("CONFIG_FILE", testConfigPath)
+		t.Setenv("CONFIG_ENCODER", encoderName)
+
+		cfg, err := env.NewConfig()
+		require.NoError(t, err, encoderName)
+
+		configtest.TestSampleConfig(t, cfg, configtest.ExpectedSampleRawDataFlat)
+
+		iotest.RemoveFile(t, testConfigPath)
+	}
+}
+
+func TestEnvDefaultFile(t *testing.T) {
+	iotest.WriteFile(t, "configs/config.env", configtest.SampleConfigDataDotenv)
+	defer iotest.RemoveFile(t, "configs")
+	defer iotest.RemoveFile(t, "configs/config.env")
+
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("CONFIG_ETCD_ENDPOINTS", "")
+	t.Setenv("CONFIG_ETCD_KEY", "")
+	t.Setenv("CONFIG_ENCODER", "dotenv")
+
+	cfg, err := env.NewConfig()
+	require.NoError(t, err)
+
+	configtest.TestSampleConfig(t, cfg, configtest.ExpectedSampleRawDataFlat)
+}
+
 func TestEnvEtcd(t *testing.T) {
 	endpoints := os.Getenv("TEST_CONFIG_ETCD_ENDPOINTS")
 	if endpoints == "" {
@@ -81,7 +120,8 @@ func TestEnvErrors(t *testing.T) {
 	t.Setenv("CONFIG_ENCODER", "unknown")
 
 	_, err := env.NewConfig()
-	require.Error(t, err)
+	require.Same(t, env.ErrUnknownEncoder, err)
+	require.ErrorIs(t, err, encoder.ErrUnknownEncoder)
 
 	t.Setenv("CONFIG_FILE", "")
 	t.Setenv("CONFIG_ETCD_ENDPOINTS", "")
@@ -91,3 +131,176 @@ func TestEnvErrors(t *testing.T) {
 	_, err = env.NewConfig()
 	require.Error(t, err)
 }
+
+func TestEnvOverrides(t *testing.T) {
+	iotest.WriteFile(t, testConfigPath, configtest.SampleConfigDataJSON)
+	defer iotest.RemoveFile(t, testConfigPath)
+
+	t.Setenv("CONFIG_FILE", testConfigPath)
+	t.Setenv("CONFIG_ENCODER", "json")
+	t.Setenv("CONFIG_ENV_PREFIX", "TEST")
+	t.Setenv("TEST__PROFILE__AGE", "40")
+
+	cfg, err := env.NewConfig()
+	require.NoError(t, err)
+
+	var profile configtest.UserProfile
+
+	require.NoError(t, cfg.GetByKey("profile", &profile))
+	require.Equal(t, 40, profile.Age)
+}
+
+func TestEnvInterpolation(t *testing.T) {
+	iotest.WriteFile(t, testConfigPath, []byte(`{"profile":{"sex":"none","age":"${env:TEST_PROFILE_AGE:-30}"}}`))
+	defer iotest.RemoveFile(t, testConfigPath)
+
+	t.Setenv("CONFIG_FILE", testConfigPath)
+	t.Setenv("CONFIG_ENCODER", "json")
+	t.Setenv("CONFIG_ENV_PREFIX", "TEST")
+	t.Setenv("TEST__PROFILE__SEX", "${env:TEST_PROFILE_SEX}")
+	t.Setenv("TEST_PROFILE_SEX", "male")
+
+	cfg, err := env.NewConfig()
+	require.NoError(t, err)
+	require.Equal(t, "${env:TEST_PROFILE_SEX}", cfg.GetRaw()["profile"].(map[string]interface{})["sex"])
+
+	t.Setenv("CONFIG_INTERPOLATE", "true")
+
+	cfg, err = env.NewConfig()
+	require.NoError(t, err)
+
+	var profile configtest.UserProfile
+
+	require.NoError(t, cfg.GetByKey("profile", &profile))
+	require.Equal(t, "male", profile.Sex)
+	require.Equal(t, 30, profile.Age)
+
+	require.NoError(t, os.Unsetenv("TEST_PROFILE_SEX"))
+
+	_, err = env.NewConfig()
+	require.ErrorIs(t, err, config.ErrUnresolvedReference)
+
+	t.Setenv("CONFIG_INTERPOLATE", "maybe")
+
+	_, err = env.NewConfig()
+	require.ErrorContains(t, err, "CONFIG_INTERPOLATE")
+}
+
+func TestEnvArgs(t *testing.T) {
+	iotest.WriteFile(t, testConfigPath, configtest.SampleConfigDataJSON)
+	defer iotest.RemoveFile(t, testConfigPath)
+
+	t.Setenv("CONFIG_FILE", testConfigPath)
+	t.Setenv("CONFIG_ENCODER", "json")
+	t.Setenv("CONFIG_INTERPOLATE", "1")
+	t.Setenv("CONFIG_ENV_PREFIX", "TEST")
+	t.Setenv("TEST__PROFILE__AGE", "40")
+	t.Setenv("TEST__PROFILE__SEX", "f")
+	t.Setenv("TEST_PROFILE_MARRIED", "false")
+
+	cfg, err := env.NewConfigWithArgs([]string{"--profile.age=50", "--profile.married=${env:TEST_PROFILE_MARRIED}"},
+		nil)
+	require.NoError(t, err)
+
+	var profile configtest.UserProfile
+
+	require.NoError(t, cfg.GetByKey("profile", &profile))
+	require.Equal(t, 50, profile.Age)
+	require.Equal(t, "f", profile.Sex)
+	require.False(t, profile.Married)
+
+	_, err = env.NewConfigWithArgs([]string{"--profile.age=50", "run"}, nil)
+	require.ErrorIs(t, err, flags.ErrUnexpectedArgument)
+}
+
+func TestEnvProfiles(t *testing.T) {
+	iotest.WriteFile(t, testConfigPath, []byte(`{
+		"profile": {"sex": "m", "age": 30},
+		"profiles": {
+			"dev": {"profile": {"age": 40, "married": true}},
+			"prod": {"profile": {"age": 50}}
+		}
+	}`))
+	defer iotest.RemoveFile(t, testConfigPath)
+
+	t.Setenv("CONFIG_FILE", testConfigPath)
+	t.Setenv("CONFIG_ENCODER", "json")
+	t.Setenv("CONFIG_PROFILE", "dev, prod")
+	t.Setenv("CONFIG_ENV_PREFIX", "TEST")
+	t.Setenv("TEST__PROFILE__SEX", "f")
+
+	cfg, err := env.NewConfig()
+	require.NoError(t, err)
+
+	var profile configtest.UserProfile
+
+	require.NoError(t, cfg.GetByKey("profile", &profile))
+	require.Equal(t, "f", profile.Sex)
+	require.Equal(t, 50, profile.Age)
+	require.True(t, profile.Married)
+	require.Equal(t, []string{"dev", "prod"}, cfg.Profiles())
+
+	t.Setenv("CONFIG_PROFILE", "")
+
+	cfg, err = env.NewConfig()
+	require.NoError(t, err)
+	require.Empty(t, cfg.Profiles())
+
+	_, err = cfg.GetRawByKey("profiles.dev")
+	require.NoError(t, err)
+
+	t.Setenv("CONFIG_PROFILE", "staging")
+
+	_, err = env.NewConfig()
+	require.ErrorIs(t, err, config.ErrUnknownProfile)
+
+	iotest.WriteFile(t, testConfigPath, []byte(`{"profiles": ["admin", "guest"]}`))
+
+	t.Setenv("CONFIG_PROFILE", " ")
+
+	cfg, err = env.NewConfig()
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"admin", "guest"}, cfg.GetRaw()["profiles"])
+}
+
+func TestEnvDecryption(t *testing.T) {
+	entry, err := config.GenerateKey("main")
+	require.NoError(t, err)
+
+	keyring, err := config.ParseKeyring(entry)
+	require.NoError(t, err)
+
+	password, err := keyring.Encrypt("mongo-secret")
+	require.NoError(t, err)
+
+	iotest.WriteFile(t, testConfigPath, []byte(`{"mongo": {"password": "`+password+`"}}`))
+	defer iotest.RemoveFile(t, testConfigPath)
+
+	t.Setenv("CONFIG_FILE", testConfigPath)
+	t.Setenv("CONFIG_ENCODER", "json")
+	t.Setenv("CONFIG_KEYS", entry)
+
+	cfg, err := env.NewConfig()
+	require.NoError(t, err)
+
+	value, err := config.Value(cfg, "mongo.password", "")
+	require.NoError(t, err)
+	require.Equal(t, "mongo-secret", value)
+
+	t.Setenv("CONFIG_KEYS", "")
+
+	cfg, err = env.NewConfig()
+	require.NoError(t, err)
+
+	value, err = config.Value(cfg, "mongo.password", "")
+	require.NoError(t, err)
+	require.Equal(t, password, value)
+
+	otherEntry, err := config.GenerateKey("other")
+	require.NoError(t, err)
+
+	t.Setenv("CONFIG_KEYS", otherEntry)
+
+	_, err = env.NewConfig()
+	require.ErrorIs(t, err, config.ErrDecryption)
+}
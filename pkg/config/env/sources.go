@@ -0,0 +1,201 @@
+package env
+
+import (
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder"
+	"github.com/lightstar/golib/pkg/config/etcd"
+	"github.com/lightstar/golib/pkg/config/file"
+	"github.com/lightstar/golib/pkg/config/http"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+const (
+	configSourcesEnvVar = "CONFIG_SOURCES"
+	encoderQueryParam   = "encoder"
+	schemeSeparator     = "://"
+)
+
+var (
+	// ErrUnknownSource error is returned when source URI in environment variable CONFIG_SOURCES has scheme without
+	// registered source type.
+	ErrUnknownSource = errors.New("unknown source")
+
+	// ErrInvalidSource error is returned when source URI in environment variable CONFIG_SOURCES is malformed.
+	ErrInvalidSource = errors.New("invalid source")
+)
+
+// Source function creates configuration service using source URI and encoder chosen for it. It is registered for
+// some URI scheme with RegisterSource function.
+type Source func(uri *url.URL, encoder config.Encoder) (*config.Config, error)
+
+//nolint:gochecknoglobals // registry of source types is global, so they can be added by other packages.
+var (
+	sourcesMu sync.RWMutex
+	sources   = map[string]Source{
+		"file":  fileSource,
+		"etcd":  etcdSource,
+		"http":  httpSource,
+		"https": httpSource,
+	}
+)
+
+// RegisterSource function registers source type for provided URI scheme, so URIs like '<scheme>://...' can be used in
+// environment variable CONFIG_SOURCES. Predefined types 'file', 'etcd', 'http' and 'https' can be replaced as well.
+func RegisterSource(scheme string, source Source) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	sources[strings.ToLower(scheme)] = source
+}
+
+// NewSourcesConfig function creates new configuration service by deep-merging data of sources with provided URIs in
+// order, so later sources override earlier ones. URIs are separated with whitespace, semicolons or commas that are
+// followed by another URI, so commas between etcd endpoints are kept. Supported URIs are:
+//
+//	file://configs/base.yaml - file with path relative to the current directory
+//	file:///etc/app/override.toml - file with absolute path
+//	etcd://host1:2379,host2:2379/app/config - etcd key '/app/config' on provided endpoints
+//	http://host/app/config.json or https://... - document at provided URL
+//
+// Encoder of each source is inferred from the extension of URI path, such as '.yaml' or '.toml'. Use query parameter
+// like '?encoder=json' to choose it explicitly. If neither is present, default encoder is used, it is defined by
+// environment variable CONFIG_ENCODER and is 'yaml' by default. Other source types can be added with RegisterSource
+// function.
+func NewSourcesConfig(uris string) (*config.Config, error) {
+	defaultEncoderName := os.Getenv(configEncoderEnvVar)
+	if defaultEncoderName == "" {
+		defaultEncoderName = configEncoderNameDef
+	}
+
+	splitURIs := splitSources(uris)
+	if len(splitURIs) == 0 {
+		return nil, errors.New("no sources provided").WithCause(ErrInvalidSource)
+	}
+
+	cfgs := make([]*config.Config, 0, len(splitURIs))
+
+	for _, rawURI := range splitURIs {
+		cfg, err := newURIConfig(rawURI, defaultEncoderName)
+		if err != nil {
+			return nil, err
+		}
+
+		cfgs = append(cfgs, cfg)
+	}
+
+	if len(cfgs) == 1 {
+		return cfgs[0], nil
+	}
+
+	return config.Merge(cfgs...), nil
+}
+
+// newURIConfig function creates configuration service using source with provided URI.
+func newURIConfig(rawURI string, defaultEncoderName string) (*config.Config, error) {
+	uri, err := url.Parse(rawURI)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+
+		return nil, errors.NewFmt("malformed source '%s' (%s)", http.RedactURL(rawURI), err.Error()).
+			WithCause(ErrInvalidSource)
+	}
+
+	sourcesMu.RLock()
+	source, ok := sources[strings.ToLower(uri.Scheme)]
+	sourcesMu.RUnlock()
+
+	if !ok {
+		return nil, errors.NewFmt("unknown source '%s'", http.RedactURL(rawURI)).WithCause(ErrUnknownSource)
+	}
+
+	sourceEncoder, err := uriEncoder(uri, defaultEncoderName)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := source(uri, sourceEncoder)
+	if err != nil {
+		return nil, errors.NewFmt("can't read source '%s' (%s)", http.RedactURL(uri.String()), err.Error()).WithCause(err)
+	}
+
+	return cfg, nil
+}
+
+// uriEncoder function retrieves encoder of the source defined in its URI query parameter, or inferred from its path,
+// or the default one. Query parameter is removed from the URI, so sources don't see it.
+func uriEncoder(uri *url.URL, defaultEncoderName string) (config.Encoder, error) {
+	query := uri.Query()
+
+	if query.Has(encoderQueryParam) {
+		encoderName := query.Get(encoderQueryParam)
+
+		query.Del(encoderQueryParam)
+		uri.RawQuery = query.Encode()
+
+		return encoder.ByType(encoderName)
+	}
+
+	if sourceEncoder, err := encoder.ByFileName(uri.Host + uri.Path); err == nil {
+		return sourceEncoder, nil
+	}
+
+	return encoder.ByType(defaultEncoderName)
+}
+
+// splitSources function splits list of source URIs. Whitespace and semicolons always separate URIs, while comma
+// separates them only if it is followed by another URI with scheme.
+func splitSources(uris string) []string {
+	parts := strings.FieldsFunc(uris, func(r rune) bool {
+		return r == ';' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+
+	result := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		for i, segment := range strings.Split(part, ",") {
+			if i > 0 && len(result) > 0 && !strings.Contains(segment, schemeSeparator) {
+				result[len(result)-1] += "," + segment
+				continue
+			}
+
+			if segment != "" {
+				result = append(result, segment)
+			}
+		}
+	}
+
+	return result
+}
+
+// fileSource function creates configuration service using file source, see file.NewConfig for details.
+func fileSource(uri *url.URL, encoder config.Encoder) (*config.Config, error) {
+	name := uri.Host + uri.Path
+	if name == "" {
+		return nil, errors.NewFmt("source '%s' must have file path", http.RedactURL(uri.String())).WithCause(ErrInvalidSource)
+	}
+
+	return file.NewConfig(name, encoder)
+}
+
+// etcdSource function creates configuration service using etcd source, see etcd.NewConfig for details.
+func etcdSource(uri *url.URL, encoder config.Encoder) (*config.Config, error) {
+	if uri.Host == "" || uri.Path == "" || uri.Path == "/" {
+		return nil, errors.NewFmt("source '%s' must have endpoints and key", http.RedactURL(uri.String())).
+			WithCause(ErrInvalidSource)
+	}
+
+	return etcd.NewConfig(strings.Split(uri.Host, ","), uri.Path, encoder)
+}
+
+// httpSource function creates configuration service using HTTP source, see http.NewConfig for details.
+func httpSource(uri *url.URL, encoder config.Encoder) (*config.Config, error) {
+	return http.NewConfig(uri.String(), encoder)
+}
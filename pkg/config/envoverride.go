@@ -0,0 +1,153 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config/internal/flat"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// DefEnvSeparator is the default separator of key segments inside environment variable names.
+const DefEnvSeparator = "__"
+
+// NewWithEnvOverrides function creates new configuration service with data of provided one, where any value can be
+// overridden by environment variable. Variable name consists of prefix and key segments joined by separator, so with
+// prefix 'APP' and separator '__' variable 'APP__HTTP__ADDRESS' overrides key 'http.address'.
+//
+// Key segments are matched with existing keys case-insensitively, missing keys are created in lower case. Variable
+// with empty key segment, such as 'APP__HTTP____PORT' or 'APP__HTTP__', fails with ErrInvalidKey error.
+// Variable value is converted to the type of existing value: int, float, bool, string, or JSON literal for
+// lists and maps. If there is no existing value, type is inferred from the variable value itself, where only plain
+// decimal literals become numbers, so values like '0123' or 'inf' stay strings.
+//
+// Created configuration service follows updates of the provided one. Updates with invalid variable values are ignored
+// and reported to the error function, see WithErrorFunc method.
+func NewWithEnvOverrides(config *Config, prefix string, separator string) (*Config, error) {
+	if prefix == "" {
+		return nil, ErrEmptyEnvPrefix
+	}
+
+	if separator == "" {
+		separator = DefEnvSeparator
+	}
+
+	data, err := overrideWithEnv(config.GetRaw(), prefix, separator)
+	if err != nil {
+		return nil, err
+	}
+
+	overridden := NewFromRaw(data).inherit(config)
+
+	config.follow(overridden, func(data map[string]interface{}) (map[string]interface{}, error) {
+		return overrideWithEnv(data, prefix, separator)
+	})
+
+	return overridden, nil
+}
+
+// overrideWithEnv function applies all environment variables with provided prefix to the copy of provided data.
+func overrideWithEnv(data map[string]interface{}, prefix string, separator string) (map[string]interface{}, error) {
+	environ := os.Environ()
+	sort.Strings(environ)
+
+	namePrefix := prefix + separator
+
+	for _, envElem := range environ {
+		name, value, _ := strings.Cut(envElem, "=")
+		if !strings.HasPrefix(name, namePrefix) || len(name) == len(namePrefix) {
+			continue
+		}
+
+		path := strings.Split(name[len(namePrefix):], separator)
+
+		for _, segment := range path {
+			if segment == "" {
+				return nil, errors.NewFmt("environment variable '%s' has empty key segment", name).
+					WithCause(ErrInvalidKey)
+			}
+		}
+
+		var err error
+
+		data, err = overrideValue(data, path, value)
+		if err != nil {
+			return nil, errors.NewFmt("can't override config with environment variable '%s' (%s)", name,
+				err.Error()).WithCause(err)
+		}
+	}
+
+	return data, nil
+}
+
+// overrideValue function sets value by the key path in the copy of provided data.
+func overrideValue(data map[string]interface{}, path []string, value string) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(data)+1)
+
+	key := strings.ToLower(path[0])
+
+	for dataKey, dataValue := range data {
+		result[dataKey] = dataValue
+
+		if strings.EqualFold(dataKey, path[0]) {
+			key = dataKey
+		}
+	}
+
+	if len(path) == 1 {
+		coercedValue, err := coerceEnvValue(result[key], value)
+		if err != nil {
+			return nil, err
+		}
+
+		result[key] = coercedValue
+
+		return result, nil
+	}
+
+	inner, ok := result[key].(map[string]interface{})
+	if !ok {
+		inner = nil
+	}
+
+	inner, err := overrideValue(inner, path[1:], value)
+	if err != nil {
+		return nil, err
+	}
+
+	result[key] = inner
+
+	return result, nil
+}
+
+// coerceEnvValue function converts environment variable value to the type of existing value.
+func coerceEnvValue(existing interface{}, value string) (interface{}, error) {
+	var result interface{}
+	var err error
+
+	switch existing.(type) {
+	case string:
+		result = value
+	case int:
+		result, err = strconv.Atoi(value)
+	case int64:
+		result, err = strconv.ParseInt(value, 10, 64)
+	case float64:
+		result, err = strconv.ParseFloat(value, 64)
+	case bool:
+		result, err = strconv.ParseBool(value)
+	case nil:
+		result = flat.InferJSON(value)
+	default:
+		err = json.Unmarshal([]byte(value), &result)
+	}
+
+	if err != nil {
+		return nil, errors.NewFmt("value '%s' doesn't match type '%T'", value, existing).WithCause(ErrInvalidEnvValue)
+	}
+
+	return result, nil
+}
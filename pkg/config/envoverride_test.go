@@ -0,0 +1,87 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config"
+)
+
+func TestEnvOverrides(t *testing.T) {
+	base := config.NewFromRaw(map[string]interface{}{
+		"name": "Peter",
+		"http": map[string]interface{}{
+			"address": "127.0.0.1:8080",
+			"timeout": 3,
+			"ratio":   0.5,
+			"debug":   false,
+			"tags":    []interface{}{"a"},
+		},
+		"redis": map[string]interface{}{
+			"maxIdle": int64(3),
+		},
+	})
+
+	t.Setenv("APP__HTTP__ADDRESS", "0.0.0.0:80")
+	t.Setenv("APP__HTTP__TIMEOUT", "10")
+	t.Setenv("APP__HTTP__RATIO", "0.75")
+	t.Setenv("APP__HTTP__DEBUG", "true")
+	t.Setenv("APP__HTTP__TAGS", `["b","c"]`)
+	t.Setenv("APP__REDIS__MAXIDLE", "5")
+	t.Setenv("APP__MONGO__ADDRESS", "mongo:27017")
+	t.Setenv("APP__MONGO__TIMEOUT", "15")
+	t.Setenv("OTHER__NAME", "George")
+
+	cfg, err := config.NewWithEnvOverrides(base, "APP", "")
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"name": "Peter",
+		"http": map[string]interface{}{
+			"address": "0.0.0.0:80",
+			"timeout": 10,
+			"ratio":   0.75,
+			"debug":   true,
+			"tags":    []interface{}{"b", "c"},
+		},
+		"redis": map[string]interface{}{
+			"maxIdle": int64(5),
+		},
+		"mongo": map[string]interface{}{
+			"address": "mongo:27017",
+			"timeout": 15,
+		},
+	}, cfg.GetRaw())
+
+	require.Equal(t, "127.0.0.1:8080", base.GetRaw()["http"].(map[string]interface{})["address"])
+
+	base.Update(map[string]interface{}{"name": "George"})
+	require.Equal(t, "George", cfg.GetRaw()["name"])
+	require.Equal(t, "0.0.0.0:80", cfg.GetRaw()["http"].(map[string]interface{})["address"])
+}
+
+func TestEnvOverridesErrors(t *testing.T) {
+	base := config.NewFromRaw(map[string]interface{}{
+		"timeout": 3,
+	})
+
+	_, err := config.NewWithEnvOverrides(base, "", "")
+	require.Same(t, config.ErrEmptyEnvPrefix, err)
+
+	t.Setenv("APP_TIMEOUT", "soon")
+
+	_, err = config.NewWithEnvOverrides(base, "APP", "_")
+	require.ErrorIs(t, err, config.ErrInvalidEnvValue)
+	require.ErrorContains(t, err, "APP_TIMEOUT")
+
+	for _, name := range []string{"APP__REDIS____PORT", "APP__REDIS__", "APP____PORT"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, "6379")
+
+			_, err := config.NewWithEnvOverrides(base, "APP", "__")
+			require.ErrorIs(t, err, config.ErrInvalidKey)
+			require.ErrorContains(t, err, name)
+		})
+	}
+}
@@ -8,6 +8,46 @@ var (
 	// ErrNoSuchKey error is returned when source configuration data doesn't have the wanted key.
 	ErrNoSuchKey = errors.New("no such key")
 
+	// ErrInvalidKey error is returned when composite key has invalid syntax.
+	ErrInvalidKey = errors.New("invalid key")
+
+	// ErrInvalidValue error is returned when configuration value can't be converted into the requested type, see
+	// ValueError structure.
+	ErrInvalidValue = errors.New("invalid configuration value")
+
 	// ErrNotMap error is returned when retrieved data is not a map, but it has to be.
 	ErrNotMap = errors.New("data by key is not a map")
+
+	// ErrUnknownProfile error is returned when selected profile is not defined in configuration data.
+	ErrUnknownProfile = errors.New("unknown profile")
+
+	// ErrEmptyEnvPrefix error is returned when environment variables prefix is empty, so all environment variables
+	// would be treated as configuration overrides.
+	ErrEmptyEnvPrefix = errors.New("environment variables prefix is empty")
+
+	// ErrInvalidEnvValue error is returned when value of environment variable can't be converted to the type of the
+	// value it overrides.
+	ErrInvalidEnvValue = errors.New("invalid environment variable value")
+
+	// ErrInvalidReference error is returned when reference inside configuration value has invalid syntax.
+	ErrInvalidReference = errors.New("invalid reference")
+
+	// ErrUnknownResolver error is returned when reference inside configuration value uses unknown resolver.
+	ErrUnknownResolver = errors.New("unknown resolver")
+
+	// ErrUnresolvedReference error is returned when reference inside configuration value can't be resolved and it
+	// has no default value.
+	ErrUnresolvedReference = errors.New("unresolved reference")
+
+	// ErrReferenceCycle error is returned when references inside configuration values refer to each other in a cycle.
+	ErrReferenceCycle = errors.New("reference cycle")
+
+	// ErrNoKeyring error is returned when no encryption keys are provided in environment variables.
+	ErrNoKeyring = errors.New("no encryption keys")
+
+	// ErrInvalidKeyring error is returned when encryption keys are malformed.
+	ErrInvalidKeyring = errors.New("invalid encryption keys")
+
+	// ErrDecryption error is returned when encrypted configuration value can't be decrypted.
+	ErrDecryption = errors.New("can't decrypt value")
 )
@@ -9,6 +9,18 @@
 //	    panic(err)
 //	}
 //
+// If you need configuration to follow changes of the key without restarting the process, use Watcher instead:
+//
+//	watcher := etcd.MustNewWatcher(endpoints, key, encoder)
+//	go watcher.Run(ctx)
+//
+//	cfg := watcher.Config()
+//
+// If configuration is stored as individual keys under some prefix, like '/app/http/address' and '/app/redis/maxIdle',
+// use NewTreeConfig or NewTreeWatcher instead:
+//
+//	cfg := config.Must(etcd.NewTreeConfig(endpoints, "/app", nil))
+//
 // See config package for more details.
 package etcd
 
@@ -23,13 +35,38 @@ import (
 	"github.com/lightstar/golib/pkg/errors"
 )
 
-// ErrNoData error is returned when etcd doesn't have any configuration data by given key.
-var ErrNoData = errors.New("no data")
+var (
+	// ErrNoData error is returned when etcd doesn't have any configuration data by given key.
+	ErrNoData = errors.New("no data")
+
+	// ErrRevisionMismatch error is returned when key in etcd was modified after the revision it was expected to have.
+	ErrRevisionMismatch = errors.New("revision mismatch")
+
+	// ErrInvalidOption error is returned when some watcher option has invalid value, such as non-positive retry delay.
+	ErrInvalidOption = errors.New("invalid option")
+)
 
 // NewConfig function creates new configuration service using data stored in some key in etcd server and
 // chosen encoder.
 // Most likely you will use one of the predefined encoders: json.Encoder, yaml.Encoder or toml.Encoder.
 func NewConfig(endpoints []string, key string, encoder config.Encoder) (*config.Config, error) {
+	client, err := newClient(endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	defer client.Close()
+
+	resp, err := get(client, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.NewFromBytes(resp.Kvs[0].Value, encoder)
+}
+
+// newClient function creates new etcd client connected to provided endpoints.
+func newClient(endpoints []string) (*clientv3.Client, error) {
 	client, err := clientv3.New(clientv3.Config{
 		Endpoints:   endpoints,
 		DialTimeout: time.Second,
@@ -44,8 +81,11 @@ func NewConfig(endpoints []string, key string, encoder config.Encoder) (*config.
 		return nil, errors.NewFmt("etcd error (%s)", err.Error()).WithCause(err)
 	}
 
-	defer client.Close()
+	return client, nil
+}
 
+// get function retrieves provided key from etcd server. It returns ErrNoData if there is no such key.
+func get(client *clientv3.Client, key string) (*clientv3.GetResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
@@ -58,5 +98,5 @@ func NewConfig(endpoints []string, key string, encoder config.Encoder) (*config.
 		return nil, ErrNoData
 	}
 
-	return config.NewFromBytes(resp.Kvs[0].Value, encoder)
+	return resp, nil
 }
@@ -0,0 +1,62 @@
+package etcd
+
+import (
+	"context"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// Load function retrieves raw value of the key in etcd server along with its modification revision, which can be
+// passed to Store function later. It returns ErrNoData if there is no such key.
+func Load(endpoints []string, key string) ([]byte, int64, error) {
+	client, err := newClient(endpoints)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer client.Close()
+
+	resp, err := get(client, key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return resp.Kvs[0].Value, resp.Kvs[0].ModRevision, nil
+}
+
+// Store function writes raw value into the key in etcd server as compare-and-swap operation: the key is written only
+// if its modification revision is still the provided one, otherwise ErrRevisionMismatch is returned. Use revision 0
+// to write the key only if it doesn't exist yet, or negative revision to write it unconditionally.
+// It retrieves new modification revision of the key.
+func Store(endpoints []string, key string, value []byte, revision int64) (int64, error) {
+	client, err := newClient(endpoints)
+	if err != nil {
+		return 0, err
+	}
+
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	txn := client.Txn(ctx)
+
+	if revision >= 0 {
+		txn = txn.If(clientv3.Compare(clientv3.ModRevision(key), "=", revision))
+	}
+
+	resp, err := txn.Then(clientv3.OpPut(key, string(value))).Commit()
+	if err != nil {
+		return 0, errors.NewFmt("etcd error (%s)", err.Error()).WithCause(err)
+	}
+
+	if !resp.Succeeded {
+		return 0, errors.NewFmt("key '%s' was modified after revision %d", key, revision).
+			WithCause(ErrRevisionMismatch)
+	}
+
+	return resp.Header.Revision, nil
+}
@@ -0,0 +1,49 @@
+package etcd_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config/etcd"
+)
+
+const etcdStoreKey = "sample_store_config"
+
+func TestStore(t *testing.T) {
+	endpoints := os.Getenv("TEST_CONFIG_ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Log("provide 'TEST_CONFIG_ETCD_ENDPOINTS' environment variable to test etcd source")
+		return
+	}
+
+	defer configtest.CleanEtcd(t, etcdStoreKey)
+
+	_, _, err := etcd.Load(strings.Split(endpoints, ","), etcdStoreKey)
+	require.Same(t, etcd.ErrNoData, err)
+
+	revision, err := etcd.Store(strings.Split(endpoints, ","), etcdStoreKey, []byte(`{"name":"first"}`), 0)
+	require.NoError(t, err)
+	require.Positive(t, revision)
+
+	_, err = etcd.Store(strings.Split(endpoints, ","), etcdStoreKey, []byte(`{"name":"second"}`), 0)
+	require.ErrorIs(t, err, etcd.ErrRevisionMismatch)
+
+	value, loadedRevision, err := etcd.Load(strings.Split(endpoints, ","), etcdStoreKey)
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"first"}`, string(value))
+	require.Equal(t, revision, loadedRevision)
+
+	newRevision, err := etcd.Store(strings.Split(endpoints, ","), etcdStoreKey, []byte(`{"name":"second"}`), revision)
+	require.NoError(t, err)
+	require.Greater(t, newRevision, revision)
+
+	_, err = etcd.Store(strings.Split(endpoints, ","), etcdStoreKey, []byte(`{"name":"third"}`), revision)
+	require.ErrorIs(t, err, etcd.ErrRevisionMismatch)
+
+	_, err = etcd.Store(strings.Split(endpoints, ","), etcdStoreKey, []byte(`{"name":"third"}`), -1)
+	require.NoError(t, err)
+}
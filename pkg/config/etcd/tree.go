@@ -0,0 +1,151 @@
+package etcd
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/internal/flat"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// TreeSeparator is the separator of key segments inside etcd keys of prefix tree.
+const TreeSeparator = "/"
+
+// NewTreeConfig function creates new configuration service using all keys stored under some prefix in etcd server,
+// where each key is a separate configuration value, see BuildTree function. Use nil encoder for scalar values.
+func NewTreeConfig(endpoints []string, prefix string, encoder config.Encoder) (*config.Config, error) {
+	client, err := newClient(endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	defer client.Close()
+
+	resp, err := getTree(client, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := BuildTree(prefix, resp.Kvs, encoder)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.NewFromRaw(data), nil
+}
+
+// BuildTree function builds configuration data from etcd keys stored under some prefix. Key paths relative to the
+// prefix are split by '/', so with prefix '/app' key '/app/redis/maxIdle' becomes 'redis.maxIdle'. Segments that are
+// numbers turn maps into lists, so '/app/servers/0/address' becomes 'address' of the first element of 'servers' list.
+//
+// If encoder is nil, values are type-inferred scalars: int, float, bool or string. Otherwise, each value is a document
+// parsed with the encoder and deep-merged into its place, where key '/app/' holds the top-level document. Keys are
+// applied in lexical order, so values of deeper keys override ones from documents of their parents.
+func BuildTree(prefix string, kvs []*mvccpb.KeyValue, encoder config.Encoder) (map[string]interface{}, error) {
+	prefix = treePrefix(prefix)
+
+	sortedKvs := append([]*mvccpb.KeyValue(nil), kvs...)
+	sort.Slice(sortedKvs, func(i, j int) bool {
+		return string(sortedKvs[i].Key) < string(sortedKvs[j].Key)
+	})
+
+	data := make(map[string]interface{})
+	layers := make([]map[string]interface{}, 0, len(sortedKvs))
+
+	for _, kv := range sortedKvs {
+		key := string(kv.Key)
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		path := treePath(key[len(prefix):])
+
+		if encoder == nil {
+			if len(path) == 0 {
+				continue
+			}
+
+			if err := flat.Set(data, path, flat.Infer(string(kv.Value))); err != nil {
+				return nil, errors.NewFmt("etcd error (key '%s': %s)", key, err.Error())
+			}
+
+			continue
+		}
+
+		var value map[string]interface{}
+
+		if err := encoder.Encode(kv.Value, &value); err != nil {
+			return nil, errors.NewFmt("etcd error (key '%s': %s)", key, err.Error()).WithCause(err)
+		}
+
+		for i := len(path) - 1; i >= 0; i-- {
+			value = map[string]interface{}{path[i]: value}
+		}
+
+		layers = append(layers, value)
+	}
+
+	if encoder != nil {
+		data = config.MergeRaw(config.SliceReplace, layers...)
+	}
+
+	flat.NormalizeMap(data)
+
+	return data, nil
+}
+
+// treePrefix function normalizes prefix, so it always ends with separator and matches only keys inside the tree.
+func treePrefix(prefix string) string {
+	if !strings.HasSuffix(prefix, TreeSeparator) {
+		prefix += TreeSeparator
+	}
+
+	return prefix
+}
+
+// treePath function splits relative key into path segments skipping empty ones.
+func treePath(key string) []string {
+	var path []string
+
+	for _, segment := range strings.Split(key, TreeSeparator) {
+		if segment != "" {
+			path = append(path, segment)
+		}
+	}
+
+	return path
+}
+
+// getTree function retrieves all keys under provided prefix from etcd server. It returns ErrNoData if there are no
+// such keys.
+func getTree(client *clientv3.Client, prefix string) (*clientv3.GetResponse, error) {
+	resp, err := listTree(client, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Kvs) == 0 {
+		return nil, ErrNoData
+	}
+
+	return resp, nil
+}
+
+// listTree function retrieves all keys under provided prefix from etcd server, there can be none of them.
+func listTree(client *clientv3.Client, prefix string) (*clientv3.GetResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	resp, err := client.Get(ctx, treePrefix(prefix), clientv3.WithPrefix())
+	if err != nil {
+		return nil, errors.NewFmt("etcd error (%s)", err.Error()).WithCause(err)
+	}
+
+	return resp, nil
+}
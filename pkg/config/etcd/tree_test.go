@@ -0,0 +1,209 @@
+package etcd_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder/json"
+	"github.com/lightstar/golib/pkg/config/etcd"
+)
+
+const etcdTreePrefix = "/sample_tree_config"
+
+func treeKvs(values map[string]string) []*mvccpb.KeyValue {
+	kvs := make([]*mvccpb.KeyValue, 0, len(values))
+
+	for key, value := range values {
+		kvs = append(kvs, &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value)})
+	}
+
+	return kvs
+}
+
+func TestBuildTree(t *testing.T) {
+	data, err := etcd.BuildTree("/app", treeKvs(map[string]string{
+		"/app/http/address":     "0.0.0.0:80",
+		"/app/http/timeout":     "3",
+		"/app/redis/maxIdle":    "5",
+		"/app/redis/ratio":      "0.5",
+		"/app//debug":           "true",
+		"/app/servers/0/host":   "a",
+		"/app/servers/1/host":   "b",
+		"/application/ignored":  "1",
+		"/app/":                 "ignored",
+		"/other/app/redis/port": "1",
+	}), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"http":  map[string]interface{}{"address": "0.0.0.0:80", "timeout": 3},
+		"redis": map[string]interface{}{"maxIdle": 5, "ratio": 0.5},
+		"debug": true,
+		"servers": []interface{}{
+			map[string]interface{}{"host": "a"},
+			map[string]interface{}{"host": "b"},
+		},
+	}, data)
+
+	data, err = etcd.BuildTree("/app/", treeKvs(map[string]string{
+		"/app/":                `{"name": "app", "redis": {"address": "localhost:6379", "maxIdle": 1}}`,
+		"/app/redis":           `{"maxIdle": 5}`,
+		"/app/http":            `{"address": "0.0.0.0:80"}`,
+		"/app/http/tls/config": `{"enabled": true}`,
+	}), json.Encoder)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"name":  "app",
+		"redis": map[string]interface{}{"address": "localhost:6379", "maxIdle": float64(5)},
+		"http": map[string]interface{}{
+			"address": "0.0.0.0:80",
+			"tls":     map[string]interface{}{"config": map[string]interface{}{"enabled": true}},
+		},
+	}, data)
+}
+
+func TestBuildTreeErrors(t *testing.T) {
+	_, err := etcd.BuildTree("/app", treeKvs(map[string]string{
+		"/app/http":         "1",
+		"/app/http/address": "0.0.0.0:80",
+	}), nil)
+	require.ErrorContains(t, err, "etcd error (key '/app/http/address'")
+
+	_, err = etcd.BuildTree("/app", treeKvs(map[string]string{"/app/http": "1"}), json.Encoder)
+	require.ErrorContains(t, err, "json error")
+}
+
+func TestTree(t *testing.T) {
+	endpoints := os.Getenv("TEST_CONFIG_ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Log("provide 'TEST_CONFIG_ETCD_ENDPOINTS' environment variable to test etcd source")
+		return
+	}
+
+	_, err := etcd.NewTreeConfig(strings.Split(endpoints, ","), etcdTreePrefix, nil)
+	require.Same(t, etcd.ErrNoData, err)
+
+	configtest.PutEtcd(t, etcdTreePrefix+"/http/address", []byte("0.0.0.0:80"))
+	configtest.PutEtcd(t, etcdTreePrefix+"/redis/maxIdle", []byte("5"))
+	defer configtest.CleanEtcdPrefix(t, etcdTreePrefix+"/")
+
+	cfg, err := etcd.NewTreeConfig(strings.Split(endpoints, ","), etcdTreePrefix, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"http":  map[string]interface{}{"address": "0.0.0.0:80"},
+		"redis": map[string]interface{}{"maxIdle": 5},
+	}, cfg.GetRaw())
+}
+
+func TestTreeWatcher(t *testing.T) {
+	endpoints := os.Getenv("TEST_CONFIG_ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Log("provide 'TEST_CONFIG_ETCD_ENDPOINTS' environment variable to test etcd source")
+		return
+	}
+
+	configtest.PutEtcd(t, etcdTreePrefix+"/http/address", []byte("0.0.0.0:80"))
+	configtest.PutEtcd(t, etcdTreePrefix+"/redis/maxIdle", []byte("5"))
+	defer configtest.CleanEtcdPrefix(t, etcdTreePrefix+"/")
+
+	watcher, err := etcd.NewTreeWatcher(strings.Split(endpoints, ","), etcdTreePrefix, nil)
+	require.NoError(t, err)
+
+	revision := watcher.Revision()
+	require.Positive(t, revision)
+
+	base := config.NewFromRaw(map[string]interface{}{
+		"http":  map[string]interface{}{"address": "127.0.0.1:8080", "timeout": 3},
+		"redis": map[string]interface{}{"maxIdle": 1},
+	})
+	cfg := config.Merge(base, watcher.Config())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	go func() {
+		watcher.Run(ctx)
+		close(stopped)
+	}()
+
+	configtest.PutEtcd(t, etcdTreePrefix+"/redis/maxIdle", []byte("10"))
+
+	require.Eventually(t, func() bool {
+		value, err := cfg.GetRawByKey("redis.maxIdle")
+		return err == nil && value == 10
+	}, testWatchWait, testWatchTick)
+
+	configtest.CleanEtcd(t, etcdTreePrefix+"/http/address")
+
+	require.Eventually(t, func() bool {
+		value, err := cfg.GetRawByKey("http.address")
+		return err == nil && value == "127.0.0.1:8080"
+	}, testWatchWait, testWatchTick)
+
+	require.Greater(t, watcher.Revision(), revision)
+	require.Equal(t, map[string]interface{}{"redis": map[string]interface{}{"maxIdle": 10}},
+		watcher.Config().GetRaw())
+
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(testWatchWait):
+		require.FailNow(t, "watcher wasn't stopped")
+	}
+}
+
+func TestTreeWatcherReloadEmpty(t *testing.T) {
+	endpoints := os.Getenv("TEST_CONFIG_ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Log("provide 'TEST_CONFIG_ETCD_ENDPOINTS' environment variable to test etcd source")
+		return
+	}
+
+	configtest.PutEtcd(t, etcdTreePrefix+"/redis/maxIdle", []byte("5"))
+	defer configtest.CleanEtcdPrefix(t, etcdTreePrefix+"/")
+
+	var errs []error
+	var errsMu sync.Mutex
+
+	watcher, err := etcd.NewTreeWatcher(strings.Split(endpoints, ","), etcdTreePrefix, nil,
+		etcd.WithRetryDelay(10*time.Millisecond), etcd.WithErrorFunc(func(err error) {
+			errsMu.Lock()
+			defer errsMu.Unlock()
+
+			errs = append(errs, err)
+		}))
+	require.NoError(t, err)
+
+	configtest.CleanEtcdPrefix(t, etcdTreePrefix+"/")
+	configtest.PutEtcd(t, etcdTreePrefix+"_other", []byte("1"))
+	defer configtest.CleanEtcd(t, etcdTreePrefix+"_other")
+	configtest.CompactEtcd(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go watcher.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		return len(watcher.Config().GetRaw()) == 0
+	}, testWatchWait, testWatchTick)
+
+	errsMu.Lock()
+	defer errsMu.Unlock()
+
+	for _, err := range errs {
+		require.NotErrorIs(t, err, etcd.ErrNoData)
+	}
+}
@@ -0,0 +1,330 @@
+package etcd
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// Watcher structure that keeps configuration service in sync with some key in etcd server, or with all keys under some
+// prefix, see NewTreeWatcher function.
+// It holds long-lived etcd client and watches the key, re-parsing each new revision of it.
+// Don't create it manually, use the functions down below instead.
+//
+// Typical usage:
+//
+//	watcher := etcd.MustNewWatcher(endpoints, key, encoder, etcd.WithErrorFunc(logError))
+//	cfg := watcher.Config()
+//	cfg.Subscribe(func(cfg *config.Config) { ... })
+//	go watcher.Run(ctx)
+type Watcher struct {
+	client     *clientv3.Client
+	key        string
+	encoder    config.Encoder
+	config     *config.Config
+	retryDelay time.Duration
+	errorFn    func(error)
+	revision   atomic.Int64
+	watchRev   int64
+	isTree     bool
+	kvs        map[string]*mvccpb.KeyValue
+}
+
+// NewWatcher function creates new watcher of the key in etcd server with chosen encoder and provided options.
+// Configuration is read immediately, so any error with the key is returned right here.
+func NewWatcher(endpoints []string, key string, encoder config.Encoder, opts ...WatcherOption) (*Watcher, error) {
+	watcherConfig, err := buildWatcherConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newClient(endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := get(client, key)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	cfg, err := config.NewFromBytes(resp.Kvs[0].Value, encoder)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	watcher := &Watcher{
+		client:     client,
+		key:        key,
+		encoder:    encoder,
+		config:     cfg,
+		retryDelay: watcherConfig.retryDelay,
+		errorFn:    watcherConfig.errorFn,
+		watchRev:   resp.Header.Revision,
+	}
+
+	watcher.revision.Store(resp.Kvs[0].ModRevision)
+
+	return watcher, nil
+}
+
+// NewTreeWatcher function creates new watcher of all keys under the prefix in etcd server with chosen encoder and
+// provided options. Keys are turned into configuration data as described in BuildTree function, so use nil encoder
+// for scalar values. Each change of any key under the prefix rebuilds configuration data, while deleted keys just
+// disappear from it. When all keys are deleted, configuration data becomes empty.
+// Each batch of changes rebuilds and re-parses the whole tree, so keep the number of keys under the prefix moderate.
+// Configuration is read immediately, so any error with the keys is returned right here, and there must be at least
+// one key under the prefix.
+func NewTreeWatcher(endpoints []string, prefix string, encoder config.Encoder,
+	opts ...WatcherOption,
+) (*Watcher, error) {
+	watcherConfig, err := buildWatcherConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newClient(endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := getTree(client, prefix)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	data, err := BuildTree(prefix, resp.Kvs, encoder)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	watcher := &Watcher{
+		client:     client,
+		key:        treePrefix(prefix),
+		encoder:    encoder,
+		config:     config.NewFromRaw(data),
+		retryDelay: watcherConfig.retryDelay,
+		errorFn:    watcherConfig.errorFn,
+		watchRev:   resp.Header.Revision,
+		isTree:     true,
+	}
+
+	watcher.resetTree(resp.Kvs)
+
+	return watcher, nil
+}
+
+// MustNewTreeWatcher function creates new watcher of the prefix with provided options and panics on any error.
+func MustNewTreeWatcher(endpoints []string, prefix string, encoder config.Encoder, opts ...WatcherOption) *Watcher {
+	watcher, err := NewTreeWatcher(endpoints, prefix, encoder, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return watcher
+}
+
+// MustNewWatcher function creates new watcher with provided options and panics on any error.
+func MustNewWatcher(endpoints []string, key string, encoder config.Encoder, opts ...WatcherOption) *Watcher {
+	watcher, err := NewWatcher(endpoints, key, encoder, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return watcher
+}
+
+// Config method gets configuration service that is kept in sync with the key.
+func (watcher *Watcher) Config() *config.Config {
+	return watcher.config
+}
+
+// Revision method gets etcd modification revision of the key that current configuration data was obtained from.
+// For watcher of the prefix it is the latest modification revision of all keys under it.
+func (watcher *Watcher) Revision() int64 {
+	return watcher.revision.Load()
+}
+
+// Run method runs watch loop. It is blocking so you probably want to run it in a separate goroutine.
+// If watch is broken, for example when etcd cluster loses its leader or watched revision is compacted, the key is
+// re-read and watch is re-established after retry delay.
+// Pass cancellable context here to stop watching. Etcd client is closed when this method returns.
+func (watcher *Watcher) Run(ctx context.Context) {
+	defer watcher.client.Close()
+
+	for {
+		watcher.watch(ctx)
+
+		select {
+		case <-time.After(watcher.retryDelay):
+		case <-ctx.Done():
+			return
+		}
+
+		if err := watcher.reload(); err != nil {
+			watcher.reportError(err)
+		}
+	}
+}
+
+// watch method watches the key until watch is broken or context is canceled.
+func (watcher *Watcher) watch(ctx context.Context) {
+	watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+
+	watchOpts := []clientv3.OpOption{clientv3.WithRev(watcher.watchRev + 1)}
+	if watcher.isTree {
+		watchOpts = append(watchOpts, clientv3.WithPrefix())
+	}
+
+	watchChan := watcher.client.Watch(watchCtx, watcher.key, watchOpts...)
+
+	for resp := range watchChan {
+		if err := resp.Err(); err != nil {
+			watcher.reportError(errors.NewFmt("etcd error (%s)", err.Error()).WithCause(err))
+			return
+		}
+
+		if watcher.isTree {
+			watcher.applyTreeEvents(resp.Events)
+			continue
+		}
+
+		for _, event := range resp.Events {
+			watcher.watchRev = event.Kv.ModRevision
+
+			if event.Type == mvccpb.DELETE {
+				watcher.reportError(ErrNoData)
+				continue
+			}
+
+			watcher.apply(event.Kv.Value, event.Kv.ModRevision)
+		}
+	}
+}
+
+// reload method reads the key directly, it is used to catch up with changes after watch was broken.
+func (watcher *Watcher) reload() error {
+	if watcher.isTree {
+		return watcher.reloadTree()
+	}
+
+	resp, err := get(watcher.client, watcher.key)
+	if err != nil {
+		return err
+	}
+
+	watcher.watchRev = resp.Header.Revision
+
+	if resp.Kvs[0].ModRevision != watcher.Revision() {
+		watcher.apply(resp.Kvs[0].Value, resp.Kvs[0].ModRevision)
+	}
+
+	return nil
+}
+
+// apply method replaces configuration data with provided one if it is parsed successfully.
+func (watcher *Watcher) apply(value []byte, revision int64) {
+	var data map[string]interface{}
+
+	if err := watcher.encoder.Encode(value, &data); err != nil {
+		watcher.reportError(err)
+		return
+	}
+
+	watcher.revision.Store(revision)
+	watcher.config.Update(data)
+}
+
+// reportError method passes error to the error function if it is set.
+func (watcher *Watcher) reportError(err error) {
+	if watcher.errorFn != nil {
+		watcher.errorFn(err)
+	}
+}
+
+// applyTreeEvents method applies changes of keys under the prefix and rebuilds configuration data.
+func (watcher *Watcher) applyTreeEvents(events []*clientv3.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	for _, event := range events {
+		watcher.watchRev = event.Kv.ModRevision
+
+		if event.Type == mvccpb.DELETE {
+			delete(watcher.kvs, string(event.Kv.Key))
+		} else {
+			watcher.kvs[string(event.Kv.Key)] = event.Kv
+		}
+	}
+
+	watcher.applyTree(events[len(events)-1].Kv.ModRevision)
+}
+
+// reloadTree method reads all keys under the prefix directly and rebuilds configuration data. Same as with watch
+// events, absence of keys makes configuration data empty, at the revision of the read.
+func (watcher *Watcher) reloadTree() error {
+	resp, err := listTree(watcher.client, watcher.key)
+	if err != nil {
+		return err
+	}
+
+	watcher.watchRev = resp.Header.Revision
+	watcher.resetTree(resp.Kvs)
+
+	revision := watcher.Revision()
+	if len(resp.Kvs) == 0 {
+		revision = resp.Header.Revision
+	}
+
+	watcher.applyTree(revision)
+
+	return nil
+}
+
+// resetTree method replaces known keys under the prefix and sets revision to the latest modification revision of them.
+func (watcher *Watcher) resetTree(kvs []*mvccpb.KeyValue) {
+	watcher.kvs = make(map[string]*mvccpb.KeyValue, len(kvs))
+
+	var revision int64
+
+	for _, kv := range kvs {
+		watcher.kvs[string(kv.Key)] = kv
+
+		if kv.ModRevision > revision {
+			revision = kv.ModRevision
+		}
+	}
+
+	watcher.revision.Store(revision)
+}
+
+// applyTree method rebuilds configuration data from known keys under the prefix if they are parsed successfully.
+// The whole tree is rebuilt and all values are re-parsed on every call, as merging of documents and turning maps into
+// lists depend on all keys at once.
+func (watcher *Watcher) applyTree(revision int64) {
+	kvs := make([]*mvccpb.KeyValue, 0, len(watcher.kvs))
+	for _, kv := range watcher.kvs {
+		kvs = append(kvs, kv)
+	}
+
+	data, err := BuildTree(watcher.key, kvs, watcher.encoder)
+	if err != nil {
+		watcher.reportError(err)
+		return
+	}
+
+	watcher.revision.Store(revision)
+	watcher.config.Update(data)
+}
@@ -0,0 +1,57 @@
+package etcd
+
+import (
+	"time"
+
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// DefRetryDelay is the default delay before watch is re-established after it was broken.
+const DefRetryDelay = time.Second
+
+// WatcherConfig structure with watcher configuration. Shouldn't be created manually.
+type WatcherConfig struct {
+	retryDelay time.Duration
+	errorFn    func(error)
+}
+
+// WatcherOption function that is fed to NewWatcher and MustNewWatcher. Obtain them using 'With' functions down below.
+type WatcherOption func(*WatcherConfig) error
+
+// WithRetryDelay option applies provided delay before watch is re-established after it was broken, for example
+// because of compaction of watched revision. It must be positive. Default: 1s.
+func WithRetryDelay(retryDelay time.Duration) WatcherOption {
+	return func(cfg *WatcherConfig) error {
+		if retryDelay <= 0 {
+			return errors.NewFmt("retry delay must be positive, got %s", retryDelay).WithCause(ErrInvalidOption)
+		}
+
+		cfg.retryDelay = retryDelay
+
+		return nil
+	}
+}
+
+// WithErrorFunc option applies provided function that will be called on any error occurred while watching
+// the key, such as parse error of the new revision. Default: none, errors are silently ignored.
+func WithErrorFunc(errorFn func(error)) WatcherOption {
+	return func(cfg *WatcherConfig) error {
+		cfg.errorFn = errorFn
+		return nil
+	}
+}
+
+// buildWatcherConfig function builds watcher configuration using list of provided options.
+func buildWatcherConfig(opts []WatcherOption) (*WatcherConfig, error) {
+	cfg := &WatcherConfig{
+		retryDelay: DefRetryDelay,
+	}
+
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,129 @@
+package etcd_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder/json"
+	"github.com/lightstar/golib/pkg/config/etcd"
+)
+
+const (
+	etcdWatchKey  = "sample_watch_config"
+	testWatchWait = 3 * time.Second
+	testWatchTick = 10 * time.Millisecond
+)
+
+func TestWatcher(t *testing.T) {
+	endpoints := os.Getenv("TEST_CONFIG_ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Log("provide 'TEST_CONFIG_ETCD_ENDPOINTS' environment variable to test etcd source")
+		return
+	}
+
+	configtest.SetupEtcd(t, etcdWatchKey)
+	defer configtest.CleanEtcd(t, etcdWatchKey)
+
+	var mu sync.Mutex
+	var errs []error
+
+	watcher, err := etcd.NewWatcher(strings.Split(endpoints, ","), etcdWatchKey, json.Encoder,
+		etcd.WithErrorFunc(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			errs = append(errs, err)
+		}),
+	)
+	require.NoError(t, err)
+
+	cfg := watcher.Config()
+	configtest.TestSampleConfig(t, cfg, configtest.ExpectedSampleRawDataJSON)
+
+	revision := watcher.Revision()
+	require.Positive(t, revision)
+
+	updated := make(chan struct{}, 1)
+	cfg.Subscribe(func(*config.Config) {
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	go func() {
+		watcher.Run(ctx)
+		close(stopped)
+	}()
+
+	configtest.PutEtcd(t, etcdWatchKey, []byte(`{"name":"George"}`))
+
+	select {
+	case <-updated:
+	case <-time.After(testWatchWait):
+		require.FailNow(t, "configuration wasn't reloaded")
+	}
+
+	require.Equal(t, map[string]interface{}{"name": "George"}, cfg.GetRaw())
+	require.Greater(t, watcher.Revision(), revision)
+
+	configtest.SetupEtcdWrong(t, etcdWatchKey)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(errs) > 0
+	}, testWatchWait, testWatchTick)
+
+	require.Equal(t, map[string]interface{}{"name": "George"}, cfg.GetRaw())
+
+	mu.Lock()
+	require.ErrorContains(t, errs[0], "json error")
+	mu.Unlock()
+
+	cancel()
+	<-stopped
+}
+
+func TestWatcherErrors(t *testing.T) {
+	endpoints := os.Getenv("TEST_CONFIG_ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Log("provide 'TEST_CONFIG_ETCD_ENDPOINTS' environment variable to test etcd source")
+		return
+	}
+
+	_, err := etcd.NewWatcher(strings.Split(endpoints, ","), etcdWatchKey, json.Encoder)
+	require.Same(t, etcd.ErrNoData, err)
+
+	require.Panics(t, func() {
+		_ = etcd.MustNewWatcher(strings.Split(endpoints, ","), etcdWatchKey, json.Encoder)
+	})
+
+	configtest.SetupEtcdWrong(t, etcdWatchKey)
+	defer configtest.CleanEtcd(t, etcdWatchKey)
+
+	_, err = etcd.NewWatcher(strings.Split(endpoints, ","), etcdWatchKey, json.Encoder)
+	require.ErrorContains(t, err, "json error")
+}
+
+func TestWatcherOptionErrors(t *testing.T) {
+	for _, retryDelay := range []time.Duration{0, -time.Second} {
+		_, err := etcd.NewWatcher(nil, etcdWatchKey, json.Encoder, etcd.WithRetryDelay(retryDelay))
+		require.ErrorIs(t, err, etcd.ErrInvalidOption)
+
+		_, err = etcd.NewTreeWatcher(nil, etcdWatchKey, json.Encoder, etcd.WithRetryDelay(retryDelay))
+		require.ErrorIs(t, err, etcd.ErrInvalidOption)
+	}
+}
@@ -0,0 +1,16 @@
+package file
+
+import (
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+var (
+	// ErrIncludeCycle error is returned when included files include each other in a cycle.
+	ErrIncludeCycle = errors.New("include cycle")
+
+	// ErrInvalidInclude error is returned when include directive doesn't contain file name or list of file names.
+	ErrInvalidInclude = errors.New("invalid include directive")
+
+	// ErrInvalidOption error is returned when some watcher option has invalid value, such as non-positive interval.
+	ErrInvalidOption = errors.New("invalid option")
+)
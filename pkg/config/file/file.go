@@ -9,23 +9,30 @@
 //	    panic(err)
 //	}
 //
+// If you need configuration to follow changes of the file without restarting the process, use Watcher instead:
+//
+//	watcher := file.MustNewWatcher(fileName, encoder)
+//	go watcher.Run(ctx)
+//
+//	cfg := watcher.Config()
+//
+// Files can include other files with '$include' directive, see Load function.
+//
 // See config package for more details.
 package file
 
 import (
-	"os"
-
 	"github.com/lightstar/golib/pkg/config"
-	"github.com/lightstar/golib/pkg/errors"
 )
 
 // NewConfig function creates new configuration service using data in some file as a source and chosen encoder.
 // Most likely you will use one of the predefined encoders: json.Encoder, yaml.Encoder or toml.Encoder.
+// Include directives inside the file are processed, see Load function.
 func NewConfig(name string, encoder config.Encoder) (*config.Config, error) {
-	dataBytes, err := os.ReadFile(name)
+	data, err := Load(name, encoder)
 	if err != nil {
-		return nil, errors.NewFmt("can't read from file '%s' (%s)", name, err.Error()).WithCause(err)
+		return nil, err
 	}
 
-	return config.NewFromBytes(dataBytes, encoder)
+	return config.NewFromRaw(data), nil
 }
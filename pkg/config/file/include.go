@@ -0,0 +1,193 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// IncludeKey is the key of the directive that includes other files into configuration data, see Load function.
+const IncludeKey = "$include"
+
+// Load function reads file with chosen encoder into raw configuration data processing include directives.
+//
+// Any map inside configuration data, including the top-level one, can contain '$include' key with file name or list
+// of file names. Those files are parsed with the same encoder and deep-merged in order, then keys of the map itself
+// are merged over them, so the including file has precedence. Relative names are resolved against directory of the
+// including file. Included files can include other ones, but cycles are reported as ErrIncludeCycle.
+//
+//	$include: [base.yaml, secrets.yaml]
+//	http:
+//	  $include: http.yaml
+//	  address: 0.0.0.0:80
+func Load(name string, encoder config.Encoder) (map[string]interface{}, error) {
+	data, _, err := loadWithFiles(name, encoder)
+
+	return data, err
+}
+
+// loadWithFiles function does the same as Load function, but also retrieves absolute names of all read files, the
+// file itself goes first.
+func loadWithFiles(name string, encoder config.Encoder) (map[string]interface{}, []string, error) {
+	var files []string
+
+	data, err := load(name, encoder, nil, &files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, files, nil
+}
+
+// load function reads file processing include directives. Stack contains absolute names of the files being loaded,
+// and absolute names of all read files are appended to 'files'.
+func load(name string, encoder config.Encoder, stack []string, files *[]string) (map[string]interface{}, error) {
+	absName, err := filepath.Abs(name)
+	if err != nil {
+		return nil, errors.NewFmt("can't resolve path of file '%s' (%s)", name, err.Error()).WithCause(err)
+	}
+
+	for i, stackName := range stack {
+		if stackName == absName {
+			cycle := append(append([]string{}, stack[i:]...), absName)
+
+			return nil, errors.NewFmt("include cycle '%s'", strings.Join(cycle, " -> ")).WithCause(ErrIncludeCycle)
+		}
+	}
+
+	dataBytes, err := os.ReadFile(name)
+	if err != nil {
+		return nil, errors.NewFmt("can't read from file '%s' (%s)", name, err.Error()).WithCause(err)
+	}
+
+	*files = append(*files, absName)
+
+	var data map[string]interface{}
+
+	if err = encoder.Encode(dataBytes, &data); err != nil {
+		return nil, err
+	}
+
+	return includeFiles(data, filepath.Dir(absName), encoder, append(stack, absName), files)
+}
+
+// includeFiles function processes include directives in the map and all maps inside it.
+func includeFiles(data map[string]interface{}, dir string, encoder config.Encoder,
+	stack []string, files *[]string,
+) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	result := make(map[string]interface{}, len(data))
+
+	for _, key := range keys {
+		if key == IncludeKey {
+			continue
+		}
+
+		value, err := includeValue(data[key], dir, encoder, stack, files)
+		if err != nil {
+			return nil, err
+		}
+
+		result[key] = value
+	}
+
+	include, ok := data[IncludeKey]
+	if !ok {
+		return result, nil
+	}
+
+	names, err := includeNames(include)
+	if err != nil {
+		return nil, err
+	}
+
+	layers := make([]map[string]interface{}, 0, len(names)+1)
+
+	for _, name := range names {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+
+		included, err := load(name, encoder, stack, files)
+		if err != nil {
+			return nil, err
+		}
+
+		layers = append(layers, included)
+	}
+
+	return config.MergeRaw(config.SliceReplace, append(layers, result)...), nil
+}
+
+// includeValue function processes include directives inside maps lying in the value.
+func includeValue(value interface{}, dir string, encoder config.Encoder, stack []string,
+	files *[]string,
+) (interface{}, error) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		return includeFiles(typedValue, dir, encoder, stack, files)
+	case []interface{}:
+		result := make([]interface{}, 0, len(typedValue))
+
+		for _, elem := range typedValue {
+			elem, err := includeValue(elem, dir, encoder, stack, files)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, elem)
+		}
+
+		return result, nil
+	case []map[string]interface{}:
+		result := make([]map[string]interface{}, 0, len(typedValue))
+
+		for _, elem := range typedValue {
+			elem, err := includeFiles(elem, dir, encoder, stack, files)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, elem)
+		}
+
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+// includeNames function retrieves list of file names from include directive value.
+func includeNames(include interface{}) ([]string, error) {
+	switch typedInclude := include.(type) {
+	case string:
+		return []string{typedInclude}, nil
+	case []interface{}:
+		names := make([]string, 0, len(typedInclude))
+
+		for _, elem := range typedInclude {
+			name, ok := elem.(string)
+			if !ok {
+				return nil, errors.NewFmt("'%s' directive must contain file names", IncludeKey).
+					WithCause(ErrInvalidInclude)
+			}
+
+			names = append(names, name)
+		}
+
+		return names, nil
+	default:
+		return nil, errors.NewFmt("'%s' directive must contain file name or list of file names", IncludeKey).
+			WithCause(ErrInvalidInclude)
+	}
+}
@@ -0,0 +1,91 @@
+package file_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/iotest"
+	"github.com/lightstar/golib/pkg/config/encoder/yaml"
+	"github.com/lightstar/golib/pkg/config/file"
+)
+
+func TestInclude(t *testing.T) {
+	dir := t.TempDir()
+
+	iotest.WriteFile(t, filepath.Join(dir, "config.yaml"), []byte(`
+$include: [base.yaml, extra/secrets.yaml]
+name: app
+http:
+  $include: http.yaml
+  address: 0.0.0.0:80
+servers:
+  - $include: server.yaml
+    port: 81
+`))
+	iotest.WriteFile(t, filepath.Join(dir, "base.yaml"), []byte(`
+name: base
+debug: true
+http:
+  timeout: 3
+`))
+	iotest.WriteFile(t, filepath.Join(dir, "extra/secrets.yaml"), []byte(`
+$include: ../password.yaml
+token: abc
+`))
+	iotest.WriteFile(t, filepath.Join(dir, "password.yaml"), []byte("password: 123\n"))
+	iotest.WriteFile(t, filepath.Join(dir, "http.yaml"), []byte("address: 127.0.0.1:8080\nidle: 60\n"))
+	iotest.WriteFile(t, filepath.Join(dir, "server.yaml"), []byte("host: localhost\nport: 80\n"))
+
+	cfg, err := file.NewConfig(filepath.Join(dir, "config.yaml"), yaml.Encoder)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"name":     "app",
+		"debug":    true,
+		"token":    "abc",
+		"password": 123,
+		"http": map[string]interface{}{
+			"address": "0.0.0.0:80",
+			"timeout": 3,
+			"idle":    60,
+		},
+		"servers": []interface{}{
+			map[string]interface{}{"host": "localhost", "port": 81},
+		},
+	}, cfg.GetRaw())
+}
+
+func TestIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	iotest.WriteFile(t, filepath.Join(dir, "a.yaml"), []byte("$include: b.yaml\n"))
+	iotest.WriteFile(t, filepath.Join(dir, "b.yaml"), []byte("$include: [c.yaml, a.yaml]\n"))
+	iotest.WriteFile(t, filepath.Join(dir, "c.yaml"), []byte("name: c\n"))
+
+	_, err := file.NewConfig(filepath.Join(dir, "a.yaml"), yaml.Encoder)
+	require.ErrorIs(t, err, file.ErrIncludeCycle)
+	require.ErrorContains(t, err, "a.yaml -> ")
+
+	iotest.WriteFile(t, filepath.Join(dir, "self.yaml"), []byte("http:\n  $include: self.yaml\n"))
+
+	_, err = file.NewConfig(filepath.Join(dir, "self.yaml"), yaml.Encoder)
+	require.ErrorIs(t, err, file.ErrIncludeCycle)
+
+	iotest.WriteFile(t, filepath.Join(dir, "invalid.yaml"), []byte("$include: 5\n"))
+
+	_, err = file.NewConfig(filepath.Join(dir, "invalid.yaml"), yaml.Encoder)
+	require.ErrorIs(t, err, file.ErrInvalidInclude)
+
+	iotest.WriteFile(t, filepath.Join(dir, "missing.yaml"), []byte("$include: unknown.yaml\n"))
+
+	_, err = file.NewConfig(filepath.Join(dir, "missing.yaml"), yaml.Encoder)
+	require.ErrorContains(t, err, "can't read from file")
+
+	iotest.WriteFile(t, filepath.Join(dir, "twice.yaml"), []byte("$include: [c.yaml, c.yaml]\n"))
+
+	cfg, err := file.NewConfig(filepath.Join(dir, "twice.yaml"), yaml.Encoder)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"name": "c"}, cfg.GetRaw())
+}
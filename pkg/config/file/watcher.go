@@ -0,0 +1,172 @@
+package file
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// Watcher structure that keeps configuration service in sync with the file on disk.
+// It polls inode, size and modification time of the file and all files it includes, and re-parses the file when any of
+// them changes. The set of included files is refreshed after each successful reload.
+// Don't create it manually, use the functions down below instead.
+//
+// Typical usage:
+//
+//	watcher := file.MustNewWatcher(fileName, encoder, file.WithInterval(5*time.Second))
+//	cfg := watcher.Config()
+//	cfg.Subscribe(func(cfg *config.Config) { ... })
+//	go watcher.Run(ctx)
+type Watcher struct {
+	name     string
+	encoder  config.Encoder
+	config   *config.Config
+	interval time.Duration
+	errorFn  func(error)
+	files    []watchedFile
+}
+
+// watchedFile structure holds information about one of the watched files obtained at the last successful reload.
+type watchedFile struct {
+	name string
+	info os.FileInfo
+}
+
+// NewWatcher function creates new watcher of the file with chosen encoder and provided options. Files included with
+// '$include' directive are watched too, see Load function.
+// Configuration is read immediately, so any error with the file is returned right here.
+func NewWatcher(name string, encoder config.Encoder, opts ...WatcherOption) (*Watcher, error) {
+	watcherConfig, err := buildWatcherConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfo, err := os.Stat(name)
+	if err != nil {
+		return nil, errors.NewFmt("can't stat file '%s' (%s)", name, err.Error()).WithCause(err)
+	}
+
+	data, names, err := loadWithFiles(name, encoder)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := statFiles(names, map[string]os.FileInfo{names[0]: fileInfo})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{
+		name:     name,
+		encoder:  encoder,
+		config:   config.NewFromRaw(data),
+		interval: watcherConfig.interval,
+		errorFn:  watcherConfig.errorFn,
+		files:    files,
+	}, nil
+}
+
+// MustNewWatcher function creates new watcher with provided options and panics on any error.
+func MustNewWatcher(name string, encoder config.Encoder, opts ...WatcherOption) *Watcher {
+	watcher, err := NewWatcher(name, encoder, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return watcher
+}
+
+// Config method gets configuration service that is kept in sync with the file.
+func (watcher *Watcher) Config() *config.Config {
+	return watcher.config
+}
+
+// Run method runs watch loop. It is blocking so you probably want to run it in a separate goroutine.
+// Pass cancellable context here to stop watching.
+func (watcher *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(watcher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := watcher.check(); err != nil && watcher.errorFn != nil {
+				watcher.errorFn(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check method reloads configuration file if it or any included file was changed since the last successful reload.
+// File that can't be parsed, for example because it is half-written, is re-read on every check until it is parsed
+// successfully. Included file that disappeared also causes reload, so it fails only if the file is still included.
+func (watcher *Watcher) check() error {
+	current := make(map[string]os.FileInfo, len(watcher.files))
+	changed := false
+
+	for i, file := range watcher.files {
+		fileInfo, err := os.Stat(file.name)
+		if err != nil {
+			if i == 0 {
+				return errors.NewFmt("can't stat file '%s' (%s)", watcher.name, err.Error()).WithCause(err)
+			}
+
+			changed = true
+
+			continue
+		}
+
+		current[file.name] = fileInfo
+
+		if !os.SameFile(file.info, fileInfo) || file.info.Size() != fileInfo.Size() ||
+			!file.info.ModTime().Equal(fileInfo.ModTime()) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	data, names, err := loadWithFiles(watcher.name, watcher.encoder)
+	if err != nil {
+		return err
+	}
+
+	files, err := statFiles(names, current)
+	if err != nil {
+		return err
+	}
+
+	watcher.files = files
+	watcher.config.Update(data)
+
+	return nil
+}
+
+// statFiles function retrieves information about provided files. Already known information is reused, so changes
+// made after it was obtained are detected by the next check.
+func statFiles(names []string, known map[string]os.FileInfo) ([]watchedFile, error) {
+	files := make([]watchedFile, 0, len(names))
+
+	for _, name := range names {
+		fileInfo, ok := known[name]
+		if !ok {
+			var err error
+
+			fileInfo, err = os.Stat(name)
+			if err != nil {
+				return nil, errors.NewFmt("can't stat file '%s' (%s)", name, err.Error()).WithCause(err)
+			}
+		}
+
+		files = append(files, watchedFile{name: name, info: fileInfo})
+	}
+
+	return files, nil
+}
@@ -0,0 +1,57 @@
+package file
+
+import (
+	"time"
+
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// DefWatchInterval is the default interval between checks of configuration file for changes.
+const DefWatchInterval = time.Second
+
+// WatcherConfig structure with watcher configuration. Shouldn't be created manually.
+type WatcherConfig struct {
+	interval time.Duration
+	errorFn  func(error)
+}
+
+// WatcherOption function that is fed to NewWatcher and MustNewWatcher. Obtain them using 'With' functions down below.
+type WatcherOption func(*WatcherConfig) error
+
+// WithInterval option applies provided interval between checks of configuration file for changes. It must be
+// positive. Default: 1s.
+func WithInterval(interval time.Duration) WatcherOption {
+	return func(cfg *WatcherConfig) error {
+		if interval <= 0 {
+			return errors.NewFmt("interval must be positive, got %s", interval).WithCause(ErrInvalidOption)
+		}
+
+		cfg.interval = interval
+
+		return nil
+	}
+}
+
+// WithErrorFunc option applies provided function that will be called on any error occurred while reloading
+// configuration file, such as parse error of the new content. Default: none, errors are silently ignored.
+func WithErrorFunc(errorFn func(error)) WatcherOption {
+	return func(cfg *WatcherConfig) error {
+		cfg.errorFn = errorFn
+		return nil
+	}
+}
+
+// buildWatcherConfig function builds watcher configuration using list of provided options.
+func buildWatcherConfig(opts []WatcherOption) (*WatcherConfig, error) {
+	cfg := &WatcherConfig{
+		interval: DefWatchInterval,
+	}
+
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
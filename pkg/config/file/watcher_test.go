@@ -0,0 +1,171 @@
+package file_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/internal/test/iotest"
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder/json"
+	"github.com/lightstar/golib/pkg/config/file"
+)
+
+const (
+	testWatchConfigPath   = "../../test/config_watch"
+	testWatchIncludedPath = "../../test/config_watch_included"
+	testWatchInterval     = 10 * time.Millisecond
+	testWatchWait         = time.Second
+)
+
+func TestWatcher(t *testing.T) {
+	iotest.WriteFile(t, testWatchConfigPath, configtest.SampleConfigDataJSON)
+	defer iotest.RemoveFile(t, testWatchConfigPath)
+
+	var mu sync.Mutex
+	var errs []error
+
+	watcher, err := file.NewWatcher(testWatchConfigPath, json.Encoder,
+		file.WithInterval(testWatchInterval),
+		file.WithErrorFunc(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			errs = append(errs, err)
+		}),
+	)
+	require.NoError(t, err)
+
+	cfg := watcher.Config()
+	configtest.TestSampleConfig(t, cfg, configtest.ExpectedSampleRawDataJSON)
+
+	updated := make(chan struct{}, 1)
+	cfg.Subscribe(func(*config.Config) {
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	go func() {
+		watcher.Run(ctx)
+		close(stopped)
+	}()
+
+	iotest.WriteFile(t, testWatchConfigPath, []byte(`{"name":"George"}`))
+
+	select {
+	case <-updated:
+	case <-time.After(testWatchWait):
+		require.FailNow(t, "configuration wasn't reloaded")
+	}
+
+	require.Equal(t, map[string]interface{}{"name": "George"}, cfg.GetRaw())
+
+	iotest.WriteFile(t, testWatchConfigPath, configtest.SampleConfigDataWrongJSON)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(errs) > 0
+	}, testWatchWait, testWatchInterval)
+
+	require.Equal(t, map[string]interface{}{"name": "George"}, cfg.GetRaw())
+
+	mu.Lock()
+	require.ErrorContains(t, errs[0], "json error")
+	mu.Unlock()
+
+	wrongInfo, err := os.Stat(testWatchConfigPath)
+	require.NoError(t, err)
+
+	fixed := []byte(`{"name":"Olivia"}`)
+	fixed = append(fixed, bytes.Repeat([]byte(" "), int(wrongInfo.Size())-len(fixed))...)
+
+	iotest.WriteFile(t, testWatchConfigPath, fixed)
+	require.NoError(t, os.Chtimes(testWatchConfigPath, wrongInfo.ModTime(), wrongInfo.ModTime()))
+
+	select {
+	case <-updated:
+	case <-time.After(testWatchWait):
+		require.FailNow(t, "configuration wasn't reloaded after the file was fixed")
+	}
+
+	require.Equal(t, map[string]interface{}{"name": "Olivia"}, cfg.GetRaw())
+
+	cancel()
+	<-stopped
+}
+
+func TestWatcherErrors(t *testing.T) {
+	_, err := file.NewWatcher(testWatchConfigPath, json.Encoder)
+	require.ErrorContains(t, err, "can't stat file")
+
+	require.Panics(t, func() {
+		_ = file.MustNewWatcher(testWatchConfigPath, json.Encoder)
+	})
+
+	iotest.WriteFile(t, testWatchConfigPath, configtest.SampleConfigDataWrongJSON)
+	defer iotest.RemoveFile(t, testWatchConfigPath)
+
+	_, err = file.NewWatcher(testWatchConfigPath, json.Encoder)
+	require.ErrorContains(t, err, "json error")
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		_, err = file.NewWatcher(testWatchConfigPath, json.Encoder, file.WithInterval(interval))
+		require.ErrorIs(t, err, file.ErrInvalidOption)
+	}
+}
+
+func TestWatcherIncludedFiles(t *testing.T) {
+	iotest.WriteFile(t, testWatchIncludedPath, []byte(`{"name":"George"}`))
+	defer iotest.RemoveFile(t, testWatchIncludedPath)
+
+	iotest.WriteFile(t, testWatchConfigPath, []byte(`{"$include":"config_watch_included","age":25}`))
+	defer iotest.RemoveFile(t, testWatchConfigPath)
+
+	watcher, err := file.NewWatcher(testWatchConfigPath, json.Encoder, file.WithInterval(testWatchInterval))
+	require.NoError(t, err)
+
+	cfg := watcher.Config()
+	require.Equal(t, map[string]interface{}{"name": "George", "age": float64(25)}, cfg.GetRaw())
+
+	updated := make(chan struct{}, 1)
+	cfg.Subscribe(func(*config.Config) {
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	go func() {
+		watcher.Run(ctx)
+		close(stopped)
+	}()
+
+	iotest.WriteFile(t, testWatchIncludedPath, []byte(`{"name":"Olivia"}`))
+
+	select {
+	case <-updated:
+	case <-time.After(testWatchWait):
+		require.FailNow(t, "configuration wasn't reloaded after the included file was changed")
+	}
+
+	require.Equal(t, map[string]interface{}{"name": "Olivia", "age": float64(25)}, cfg.GetRaw())
+
+	cancel()
+	<-stopped
+}
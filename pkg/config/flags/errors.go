@@ -0,0 +1,13 @@
+package flags
+
+import (
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+var (
+	// ErrInvalidFlag error is returned when command-line flag is malformed or its key conflicts with other one.
+	ErrInvalidFlag = errors.New("invalid flag")
+
+	// ErrUnexpectedArgument error is returned when command-line arguments contain something other than flags.
+	ErrUnexpectedArgument = errors.New("unexpected argument")
+)
@@ -0,0 +1,185 @@
+// Package flags allows overriding configuration values with command-line flags such as '--http.address=0.0.0.0:80'.
+// Flags are applied at the highest precedence, above configuration sources and environment variables.
+//
+// Typical usage:
+//
+//	cfg, err := flags.NewConfig(baseCfg, os.Args[1:], AppConfig{})
+//	if errors.Is(err, flag.ErrHelp) {
+//	    os.Exit(0)
+//	}
+//
+// Here AppConfig is the structure describing all configuration keys, so '--help' lists them with their defaults and
+// descriptions, see schema package for the tags used. Use nil description to accept any key.
+package flags
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/internal/flat"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// NewConfig function creates new configuration service with data of provided one, where values are overridden by
+// command-line flags from provided arguments, usually os.Args[1:].
+//
+// If description is nil, any '--key.path=value' flag is accepted, see Parse function. Otherwise, flags are defined by
+// the structure, see NewFlagSet function, and flag.ErrHelp is returned after usage is printed when '--help' flag is
+// provided. Arguments that are not flags are not allowed in both cases.
+//
+// Created configuration service follows updates of the provided one.
+func NewConfig(cfg *config.Config, args []string, description interface{}) (*config.Config, error) {
+	var overrides map[string]interface{}
+	var rest []string
+
+	if description == nil {
+		values, restArgs, err := Parse(args)
+		if err != nil {
+			return nil, err
+		}
+
+		overrides, rest = make(map[string]interface{}), restArgs
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if err = setValue(overrides, key, coerceValue(cfg, key, values[key])); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		flagSet, err := NewFlagSet("", description)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = flagSet.Parse(args); err != nil {
+			return nil, err
+		}
+
+		overrides, err = flagSet.Values()
+		if err != nil {
+			return nil, err
+		}
+
+		rest = flagSet.Args()
+	}
+
+	if len(rest) > 0 {
+		return nil, errors.NewFmt("unexpected argument '%s'", rest[0]).WithCause(ErrUnexpectedArgument)
+	}
+
+	if len(overrides) == 0 {
+		return cfg, nil
+	}
+
+	return config.Merge(cfg, config.NewFromRaw(overrides)), nil
+}
+
+// Parse function parses arguments in forms '--key.path=value', '--key.path value' and '--key.path', which means
+// 'true', into the map of raw values by their keys. Single dash is allowed too. Parsing stops at the first argument
+// that is not a flag or after '--', remaining arguments are returned as well. If the same key is provided several
+// times, the last value wins.
+func Parse(args []string) (map[string]string, []string, error) {
+	values := make(map[string]string)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			return values, args[i+1:], nil
+		}
+
+		if len(arg) < 2 || arg[0] != '-' {
+			return values, args[i:], nil
+		}
+
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+
+		key, value, hasValue := strings.Cut(name, "=")
+		if key == "" || strings.HasPrefix(key, "-") {
+			return nil, nil, errors.NewFmt("malformed flag '%s'", arg).WithCause(ErrInvalidFlag)
+		}
+
+		if !hasValue {
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				i++
+				value = args[i]
+			} else {
+				value = "true"
+			}
+		}
+
+		values[key] = value
+	}
+
+	return values, nil, nil
+}
+
+// coerceValue function converts raw flag value to the type of existing configuration value, or guesses its type if
+// there is no such value.
+func coerceValue(cfg *config.Config, key string, value string) interface{} {
+	existing, err := cfg.GetRawByKey(key)
+	if err != nil {
+		existing = nil
+	}
+
+	if _, ok := existing.(string); ok {
+		return value
+	}
+
+	return flat.InferJSON(value)
+}
+
+// setValue function sets value by the composite key inside nested maps creating missing ones. Key grammar is the same
+// as for config.ParseKey function, but wildcards are not allowed.
+func setValue(data map[string]interface{}, key string, value interface{}) error {
+	segments, err := config.ParseKey(key)
+	if err != nil {
+		return errors.NewFmt("malformed key '%s'", key).WithCause(ErrInvalidFlag)
+	}
+
+	if len(segments) == 0 {
+		return errors.NewFmt("key '%s' is empty", key).WithCause(ErrInvalidFlag)
+	}
+
+	for i, segment := range segments {
+		if segment.Wildcard {
+			return errors.NewFmt("key '%s' has wildcard segment", key).WithCause(ErrInvalidFlag)
+		}
+
+		if i+1 == len(segments) {
+			if _, ok := data[segment.Name].(map[string]interface{}); ok {
+				return errors.NewFmt("key '%s' conflicts with nested keys", key).WithCause(ErrInvalidFlag)
+			}
+
+			data[segment.Name] = value
+
+			break
+		}
+
+		inner, ok := data[segment.Name]
+		if !ok {
+			inner = make(map[string]interface{})
+			data[segment.Name] = inner
+		}
+
+		if data, ok = inner.(map[string]interface{}); !ok {
+			names := make([]string, 0, i+1)
+			for _, prev := range segments[:i+1] {
+				names = append(names, prev.Name)
+			}
+
+			return errors.NewFmt("key '%s' conflicts with key '%s'", key, config.JoinKey(names...)).
+				WithCause(ErrInvalidFlag)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package flags_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/flags"
+)
+
+type httpConfig struct {
+	Address string  `default:"127.0.0.1:8080" description:"Address to listen to."`
+	Timeout int     `default:"3" description:"Timeout in seconds."`
+	Ratio   float64 `description:"Sampling ratio."`
+	Debug   bool    `description:"Debug mode."`
+}
+
+type appConfig struct {
+	HTTP httpConfig        `config:"http"`
+	Tags []string          `config:"tags"`
+	Env  map[string]string `config:"env"`
+}
+
+func TestParse(t *testing.T) {
+	values, rest, err := flags.Parse([]string{"--http.address=0.0.0.0:80", "-http.timeout", "10", "--debug",
+		"--http.address", "0.0.0.0:8080", "--verbose", "--name=", "--", "--other"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"http.address": "0.0.0.0:8080",
+		"http.timeout": "10",
+		"debug":        "true",
+		"verbose":      "true",
+		"name":         "",
+	}, values)
+	require.Equal(t, []string{"--other"}, rest)
+
+	values, rest, err = flags.Parse([]string{"--debug=true", "run", "--fast"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"debug": "true"}, values)
+	require.Equal(t, []string{"run", "--fast"}, rest)
+
+	_, _, err = flags.Parse([]string{"---debug"})
+	require.ErrorIs(t, err, flags.ErrInvalidFlag)
+
+	_, _, err = flags.Parse([]string{"--=value"})
+	require.ErrorIs(t, err, flags.ErrInvalidFlag)
+}
+
+func TestNewConfig(t *testing.T) {
+	base := config.NewFromRaw(map[string]interface{}{
+		"http": map[string]interface{}{
+			"address":  "127.0.0.1:8080",
+			"password": "secret",
+			"timeout":  3,
+		},
+	})
+
+	cfg, err := flags.NewConfig(base, []string{"--http.address=0.0.0.0:80", "--http.password", "123",
+		"--http.timeout=10", "--http.debug", "--tags=[\"a\",\"b\"]", "--http.ratio=0.5"}, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"http": map[string]interface{}{
+			"address":  "0.0.0.0:80",
+			"password": "123",
+			"timeout":  10,
+			"debug":    true,
+			"ratio":    0.5,
+		},
+		"tags": []interface{}{"a", "b"},
+	}, cfg.GetRaw())
+
+	base.Update(map[string]interface{}{"name": "app"})
+	require.Equal(t, "app", cfg.GetRaw()["name"])
+	require.Equal(t, "0.0.0.0:80", cfg.GetRaw()["http"].(map[string]interface{})["address"])
+
+	cfg, err = flags.NewConfig(base, nil, nil)
+	require.NoError(t, err)
+	require.Same(t, base, cfg)
+
+	_, err = flags.NewConfig(base, []string{"--http=1", "--http.address=0.0.0.0:80"}, nil)
+	require.ErrorIs(t, err, flags.ErrInvalidFlag)
+
+	_, err = flags.NewConfig(base, []string{"--servers.*.port=80"}, nil)
+	require.ErrorIs(t, err, flags.ErrInvalidFlag)
+
+	_, err = flags.NewConfig(base, []string{"run"}, nil)
+	require.ErrorIs(t, err, flags.ErrUnexpectedArgument)
+
+	cfg, err = flags.NewConfig(base, []string{`--hosts."db.example.com".port=5432`}, nil)
+	require.NoError(t, err)
+
+	port, err := cfg.GetRawByKey(`hosts."db.example.com".port`)
+	require.NoError(t, err)
+	require.Equal(t, 5432, port)
+}
+
+func TestNewConfigWithDescription(t *testing.T) {
+	base := config.NewFromRaw(map[string]interface{}{
+		"http": map[string]interface{}{"address": "127.0.0.1:8080"},
+	})
+
+	cfg, err := flags.NewConfig(base, []string{"--http.timeout=10", "--http.debug", "-http.ratio", "0.25",
+		"--tags", `["a"]`, "--env", `{"mode":"dev"}`}, appConfig{})
+	require.NoError(t, err)
+
+	var app appConfig
+
+	require.NoError(t, cfg.Get(&app))
+	require.Equal(t, appConfig{
+		HTTP: httpConfig{Address: "127.0.0.1:8080", Timeout: 10, Ratio: 0.25, Debug: true},
+		Tags: []string{"a"},
+		Env:  map[string]string{"mode": "dev"},
+	}, app)
+
+	for _, args := range [][]string{
+		{"--http.timeout=ten"},
+		{"--http.ratio=half"},
+		{"--http.debug=maybe"},
+		{"--tags=a,b"},
+		{"--http.unknown=1"},
+	} {
+		_, err = flags.NewConfig(base, args, appConfig{})
+		require.Error(t, err, args)
+	}
+
+	_, err = flags.NewConfig(base, []string{"--http.debug", "run"}, appConfig{})
+	require.ErrorIs(t, err, flags.ErrUnexpectedArgument)
+
+	_, err = flags.NewConfig(base, nil, struct{ Events chan int }{})
+	require.Error(t, err)
+}
+
+func TestHelp(t *testing.T) {
+	flagSet, err := flags.NewFlagSet("app", appConfig{})
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+
+	flagSet.SetOutput(&output)
+
+	err = flagSet.Parse([]string{"--help"})
+	require.ErrorIs(t, err, flag.ErrHelp)
+
+	require.Contains(t, output.String(), "Usage of app:")
+	require.Contains(t, output.String(), "-http.address value\n    \tAddress to listen to. (default 127.0.0.1:8080)")
+	require.Contains(t, output.String(), "-http.timeout value\n    \tTimeout in seconds. (default 3)")
+	require.Contains(t, output.String(), "-http.debug\n    \tDebug mode.")
+	require.Contains(t, output.String(), "-tags value")
+
+	values, err := flagSet.Values()
+	require.NoError(t, err)
+	require.Empty(t, values)
+}
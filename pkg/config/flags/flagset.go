@@ -0,0 +1,152 @@
+package flags
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/internal/flat"
+	"github.com/lightstar/golib/pkg/config/schema"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// FlagSet structure is a standard flag set with a flag for each configuration key described by the structure.
+// Use it directly if you need to add your own flags, otherwise NewConfig function is enough.
+type FlagSet struct {
+	*flag.FlagSet
+	values []*value
+}
+
+// value structure is a flag value of some configuration key.
+type value struct {
+	key    string
+	schema *schema.Schema
+	raw    string
+	parsed interface{}
+	isSet  bool
+}
+
+// NewFlagSet function creates flag set with provided name and a flag for each configuration key described by the
+// structure, see schema package for the tags used. Flag names are full key paths like 'http.address', flag defaults
+// and usages are taken from 'default' and 'description' tags. Flag values are converted to the types of structure
+// fields, lists and maps are expected as JSON literals.
+//
+// Flag set is created with flag.ContinueOnError handling, so errors are returned by Parse method.
+func NewFlagSet(name string, description interface{}) (*FlagSet, error) {
+	s, err := schema.Generate(description)
+	if err != nil {
+		return nil, err
+	}
+
+	flagSet := &FlagSet{FlagSet: flag.NewFlagSet(name, flag.ContinueOnError)}
+	flagSet.addFlags("", s)
+
+	return flagSet, nil
+}
+
+// Values method retrieves nested map of configuration values of flags that were set while parsing.
+func (flagSet *FlagSet) Values() (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	for _, v := range flagSet.values {
+		if !v.isSet {
+			continue
+		}
+
+		if err := setValue(values, v.key, v.parsed); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// addFlags method adds flags for all properties of the object schema. Nested structures are added recursively.
+func (flagSet *FlagSet) addFlags(prefix string, s *schema.Schema) {
+	for _, name := range s.PropertyNames() {
+		property := s.Properties[name]
+		key := prefix + config.JoinKey(name)
+
+		if property.Type == schema.TypeObject && property.Properties != nil {
+			flagSet.addFlags(key+".", property)
+			continue
+		}
+
+		v := &value{key: key, schema: property}
+		if property.HasDefault && property.Default != nil {
+			v.raw = fmt.Sprint(property.Default)
+		}
+
+		flagSet.Var(v, key, property.Description)
+		flagSet.values = append(flagSet.values, v)
+	}
+}
+
+// String method retrieves raw value of the flag.
+func (v *value) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.raw
+}
+
+// Set method converts raw value of the flag to the type of configuration key.
+func (v *value) Set(raw string) error {
+	parsed, err := parseValue(v.schema, raw)
+	if err != nil {
+		return err
+	}
+
+	v.raw = raw
+	v.parsed = parsed
+	v.isSet = true
+
+	return nil
+}
+
+// IsBoolFlag method allows boolean flags without value.
+func (v *value) IsBoolFlag() bool {
+	return v.schema != nil && v.schema.Type == schema.TypeBoolean
+}
+
+// parseValue function converts raw flag value to the type defined by the schema.
+func parseValue(s *schema.Schema, raw string) (interface{}, error) {
+	switch s.Type {
+	case schema.TypeString:
+		return raw, nil
+	case schema.TypeInteger:
+		intValue, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, errors.NewFmt("'%s' is not an integer", raw)
+		}
+
+		return int(intValue), nil
+	case schema.TypeNumber:
+		floatValue, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, errors.NewFmt("'%s' is not a number", raw)
+		}
+
+		return floatValue, nil
+	case schema.TypeBoolean:
+		boolValue, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.NewFmt("'%s' is not a boolean", raw)
+		}
+
+		return boolValue, nil
+	case schema.TypeArray, schema.TypeObject:
+		var result interface{}
+
+		if err := json.Unmarshal([]byte(raw), &result); err != nil {
+			return nil, errors.NewFmt("'%s' is not a JSON %s", raw, s.Type)
+		}
+
+		return result, nil
+	default:
+		return flat.InferJSON(raw), nil
+	}
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"context"
+	"io"
+	nethttp "net/http"
+	"net/url"
+	"time"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// redacted is the replacement of secret parts of the URL in error messages.
+const redacted = "xxxxx"
+
+// fetcher structure makes requests to the URL and remembers validators of the last successfully parsed document, so
+// next requests are conditional.
+type fetcher struct {
+	url          string
+	redactedURL  string
+	encoder      config.Encoder
+	client       *nethttp.Client
+	header       nethttp.Header
+	timeout      time.Duration
+	maxSize      int64
+	retries      int
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	etag         string
+	lastModified string
+}
+
+// newFetcher function creates new fetcher of the URL with chosen encoder and source configuration.
+func newFetcher(url string, encoder config.Encoder, sourceConfig *SourceConfig) *fetcher {
+	return &fetcher{
+		url:         url,
+		redactedURL: RedactURL(url),
+		encoder:     encoder,
+		client:      sourceConfig.client,
+		header:      sourceConfig.header,
+		timeout:     sourceConfig.timeout,
+		maxSize:     sourceConfig.maxSize,
+		retries:     sourceConfig.retries,
+		minBackoff:  sourceConfig.minBackoff,
+		maxBackoff:  sourceConfig.maxBackoff,
+	}
+}
+
+// fetch method retrieves and parses the document, retrying failed requests with growing delay. It returns false if
+// the document wasn't modified since the last successful fetch, data is nil in that case.
+func (f *fetcher) fetch(ctx context.Context) (map[string]interface{}, bool, error) {
+	backoff := f.minBackoff
+
+	for attempt := 0; ; attempt++ {
+		data, modified, retry, err := f.fetchOnce(ctx)
+		if err == nil || !retry || attempt >= f.retries {
+			return data, modified, err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, false, err
+		}
+
+		backoff *= 2
+		if backoff > f.maxBackoff {
+			backoff = f.maxBackoff
+		}
+	}
+}
+
+// fetchOnce method makes one request to the URL. Besides the result of fetch method it returns true if the request
+// failed with temporary error and can be retried.
+func (f *fetcher) fetchOnce(ctx context.Context) (map[string]interface{}, bool, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, false, false, errors.NewFmt("can't create request to '%s' (%s)", f.redactedURL,
+			unwrapURLError(err).Error()).WithCause(err)
+	}
+
+	for name, values := range f.header {
+		req.Header[name] = values
+	}
+
+	if f.etag != "" {
+		req.Header.Set("If-None-Match", f.etag)
+	}
+
+	if f.lastModified != "" {
+		req.Header.Set("If-Modified-Since", f.lastModified)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, false, true, errors.NewFmt("can't request '%s' (%s)", f.redactedURL,
+			unwrapURLError(err).Error()).WithCause(err)
+	}
+
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == nethttp.StatusNotModified:
+		return nil, false, false, nil
+	case resp.StatusCode != nethttp.StatusOK:
+		retry := resp.StatusCode >= nethttp.StatusInternalServerError ||
+			resp.StatusCode == nethttp.StatusTooManyRequests
+
+		return nil, false, retry, errors.NewFmt("can't request '%s' (%s)", f.redactedURL, resp.Status).
+			WithCause(ErrUnexpectedStatus)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxSize+1))
+	if err != nil {
+		return nil, false, true, errors.NewFmt("can't read response from '%s' (%s)", f.redactedURL,
+			unwrapURLError(err).Error()).WithCause(err)
+	}
+
+	if int64(len(body)) > f.maxSize {
+		return nil, false, false, errors.NewFmt("response from '%s' exceeds %d bytes", f.redactedURL, f.maxSize).
+			WithCause(ErrResponseTooLarge)
+	}
+
+	var data map[string]interface{}
+
+	if err = f.encoder.Encode(body, &data); err != nil {
+		return nil, false, false, err
+	}
+
+	f.etag = resp.Header.Get("ETag")
+	f.lastModified = resp.Header.Get("Last-Modified")
+
+	return data, true, false, nil
+}
+
+// RedactURL function retrieves form of the URL that is safe to put into error messages and logs: password and values
+// of query parameters are replaced with 'xxxxx'. Malformed URL is replaced completely.
+func RedactURL(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+
+	if parsedURL.RawQuery != "" {
+		query := parsedURL.Query()
+		for name, values := range query {
+			for i := range values {
+				values[i] = redacted
+			}
+
+			query[name] = values
+		}
+
+		parsedURL.RawQuery = query.Encode()
+	}
+
+	return parsedURL.Redacted()
+}
+
+// unwrapURLError function retrieves underlying error of url.Error, as its message contains the whole URL.
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+
+	return err
+}
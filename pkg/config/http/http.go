@@ -0,0 +1,62 @@
+// Package http is a wrapper around config package and allows reading configuration from some URL served by HTTP
+// server.
+//
+// Typical usage:
+//
+//	cfg := config.Must(http.NewConfig(url, encoder, http.WithAuthorization("Bearer "+token)))
+//
+//	err = cfg.Get(&myStructure)
+//	if err != nil {
+//	    panic(err)
+//	}
+//
+// If you need configuration to follow changes of the document without restarting the process, use Watcher instead:
+//
+//	watcher := http.MustNewWatcher(url, encoder, http.WithInterval(30*time.Second))
+//	go watcher.Run(ctx)
+//
+//	cfg := watcher.Config()
+//
+// URL can contain credentials and tokens, they are redacted in error messages, see RedactURL function.
+//
+// Watcher polls the URL with conditional requests using 'If-None-Match' and 'If-Modified-Since' headers, so servers
+// that support them just answer '304 Not Modified' while the document stays the same.
+//
+// See config package for more details.
+package http
+
+import (
+	"context"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+var (
+	// ErrUnexpectedStatus error is returned when HTTP server answers with status other than '200 OK' or
+	// '304 Not Modified'.
+	ErrUnexpectedStatus = errors.New("unexpected status")
+
+	// ErrResponseTooLarge error is returned when document is larger than allowed by WithMaxSize option.
+	ErrResponseTooLarge = errors.New("response too large")
+
+	// ErrInvalidOption error is returned when some option has invalid value, such as non-positive interval.
+	ErrInvalidOption = errors.New("invalid option")
+)
+
+// NewConfig function creates new configuration service using document at provided URL as a source and chosen
+// encoder. Options such as timeout, authorization header and retries are applied to the request.
+// Most likely you will use one of the predefined encoders: json.Encoder, yaml.Encoder or toml.Encoder.
+func NewConfig(url string, encoder config.Encoder, opts ...Option) (*config.Config, error) {
+	sourceConfig, err := buildSourceConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _, err := newFetcher(url, encoder, sourceConfig).fetch(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return config.NewFromRaw(data), nil
+}
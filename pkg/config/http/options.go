@@ -0,0 +1,174 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+const (
+	// DefInterval is the default interval between polls of the URL for changes.
+	DefInterval = 10 * time.Second
+	// DefTimeout is the default timeout of one request to the URL.
+	DefTimeout = 5 * time.Second
+	// DefRetries is the default number of retries of the failed request.
+	DefRetries = 2
+	// DefMinBackoff is the default delay before the first retry of the failed request.
+	DefMinBackoff = 100 * time.Millisecond
+	// DefMaxBackoff is the default maximum delay between retries of the failed request.
+	DefMaxBackoff = 5 * time.Second
+	// DefMaxSize is the default maximum size of the document in bytes.
+	DefMaxSize = 10 << 20
+)
+
+// SourceConfig structure with configuration of the source. Shouldn't be created manually.
+type SourceConfig struct {
+	client     *nethttp.Client
+	header     nethttp.Header
+	interval   time.Duration
+	timeout    time.Duration
+	maxSize    int64
+	retries    int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	errorFn    func(error)
+}
+
+// Option function that is fed to NewConfig, NewWatcher and MustNewWatcher. Obtain them using 'With' functions down
+// below.
+type Option func(*SourceConfig) error
+
+// WithInterval option applies provided interval between polls of the URL for changes. It is used by watcher only.
+// Default: 10s.
+func WithInterval(interval time.Duration) Option {
+	return func(cfg *SourceConfig) error {
+		if interval <= 0 {
+			return errors.NewFmt("interval must be positive, got %s", interval).WithCause(ErrInvalidOption)
+		}
+
+		cfg.interval = interval
+
+		return nil
+	}
+}
+
+// WithTimeout option applies provided timeout of one request to the URL, including reading of the response body.
+// Default: 5s.
+func WithTimeout(timeout time.Duration) Option {
+	return func(cfg *SourceConfig) error {
+		if timeout <= 0 {
+			return errors.NewFmt("timeout must be positive, got %s", timeout).WithCause(ErrInvalidOption)
+		}
+
+		cfg.timeout = timeout
+
+		return nil
+	}
+}
+
+// WithMaxSize option applies provided maximum size of the document in bytes. Larger documents are rejected with
+// ErrResponseTooLarge error. Default: 10MiB.
+func WithMaxSize(maxSize int64) Option {
+	return func(cfg *SourceConfig) error {
+		if maxSize <= 0 {
+			return errors.NewFmt("max size must be positive, got %d", maxSize).WithCause(ErrInvalidOption)
+		}
+
+		cfg.maxSize = maxSize
+
+		return nil
+	}
+}
+
+// WithHeader option adds provided header to every request to the URL. It can be used several times.
+func WithHeader(name string, value string) Option {
+	return func(cfg *SourceConfig) error {
+		cfg.header.Add(name, value)
+		return nil
+	}
+}
+
+// WithAuthorization option sets 'Authorization' header of every request to the URL, such as 'Bearer <token>'.
+func WithAuthorization(value string) Option {
+	return func(cfg *SourceConfig) error {
+		cfg.header.Set("Authorization", value)
+		return nil
+	}
+}
+
+// WithRetries option applies provided number of retries of the request that failed because of network error or
+// server error status, such as '503 Service Unavailable'. Zero disables retries. Default: 2.
+func WithRetries(retries int) Option {
+	return func(cfg *SourceConfig) error {
+		if retries < 0 {
+			return errors.NewFmt("retries must not be negative, got %d", retries).WithCause(ErrInvalidOption)
+		}
+
+		cfg.retries = retries
+
+		return nil
+	}
+}
+
+// WithBackoff option applies provided delays between retries of the failed request. The first retry is made after
+// minimum delay, and the delay is doubled before each next one until it reaches the maximum delay.
+// Default: 100ms and 5s.
+func WithBackoff(minBackoff time.Duration, maxBackoff time.Duration) Option {
+	return func(cfg *SourceConfig) error {
+		if minBackoff <= 0 || maxBackoff < minBackoff {
+			return errors.NewFmt("invalid backoff %s-%s", minBackoff, maxBackoff).WithCause(ErrInvalidOption)
+		}
+
+		cfg.minBackoff = minBackoff
+		cfg.maxBackoff = maxBackoff
+
+		return nil
+	}
+}
+
+// WithClient option applies provided HTTP client used to make requests, for example the one with custom TLS
+// configuration. Default: new client with default transport.
+func WithClient(client *nethttp.Client) Option {
+	return func(cfg *SourceConfig) error {
+		if client == nil {
+			return errors.New("client must not be nil").WithCause(ErrInvalidOption)
+		}
+
+		cfg.client = client
+
+		return nil
+	}
+}
+
+// WithErrorFunc option applies provided function that will be called on any error occurred while polling the URL,
+// such as network error or parse error of the new document. It is used by watcher only.
+// Default: none, errors are silently ignored.
+func WithErrorFunc(errorFn func(error)) Option {
+	return func(cfg *SourceConfig) error {
+		cfg.errorFn = errorFn
+		return nil
+	}
+}
+
+// buildSourceConfig function builds source configuration using list of provided options.
+func buildSourceConfig(opts []Option) (*SourceConfig, error) {
+	cfg := &SourceConfig{
+		client:     &nethttp.Client{},
+		header:     make(nethttp.Header),
+		interval:   DefInterval,
+		timeout:    DefTimeout,
+		maxSize:    DefMaxSize,
+		retries:    DefRetries,
+		minBackoff: DefMinBackoff,
+		maxBackoff: DefMaxBackoff,
+	}
+
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/lightstar/golib/pkg/config"
+)
+
+// Watcher structure that keeps configuration service in sync with the document at some URL.
+// It polls the URL with conditional requests and re-parses the document when server sends a new one. If server is
+// unreachable or sends a document that can't be parsed, the last good configuration is kept and the error is passed
+// to the error function.
+// Don't create it manually, use the functions down below instead.
+//
+// Typical usage:
+//
+//	watcher := http.MustNewWatcher(url, encoder, http.WithInterval(30*time.Second), http.WithErrorFunc(logError))
+//	cfg := watcher.Config()
+//	cfg.Subscribe(func(cfg *config.Config) { ... })
+//	go watcher.Run(ctx)
+type Watcher struct {
+	fetcher  *fetcher
+	config   *config.Config
+	interval time.Duration
+	errorFn  func(error)
+}
+
+// NewWatcher function creates new watcher of the URL with chosen encoder and provided options.
+// Configuration is read immediately, so any error with the URL is returned right here.
+func NewWatcher(url string, encoder config.Encoder, opts ...Option) (*Watcher, error) {
+	sourceConfig, err := buildSourceConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fetcher := newFetcher(url, encoder, sourceConfig)
+
+	data, _, err := fetcher.fetch(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{
+		fetcher:  fetcher,
+		config:   config.NewFromRaw(data),
+		interval: sourceConfig.interval,
+		errorFn:  sourceConfig.errorFn,
+	}, nil
+}
+
+// MustNewWatcher function creates new watcher with provided options and panics on any error.
+func MustNewWatcher(url string, encoder config.Encoder, opts ...Option) *Watcher {
+	watcher, err := NewWatcher(url, encoder, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return watcher
+}
+
+// Config method gets configuration service that is kept in sync with the document.
+func (watcher *Watcher) Config() *config.Config {
+	return watcher.config
+}
+
+// Run method runs poll loop. It is blocking so you probably want to run it in a separate goroutine.
+// Pass cancellable context here to stop polling, it also cancels the request in progress.
+func (watcher *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(watcher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := watcher.check(ctx); err != nil && watcher.errorFn != nil && ctx.Err() == nil {
+				watcher.errorFn(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check method reloads the document if server sends a new one.
+func (watcher *Watcher) check(ctx context.Context) error {
+	data, modified, err := watcher.fetcher.fetch(ctx)
+	if err != nil || !modified {
+		return err
+	}
+
+	watcher.config.Update(data)
+
+	return nil
+}
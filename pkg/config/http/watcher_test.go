@@ -0,0 +1,215 @@
+package http_test
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder/json"
+	"github.com/lightstar/golib/pkg/config/http"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+const (
+	testWatchInterval = 10 * time.Millisecond
+	testWatchWait     = time.Second
+)
+
+// testServer structure is HTTP server that serves configuration document with ETag or Last-Modified validators and
+// records conditional headers of requests.
+type testServer struct {
+	mu           sync.Mutex
+	body         []byte
+	etag         string
+	lastModified time.Time
+	down         bool
+	conditional  int
+	notModified  int
+}
+
+func (s *testServer) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.down {
+		w.WriteHeader(nethttp.StatusBadGateway)
+		return
+	}
+
+	if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+		s.conditional++
+	}
+
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+
+		if r.Header.Get("If-None-Match") == s.etag {
+			s.notModified++
+			w.WriteHeader(nethttp.StatusNotModified)
+
+			return
+		}
+	}
+
+	if !s.lastModified.IsZero() {
+		w.Header().Set("Last-Modified", s.lastModified.UTC().Format(nethttp.TimeFormat))
+
+		since, err := nethttp.ParseTime(r.Header.Get("If-Modified-Since"))
+		if err == nil && !s.lastModified.After(since) {
+			s.notModified++
+			w.WriteHeader(nethttp.StatusNotModified)
+
+			return
+		}
+	}
+
+	_, _ = w.Write(s.body)
+}
+
+func (s *testServer) set(fn func(s *testServer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(s)
+}
+
+func (s *testServer) get(fn func(s *testServer) int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fn(s)
+}
+
+func TestWatcher(t *testing.T) {
+	t.Run("etag", func(t *testing.T) {
+		testWatcher(t, func(s *testServer, version int) {
+			s.etag = `"v` + strconv.Itoa(version) + `"`
+		})
+	})
+
+	t.Run("last-modified", func(t *testing.T) {
+		testWatcher(t, func(s *testServer, version int) {
+			s.lastModified = time.Date(2023, 5, 1, 10, version, 0, 0, time.UTC)
+		})
+	})
+}
+
+func testWatcher(t *testing.T, setVersion func(s *testServer, version int)) {
+	t.Helper()
+
+	server := &testServer{body: configtest.SampleConfigDataJSON}
+	setVersion(server, 1)
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	var mu sync.Mutex
+	var errs []error
+
+	watcher, err := http.NewWatcher(httpServer.URL, json.Encoder,
+		http.WithInterval(testWatchInterval),
+		http.WithRetries(0),
+		http.WithErrorFunc(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			errs = append(errs, err)
+		}),
+	)
+	require.NoError(t, err)
+
+	cfg := watcher.Config()
+	configtest.TestSampleConfig(t, cfg, configtest.ExpectedSampleRawDataJSON)
+
+	updated := make(chan struct{}, 1)
+	cfg.Subscribe(func(*config.Config) {
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	go func() {
+		watcher.Run(ctx)
+		close(stopped)
+	}()
+
+	require.Eventually(t, func() bool {
+		return server.get(func(s *testServer) int { return s.notModified }) >= 2
+	}, testWatchWait, testWatchInterval)
+
+	server.set(func(s *testServer) {
+		s.body = []byte(`{"name":"George"}`)
+		setVersion(s, 2)
+	})
+
+	select {
+	case <-updated:
+	case <-time.After(testWatchWait):
+		require.FailNow(t, "configuration wasn't reloaded")
+	}
+
+	require.Equal(t, map[string]interface{}{"name": "George"}, cfg.GetRaw())
+
+	server.set(func(s *testServer) { s.down = true })
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(errs) > 0
+	}, testWatchWait, testWatchInterval)
+
+	require.Equal(t, map[string]interface{}{"name": "George"}, cfg.GetRaw())
+
+	mu.Lock()
+	require.ErrorIs(t, errs[0], http.ErrUnexpectedStatus)
+	mu.Unlock()
+
+	server.set(func(s *testServer) {
+		s.down = false
+		s.body = configtest.SampleConfigDataWrongJSON
+		setVersion(s, 3)
+	})
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return !errors.Is(errs[len(errs)-1], http.ErrUnexpectedStatus)
+	}, testWatchWait, testWatchInterval)
+
+	require.Equal(t, map[string]interface{}{"name": "George"}, cfg.GetRaw())
+	require.Positive(t, server.get(func(s *testServer) int { return s.conditional }))
+
+	cancel()
+	<-stopped
+}
+
+func TestWatcherErrors(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		_, _ = w.Write(configtest.SampleConfigDataWrongJSON)
+	}))
+	defer server.Close()
+
+	_, err := http.NewWatcher(server.URL, json.Encoder)
+	require.ErrorContains(t, err, "json error")
+
+	require.Panics(t, func() {
+		_ = http.MustNewWatcher(server.URL, json.Encoder)
+	})
+
+	_, err = http.NewWatcher(server.URL, json.Encoder, http.WithInterval(-time.Second))
+	require.ErrorIs(t, err, http.ErrInvalidOption)
+}
@@ -1,6 +1,11 @@
 package i2s
 
-import "github.com/lightstar/golib/pkg/errors"
+import (
+	"reflect"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/errors"
+)
 
 var (
 	// ErrMismatchedTypes error is returned when types of input and output fields doesn't match.
@@ -15,6 +20,106 @@ var (
 	// ErrUnknownField error is returned when output structure doesn't have an appropriate field for input data.
 	ErrUnknownField = errors.New("unknown field")
 
+	// ErrAmbiguousField error is returned when data key matches several structure fields, or several data keys match
+	// the same field.
+	ErrAmbiguousField = errors.New("ambiguous field")
+
+	// ErrRequiredField error is returned when source doesn't have data for the field marked as required.
+	ErrRequiredField = errors.New("required field is missing")
+
+	// ErrInvalidDefault error is returned when default value defined in field tag can't be converted to field type.
+	ErrInvalidDefault = errors.New("invalid default value")
+
+	// ErrOutOfRange error is returned when numeric value doesn't fit into the output field type.
+	ErrOutOfRange = errors.New("value out of range")
+
+	// ErrInvalidValue error is returned when value can't be parsed into the output field type, such as malformed
+	// duration string.
+	ErrInvalidValue = errors.New("invalid value")
+
 	// ErrUnsupportedType error is returned when source contains data of type unsupported by this package.
 	ErrUnsupportedType = errors.New("unsupported type")
 )
+
+// FieldError structure describes an error occurred while converting data under some key path.
+// Its cause is one of the errors defined above, so use errors.Is to check the kind of error.
+type FieldError struct {
+	*errors.Err
+	// Path is the full key path of the data, like 'http.server.tls.certFile'.
+	Path string
+	// Expected is the Go type of the output value, if it is known.
+	Expected string
+	// Actual is the type of the source data, if it is known.
+	Actual string
+}
+
+// newFieldError function creates new field error with optional detail appended to the cause message.
+func newFieldError(cause error, path string, detail string) *FieldError {
+	msg := cause.Error()
+
+	if path != "" {
+		msg += " at '" + path + "'"
+	}
+
+	if detail != "" {
+		msg += " (" + detail + ")"
+	}
+
+	return &FieldError{
+		Err:  errors.New(msg).WithCause(cause),
+		Path: path,
+	}
+}
+
+// newTypedFieldError function creates new field error that includes types of source data and output value.
+func newTypedFieldError(cause error, path string, dataValue reflect.Value, outValue reflect.Value) *FieldError {
+	expected, actual := outValue.Type().String(), dataValue.Type().String()
+
+	err := newFieldError(cause, path, "expected "+expected+", got "+actual)
+	err.Expected = expected
+	err.Actual = actual
+
+	return err
+}
+
+// MultiError structure holds all errors collected during conversion by convertor created with WithCollectErrors
+// option. Use errors.Is or errors.As to check them, or iterate over Errors field directly.
+type MultiError struct {
+	Errors []error
+}
+
+// Error method retrieves messages of all collected errors.
+func (err *MultiError) Error() string {
+	msgs := make([]string, 0, len(err.Errors))
+
+	for _, e := range err.Errors {
+		msgs = append(msgs, e.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap method retrieves all collected errors implementing internal interface inside 'errors' standard package.
+func (err *MultiError) Unwrap() []error {
+	return err.Errors
+}
+
+// appendError function appends error to the list flattening it if it is MultiError itself.
+func appendError(errs []error, err error) []error {
+	var multiErr *MultiError
+
+	if errors.As(err, &multiErr) {
+		return append(errs, multiErr.Errors...)
+	}
+
+	return append(errs, err)
+}
+
+// multiError function creates MultiError from the list of errors if it is not empty.
+func multiError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{Errors: errs}
+}
@@ -0,0 +1,179 @@
+package i2s
+
+import (
+	"reflect"
+	"strings"
+)
+
+const (
+	// configTag is the name of struct tag that defines key name and options of the field.
+	configTag = "config"
+	// defaultTag is the name of struct tag that defines default value of the field.
+	defaultTag = "default"
+	// requiredOption is the option of config tag that marks field as required.
+	requiredOption = "required"
+	// omitEmptyOption is the option of config tag that makes field with zero value omitted when structure is
+	// converted into map.
+	omitEmptyOption = "omitempty"
+	// ignoredName is the name in config tag that makes field ignored.
+	ignoredName = "-"
+)
+
+// field structure describes one field of output structure.
+type field struct {
+	index        []int
+	name         string
+	tagged       bool
+	required     bool
+	omitEmpty    bool
+	hasDefault   bool
+	defaultValue string
+}
+
+// structFields function collects fields of output structure type that can be filled with configuration data.
+// Fields of embedded structures are collected too as if they belonged to the outer structure, shadowed ones are dropped
+// later, see visibleFields function.
+func structFields(structType reflect.Type) []field {
+	fields := make([]field, 0, structType.NumField())
+
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+
+		tag, hasTag := structField.Tag.Lookup(configTag)
+		name, options, _ := strings.Cut(tag, ",")
+
+		if name == ignoredName {
+			continue
+		}
+
+		if structField.Anonymous && name == "" && structField.Type.Kind() == reflect.Struct {
+			for _, innerField := range structFields(structField.Type) {
+				innerField.index = append([]int{i}, innerField.index...)
+				fields = append(fields, innerField)
+			}
+
+			continue
+		}
+
+		if !structField.IsExported() {
+			continue
+		}
+
+		defaultValue, hasDefault := structField.Tag.Lookup(defaultTag)
+
+		currentField := field{
+			index:        []int{i},
+			name:         structField.Name,
+			required:     hasTag && hasOption(options, requiredOption),
+			omitEmpty:    hasTag && hasOption(options, omitEmptyOption),
+			hasDefault:   hasDefault,
+			defaultValue: defaultValue,
+		}
+
+		if name != "" {
+			currentField.name = name
+			currentField.tagged = true
+		}
+
+		fields = append(fields, currentField)
+	}
+
+	return fields
+}
+
+// KeyName function retrieves key name that corresponds to the structure field according to its tags and the default
+// name mapping strategy. It returns false if the field can't be filled with configuration data at all, such as
+// unexported or ignored one.
+func KeyName(structField reflect.StructField) (string, bool) {
+	return KeyNameWithMapping(structField, FirstLetterMapping)
+}
+
+// KeyNameWithMapping function works like KeyName function, but uses provided name mapping strategy for untagged
+// fields, see Convertor.NameMapping method.
+func KeyNameWithMapping(structField reflect.StructField, mapping NameMapping) (string, bool) {
+	name, _, _ := strings.Cut(structField.Tag.Get(configTag), ",")
+
+	if name == ignoredName || !structField.IsExported() {
+		return "", false
+	}
+
+	if name != "" {
+		return name, true
+	}
+
+	return mapping.KeyName(structField.Name), true
+}
+
+// IsRequired function checks if the structure field is marked as required in its tags.
+func IsRequired(structField reflect.StructField) bool {
+	tag, hasTag := structField.Tag.Lookup(configTag)
+	_, options, _ := strings.Cut(tag, ",")
+
+	return hasTag && hasOption(options, requiredOption)
+}
+
+// DefaultValue function retrieves default value of the structure field defined in its tags. It returns false if
+// there is no default value.
+func DefaultValue(structField reflect.StructField) (string, bool) {
+	return structField.Tag.Lookup(defaultTag)
+}
+
+// IsEmbedded function checks if the structure field is an embedded structure, fields of which are treated as if they
+// belonged to the outer structure.
+func IsEmbedded(structField reflect.StructField) bool {
+	name, _, _ := strings.Cut(structField.Tag.Get(configTag), ",")
+
+	return structField.Anonymous && name == "" && structField.Type.Kind() == reflect.Struct
+}
+
+// fieldNames function retrieves comma-separated Go names of fields with provided indexes.
+func fieldNames(structType reflect.Type, fields []field, indexes []int) string {
+	names := make([]string, 0, len(indexes))
+
+	for _, index := range indexes {
+		names = append(names, structType.FieldByIndex(fields[index].index).Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// keyName method retrieves key name that corresponds to the field according to the name mapping strategy.
+func (f *field) keyName(mapping NameMapping) string {
+	if f.tagged {
+		return f.name
+	}
+
+	return mapping.KeyName(f.name)
+}
+
+// matchName method retrieves normalized form of data keys that match the field according to the name mapping strategy.
+func (f *field) matchName(mapping NameMapping) string {
+	if f.tagged {
+		return mapping.normalizeKey(f.name)
+	}
+
+	return mapping.normalizeField(f.name)
+}
+
+// hasOption function checks if comma-separated list of options contains provided one.
+func hasOption(options string, option string) bool {
+	for options != "" {
+		var current string
+
+		current, options, _ = strings.Cut(options, ",")
+		if strings.TrimSpace(current) == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// joinPath function appends key to the key path.
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -3,14 +3,28 @@
 //
 // Designed to be used inside config package with data unmarshalled by json, yaml, toml or any other such encoders.
 //
+// Structure fields can be customized with 'config' tag defining key name and options (like 'required' or '-' to ignore
+// the field) and 'default' tag defining value used when there is no data for the field. Keys are matched with names
+// of untagged fields according to configurable strategy, such as snake_case or case-insensitive one, see NameMapping.
+// Unknown keys fail conversion unless convertor is created with WithIgnoreUnknownFields or WithUnknownFieldHandler
+// options.
+//
+// Conversion errors are FieldError values, or MultiError holding them when errors are collected, that wrap errors
+// defined in this package, such as ErrMismatchedTypes, adding the key path and types of the problematic data. Errors
+// defined in this package are never returned as is, so check them with errors.Is instead of comparing directly, and
+// use errors.As to get FieldError details.
+//
 // It provides singleton Convertor instance that must be obtained with Instance function.
 package i2s
 
 import (
+	"fmt"
+	"math"
 	"reflect"
+	"sort"
+	"strconv"
 	"sync"
-
-	"github.com/lightstar/golib/pkg/errors"
+	"time"
 )
 
 // These variables are used to support singleton pattern.
@@ -22,76 +36,300 @@ var (
 )
 
 // Convertor structure that provides converting functionality. Don't create it manually, use Instance function instead.
+// It caches compiled plans of output structure types, so repeated conversions into the same types don't walk their
+// fields again. It is safe for concurrent use.
 type Convertor struct {
-	processFuncMap map[reflect.Kind]func(reflect.Value, reflect.Value) error
+	processFuncMap      map[reflect.Kind]processFunc
+	opts                []Option
+	collectErrors       bool
+	convertStrings      bool
+	nameMapping         NameMapping
+	unknownFieldHandler func(err error)
+	plans               sync.Map
 }
 
-// Instance function retrieves Convertor singleton instance.
+// Option function that is fed to New. Obtain them using 'With' functions down below.
+type Option func(*Convertor)
+
+// processFunc is a function that converts data of some kind into output value. Path is the full key path of the
+// data used in error messages.
+type processFunc func(path string, dataValue reflect.Value, outValue reflect.Value) error
+
+// Instance function retrieves Convertor singleton instance with default behavior.
 func Instance() *Convertor {
 	once.Do(func() {
-		instance = &Convertor{}
-		instance.processFuncMap = map[reflect.Kind]func(reflect.Value, reflect.Value) error{
-			reflect.String:  instance.processString,
-			reflect.Int:     instance.processInt,
-			reflect.Int8:    instance.processInt,
-			reflect.Int16:   instance.processInt,
-			reflect.Int32:   instance.processInt,
-			reflect.Int64:   instance.processInt,
-			reflect.Float32: instance.processFloat,
-			reflect.Float64: instance.processFloat,
-			reflect.Bool:    instance.processBool,
-			reflect.Map:     instance.processMap,
-			reflect.Slice:   instance.processSlice,
-		}
+		instance = New()
 	})
 
 	return instance
 }
 
+// New function creates new Convertor instance with provided options. Use it only if you need some non-default
+// behavior, otherwise use Instance function.
+func New(opts ...Option) *Convertor {
+	c := &Convertor{opts: opts}
+	c.processFuncMap = map[reflect.Kind]processFunc{
+		reflect.String:  c.processString,
+		reflect.Int:     c.processInt,
+		reflect.Int8:    c.processInt,
+		reflect.Int16:   c.processInt,
+		reflect.Int32:   c.processInt,
+		reflect.Int64:   c.processInt,
+		reflect.Uint:    c.processUint,
+		reflect.Uint8:   c.processUint,
+		reflect.Uint16:  c.processUint,
+		reflect.Uint32:  c.processUint,
+		reflect.Uint64:  c.processUint,
+		reflect.Float32: c.processFloat,
+		reflect.Float64: c.processFloat,
+		reflect.Bool:    c.processBool,
+		reflect.Map:     c.processMap,
+		reflect.Slice:   c.processSlice,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// With method creates new Convertor instance with options of this one and provided options applied after them.
+func (c *Convertor) With(opts ...Option) *Convertor {
+	return New(append(append([]Option(nil), c.opts...), opts...)...)
+}
+
+// WithCollectErrors option makes convertor continue conversion after errors and return all of them at once
+// as MultiError. By default conversion stops at the first error.
+func WithCollectErrors() Option {
+	return func(c *Convertor) {
+		c.collectErrors = true
+	}
+}
+
+// WithStringConversion option makes convertor fill numeric and bool fields from strings like '8080' or 'true', such as
+// resolved references or values of environment variables. By default such strings fail conversion with
+// ErrMismatchedTypes error.
+func WithStringConversion() Option {
+	return func(c *Convertor) {
+		c.convertStrings = true
+	}
+}
+
+// WithIgnoreUnknownFields option makes convertor skip data keys that don't match any structure field instead of
+// failing with ErrUnknownField error.
+func WithIgnoreUnknownFields() Option {
+	return func(c *Convertor) {
+		c.unknownFieldHandler = func(error) {}
+	}
+}
+
+// WithUnknownFieldHandler option makes convertor skip data keys that don't match any structure field and call
+// provided handler with ErrUnknownField error for each of them, for example to log a warning.
+func WithUnknownFieldHandler(handler func(err error)) Option {
+	return func(c *Convertor) {
+		c.unknownFieldHandler = handler
+	}
+}
+
 // Convert method converts raw data in 'data' parameter into structure (or slice of structures) that 'out' parameter
 // points to.
-// It will return an error if the structure doesn't have some field or it is not of an appropriate type.
+// It will return an error if the structure doesn't have some field or it is not of an appropriate type, or if data key
+// matches several fields, or several data keys match the same field (ErrAmbiguousField). Keys are matched with field
+// names according to the name mapping strategy, see WithNameMapping option.
+// Conversion errors are of FieldError type, which holds full key path of the data and types of data and output.
+//
+// Output structure fields can be customized with tags:
+//
+//	type Config struct {
+//	    ReadTimeout int    `config:"read_timeout"` // filled from key 'read_timeout' instead of 'readTimeout'
+//	    Address     string `config:",required"`    // conversion fails if key 'address' is missing
+//	    Name        string `default:"server"`      // used if key 'name' is missing
+//	    Internal    string `config:"-"`           // never filled
+//	}
+//
+// Key with null value, such as 'port: null' in YAML, is treated as missing one, so default value is used for it and
+// required field fails conversion. Null elements of lists and maps are converted into zero values.
+//
+// Besides basic types, output can contain unsigned integers, pointers, maps with string keys, time.Duration (parsed
+// from strings like '1500ms', bare numbers are rejected), time.Time (parsed from RFC3339 strings) and any types
+// implementing encoding.TextUnmarshaler or Unmarshaler interfaces. Data that already has the output type, such as
+// native TOML datetime, is assigned as is. Numeric and bool fields are filled from strings only if convertor is
+// created with WithStringConversion option.
 func (c *Convertor) Convert(data interface{}, out interface{}) error {
+	return c.ConvertWithPath("", data, out)
+}
+
+// ConvertWithPath method does the same as Convert, but treats 'data' as lying under provided key path.
+// That path is used only in error messages.
+func (c *Convertor) ConvertWithPath(path string, data interface{}, out interface{}) error {
 	outValue := reflect.ValueOf(out)
 	if outValue.Kind() != reflect.Ptr {
 		return ErrOutputNotPointer
 	}
 
-	return c.process(reflect.ValueOf(data), outValue.Elem())
+	return c.process(path, reflect.ValueOf(data), outValue.Elem())
 }
 
-func (c *Convertor) process(dataValue reflect.Value, outValue reflect.Value) error {
+func (c *Convertor) process(path string, dataValue reflect.Value, outValue reflect.Value) error {
 	if dataValue.Kind() == reflect.Interface {
 		dataValue = dataValue.Elem()
 	}
 
-	if processFunc, ok := c.processFuncMap[dataValue.Kind()]; ok {
-		if err := processFunc(dataValue, outValue); err != nil {
-			return err
+	if !dataValue.IsValid() {
+		outValue.Set(reflect.Zero(outValue.Type()))
+		return nil
+	}
+
+	if outValue.Kind() == reflect.Ptr {
+		if outValue.IsNil() {
+			outValue.Set(reflect.New(outValue.Type().Elem()))
+		}
+
+		return c.process(path, dataValue, outValue.Elem())
+	}
+
+	if isScalarKind(dataValue.Kind()) && dataValue.Type().AssignableTo(outValue.Type()) {
+		outValue.Set(dataValue)
+		return nil
+	}
+
+	if ok, err := c.processUnmarshaler(path, dataValue, outValue); ok {
+		return err
+	}
+
+	if outValue.Kind() == reflect.Interface && dataValue.Type().AssignableTo(outValue.Type()) {
+		outValue.Set(dataValue)
+		return nil
+	}
+
+	processFunc, ok := c.processFuncMap[dataValue.Kind()]
+	if !ok {
+		return newTypedFieldError(ErrUnsupportedType, path, dataValue, outValue)
+	}
+
+	err := processFunc(path, dataValue, outValue)
+	if err == ErrMismatchedTypes { //nolint:errorlint // only bare error is wrapped here, others already have path
+		return newTypedFieldError(ErrMismatchedTypes, path, dataValue, outValue)
+	}
+
+	return err
+}
+
+func (c *Convertor) processString(path string, dataValue reflect.Value, outValue reflect.Value) error {
+	if outValue.Type() == durationType {
+		duration, err := time.ParseDuration(dataValue.String())
+		if err != nil {
+			return newFieldError(ErrInvalidValue, path, err.Error())
+		}
+
+		outValue.SetInt(int64(duration))
+
+		return nil
+	}
+
+	if outValue.Kind() == reflect.String {
+		outValue.SetString(dataValue.String())
+		return nil
+	}
+
+	if !c.convertStrings {
+		return ErrMismatchedTypes
+	}
+
+	return c.processConvertedString(path, dataValue.String(), outValue)
+}
+
+// processConvertedString method fills numeric or bool output value from its string representation, see
+// WithStringConversion option.
+func (c *Convertor) processConvertedString(path string, data string, outValue reflect.Value) error {
+	switch outValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(data, 10, 64)
+		if err != nil {
+			return ErrMismatchedTypes
+		}
+
+		return c.processInt(path, reflect.ValueOf(value), outValue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value, err := strconv.ParseUint(data, 10, 64)
+		if err != nil {
+			return ErrMismatchedTypes
+		}
+
+		return c.processUint(path, reflect.ValueOf(value), outValue)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(data, 64)
+		if err != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+			return ErrMismatchedTypes
+		}
+
+		return c.processFloat(path, reflect.ValueOf(value), outValue)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(data)
+		if err != nil {
+			return ErrMismatchedTypes
 		}
-	} else {
-		return errors.NewFmt("unsupported type '%v'", dataValue.Kind()).WithCause(ErrUnsupportedType)
+
+		outValue.SetBool(value)
+	default:
+		return ErrMismatchedTypes
 	}
 
 	return nil
 }
 
-func (c *Convertor) processString(dataValue reflect.Value, outValue reflect.Value) error {
-	if outValue.Kind() != reflect.String {
+func (c *Convertor) processInt(path string, dataValue reflect.Value, outValue reflect.Value) error {
+	if outValue.Type() == durationType {
 		return ErrMismatchedTypes
 	}
 
-	outValue.SetString(dataValue.String())
+	value := dataValue.Int()
+
+	switch outValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if outValue.OverflowInt(value) {
+			return outOfRangeError(path, value, outValue)
+		}
+
+		outValue.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value < 0 || outValue.OverflowUint(uint64(value)) {
+			return outOfRangeError(path, value, outValue)
+		}
+
+		outValue.SetUint(uint64(value))
+	case reflect.Float32, reflect.Float64:
+		outValue.SetFloat(float64(value))
+	default:
+		return ErrMismatchedTypes
+	}
 
 	return nil
 }
 
-func (c *Convertor) processInt(dataValue reflect.Value, outValue reflect.Value) error {
+func (c *Convertor) processUint(path string, dataValue reflect.Value, outValue reflect.Value) error {
+	if outValue.Type() == durationType {
+		return ErrMismatchedTypes
+	}
+
+	value := dataValue.Uint()
+
 	switch outValue.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		outValue.SetInt(dataValue.Int())
+		if value > math.MaxInt64 || outValue.OverflowInt(int64(value)) {
+			return outOfRangeError(path, value, outValue)
+		}
+
+		outValue.SetInt(int64(value))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if outValue.OverflowUint(value) {
+			return outOfRangeError(path, value, outValue)
+		}
+
+		outValue.SetUint(value)
 	case reflect.Float32, reflect.Float64:
-		outValue.SetFloat(float64(dataValue.Int()))
+		outValue.SetFloat(float64(value))
 	default:
 		return ErrMismatchedTypes
 	}
@@ -99,12 +337,32 @@ func (c *Convertor) processInt(dataValue reflect.Value, outValue reflect.Value)
 	return nil
 }
 
-func (c *Convertor) processFloat(dataValue reflect.Value, outValue reflect.Value) error {
+func (c *Convertor) processFloat(path string, dataValue reflect.Value, outValue reflect.Value) error {
+	if outValue.Type() == durationType {
+		return ErrMismatchedTypes
+	}
+
+	value := dataValue.Float()
+
 	switch outValue.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		outValue.SetInt(int64(dataValue.Float()))
+		if value < math.MinInt64 || value >= math.MaxInt64 || outValue.OverflowInt(int64(value)) {
+			return outOfRangeError(path, value, outValue)
+		}
+
+		outValue.SetInt(int64(value))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value < 0 || value >= math.MaxUint64 || outValue.OverflowUint(uint64(value)) {
+			return outOfRangeError(path, value, outValue)
+		}
+
+		outValue.SetUint(uint64(value))
 	case reflect.Float32, reflect.Float64:
-		outValue.SetFloat(dataValue.Float())
+		if outValue.OverflowFloat(value) {
+			return outOfRangeError(path, value, outValue)
+		}
+
+		outValue.SetFloat(value)
 	default:
 		return ErrMismatchedTypes
 	}
@@ -112,7 +370,7 @@ func (c *Convertor) processFloat(dataValue reflect.Value, outValue reflect.Value
 	return nil
 }
 
-func (c *Convertor) processBool(dataValue reflect.Value, outValue reflect.Value) error {
+func (c *Convertor) processBool(_ string, dataValue reflect.Value, outValue reflect.Value) error {
 	if outValue.Kind() != reflect.Bool {
 		return ErrMismatchedTypes
 	}
@@ -122,58 +380,163 @@ func (c *Convertor) processBool(dataValue reflect.Value, outValue reflect.Value)
 	return nil
 }
 
-func (c *Convertor) processMap(dataValue reflect.Value, outValue reflect.Value) error {
-	if outValue.Kind() == reflect.Map && outValue.Type().Elem().Kind() == dataValue.Type().Elem().Kind() {
-		outValue.Set(dataValue)
-		return nil
+func (c *Convertor) processMap(path string, dataValue reflect.Value, outValue reflect.Value) error {
+	if outValue.Kind() == reflect.Map {
+		return c.processMapToMap(path, dataValue, outValue)
 	}
 
 	if outValue.Kind() != reflect.Struct {
 		return ErrMismatchedTypes
 	}
 
+	plan := c.structPlan(outValue.Type())
+	fields := plan.fields
+	filledKeys := make([]filledKey, len(fields))
+
+	var errs []error
+
 	mapIter := dataValue.MapRange()
 	for mapIter.Next() {
-		mapKey, mapValue := mapIter.Key(), mapIter.Value()
+		if err := c.processStructField(path, mapIter.Key(), mapIter.Value(), plan, filledKeys, outValue); err != nil {
+			if !c.collectErrors {
+				return err
+			}
 
-		if mapKey.Kind() != reflect.String {
-			return ErrMapKeyNotString
+			errs = appendError(errs, err)
 		}
+	}
+
+	for i := range fields {
+		if !filledKeys[i].filled {
+			if err := c.processMissing(path, &fields[i], outValue.FieldByIndex(fields[i].index)); err != nil {
+				if !c.collectErrors {
+					return err
+				}
 
-		if mapValue.Kind() == reflect.Interface {
-			mapValue = mapValue.Elem()
+				errs = appendError(errs, err)
+			}
 		}
+	}
+
+	return multiError(errs)
+}
 
-		mapKeyBytes := []byte(mapKey.String())
-		if len(mapKeyBytes) > 0 {
-			mapKeyBytes[0] -= 32
+// processStructField method fills structure field that matches provided map key. Keys of already filled fields are
+// tracked to detect several keys matching the same field.
+func (c *Convertor) processStructField(path string, mapKey reflect.Value, mapValue reflect.Value, plan *structPlan,
+	filledKeys []filledKey, outValue reflect.Value,
+) error {
+	if mapKey.Kind() == reflect.Interface {
+		mapKey = mapKey.Elem()
+	}
+
+	if mapKey.Kind() != reflect.String {
+		return newTypedFieldError(ErrMapKeyNotString, path, mapKey, reflect.ValueOf(""))
+	}
+
+	key := mapKey.String()
+	fieldPath := joinPath(path, key)
+
+	fields := plan.fields
+	fieldIndexes := plan.find(key, c.nameMapping)
+
+	switch len(fieldIndexes) {
+	case 0:
+		err := newFieldError(ErrUnknownField, fieldPath, "no such field in "+outValue.Type().String())
+		if c.unknownFieldHandler != nil {
+			c.unknownFieldHandler(err)
+			return nil
 		}
 
-		fieldValue := outValue.FieldByName(string(mapKeyBytes))
-		if !fieldValue.IsValid() {
-			return errors.NewFmt("unknown field '%s'", mapKey.String()).WithCause(ErrUnknownField)
+		return err
+	case 1:
+	default:
+		return newFieldError(ErrAmbiguousField, fieldPath, "key matches fields "+
+			fieldNames(outValue.Type(), fields, fieldIndexes)+" of "+outValue.Type().String())
+	}
+
+	fieldIndex := fieldIndexes[0]
+
+	if filledKeys[fieldIndex].filled {
+		keys := []string{filledKeys[fieldIndex].key, key}
+		sort.Strings(keys)
+
+		return newFieldError(ErrAmbiguousField, path, "keys '"+keys[0]+"' and '"+keys[1]+"' match the same field "+
+			fieldNames(outValue.Type(), fields, fieldIndexes)+" of "+outValue.Type().String())
+	}
+
+	filledKeys[fieldIndex] = filledKey{key: key, filled: true}
+
+	if mapValue.Kind() == reflect.Interface && mapValue.IsNil() {
+		return c.processMissing(path, &fields[fieldIndex], outValue.FieldByIndex(fields[fieldIndex].index))
+	}
+
+	return c.process(fieldPath, mapValue, outValue.FieldByIndex(fields[fieldIndex].index))
+}
+
+// processMapToMap method converts data map into output map with string keys, converting each element separately
+// unless data map is already of the appropriate type.
+func (c *Convertor) processMapToMap(path string, dataValue reflect.Value, outValue reflect.Value) error {
+	if dataValue.Type().AssignableTo(outValue.Type()) {
+		outValue.Set(dataValue)
+		return nil
+	}
+
+	if outValue.Type().Key().Kind() != reflect.String {
+		return ErrMismatchedTypes
+	}
+
+	mapValue := reflect.MakeMapWithSize(outValue.Type(), dataValue.Len())
+
+	var errs []error
+
+	mapIter := dataValue.MapRange()
+	for mapIter.Next() {
+		mapKey := mapIter.Key()
+		if mapKey.Kind() == reflect.Interface {
+			mapKey = mapKey.Elem()
+		}
+
+		if mapKey.Kind() != reflect.String {
+			return newTypedFieldError(ErrMapKeyNotString, path, mapKey, reflect.ValueOf(""))
 		}
 
-		if err := c.process(mapValue, fieldValue); err != nil {
-			return err
+		elemValue := reflect.New(outValue.Type().Elem())
+
+		if err := c.process(joinPath(path, mapKey.String()), mapIter.Value(), elemValue.Elem()); err != nil {
+			if !c.collectErrors {
+				return err
+			}
+
+			errs = appendError(errs, err)
 		}
+
+		mapValue.SetMapIndex(mapKey.Convert(outValue.Type().Key()), elemValue.Elem())
 	}
 
-	return nil
+	outValue.Set(mapValue)
+
+	return multiError(errs)
 }
 
-func (c *Convertor) processSlice(dataValue reflect.Value, outValue reflect.Value) error {
+func (c *Convertor) processSlice(path string, dataValue reflect.Value, outValue reflect.Value) error {
 	if outValue.Kind() != reflect.Slice {
 		return ErrMismatchedTypes
 	}
 
 	sliceValue := reflect.MakeSlice(outValue.Type(), 0, dataValue.Len())
 
+	var errs []error
+
 	for i := 0; i < dataValue.Len(); i++ {
 		elemValue := reflect.New(outValue.Type().Elem())
 
-		if err := c.process(dataValue.Index(i), elemValue.Elem()); err != nil {
-			return err
+		if err := c.process(joinPath(path, strconv.Itoa(i)), dataValue.Index(i), elemValue.Elem()); err != nil {
+			if !c.collectErrors {
+				return err
+			}
+
+			errs = appendError(errs, err)
 		}
 
 		sliceValue = reflect.Append(sliceValue, elemValue.Elem())
@@ -181,5 +544,81 @@ func (c *Convertor) processSlice(dataValue reflect.Value, outValue reflect.Value
 
 	outValue.Set(sliceValue)
 
+	return multiError(errs)
+}
+
+// processMissing method handles structure field that has no data: fills it with default value if any, or fails
+// if it is required. Nested structures are handled recursively.
+func (c *Convertor) processMissing(path string, field *field, outValue reflect.Value) error {
+	fieldPath := joinPath(path, field.keyName(c.nameMapping))
+
+	if field.required {
+		return newFieldError(ErrRequiredField, fieldPath, "")
+	}
+
+	if field.hasDefault {
+		return c.processDefault(fieldPath, field.defaultValue, outValue)
+	}
+
+	if outValue.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := c.structPlan(outValue.Type()).fields
+
+	var errs []error
+
+	for i := range fields {
+		if err := c.processMissing(fieldPath, &fields[i], outValue.FieldByIndex(fields[i].index)); err != nil {
+			if !c.collectErrors {
+				return err
+			}
+
+			errs = appendError(errs, err)
+		}
+	}
+
+	return multiError(errs)
+}
+
+// processDefault method parses default value defined in field tag according to the field type and fills field with it.
+func (c *Convertor) processDefault(path string, defaultValue string, outValue reflect.Value) error {
+	var value interface{}
+	var err error
+
+	switch {
+	case outValue.Kind() == reflect.Ptr:
+		if outValue.IsNil() {
+			outValue.Set(reflect.New(outValue.Type().Elem()))
+		}
+
+		return c.processDefault(path, defaultValue, outValue.Elem())
+	case isTextType(outValue):
+		value = defaultValue
+	case outValue.CanInt():
+		value, err = strconv.ParseInt(defaultValue, 10, 64)
+	case outValue.CanUint():
+		value, err = strconv.ParseUint(defaultValue, 10, 64)
+	case outValue.CanFloat():
+		value, err = strconv.ParseFloat(defaultValue, 64)
+	case outValue.Kind() == reflect.Bool:
+		value, err = strconv.ParseBool(defaultValue)
+	default:
+		value = defaultValue
+	}
+
+	if err == nil {
+		err = c.process(path, reflect.ValueOf(value), outValue)
+	}
+
+	if err != nil {
+		return newFieldError(ErrInvalidDefault, path, "can't use '"+defaultValue+"' as "+outValue.Type().String())
+	}
+
 	return nil
 }
+
+// outOfRangeError function creates error about numeric value that doesn't fit into output field.
+func outOfRangeError(path string, value interface{}, outValue reflect.Value) error {
+	return newFieldError(ErrOutOfRange, path, fmt.Sprintf("value %v doesn't fit into %s", value, outValue.Type()))
+}
@@ -0,0 +1,134 @@
+package i2s_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lightstar/golib/pkg/config/i2s"
+)
+
+type benchLimits struct {
+	MaxConns    int
+	MaxIdle     int
+	IdleTimeout time.Duration
+	Weight      float64
+}
+
+type benchServer struct {
+	Name     string `config:",required"`
+	Address  string
+	Port     int `default:"80"`
+	Enabled  bool
+	Tags     []string
+	Limits   benchLimits
+	Labels   map[string]string
+	Backends []benchBackend
+}
+
+type benchBackend struct {
+	Host    string
+	Port    uint16
+	Weight  float64
+	Timeout time.Duration `default:"1s"`
+	Backup  *bool
+}
+
+type benchConfig struct {
+	App struct {
+		Name    string
+		Version string
+		Debug   bool
+	}
+	HTTP struct {
+		ReadTimeout  time.Duration
+		WriteTimeout time.Duration
+		Servers      []benchServer
+	}
+}
+
+func benchData(servers int, backends int) map[string]interface{} {
+	serverList := make([]interface{}, 0, servers)
+
+	for i := 0; i < servers; i++ {
+		backendList := make([]interface{}, 0, backends)
+
+		for j := 0; j < backends; j++ {
+			backendList = append(backendList, map[string]interface{}{
+				"host":   "10.0.0." + strconv.Itoa(j),
+				"port":   8080 + j,
+				"weight": 0.5,
+				"backup": j%2 == 0,
+			})
+		}
+
+		serverList = append(serverList, map[string]interface{}{
+			"name":    "server" + strconv.Itoa(i),
+			"address": "0.0.0.0",
+			"enabled": true,
+			"tags":    []interface{}{"a", "b", "c"},
+			"limits": map[string]interface{}{
+				"maxConns":    100,
+				"maxIdle":     10,
+				"idleTimeout": "30s",
+				"weight":      1.5,
+			},
+			"labels":   map[string]interface{}{"zone": "eu", "tier": "web"},
+			"backends": backendList,
+		})
+	}
+
+	return map[string]interface{}{
+		"app": map[string]interface{}{"name": "bench", "version": "1.0.0", "debug": false},
+		"hTTP": map[string]interface{}{
+			"readTimeout":  "5s",
+			"writeTimeout": "10s",
+			"servers":      serverList,
+		},
+	}
+}
+
+func benchmarkConvert(b *testing.B, convertor *i2s.Convertor, data map[string]interface{}) {
+	b.Helper()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var out benchConfig
+
+		if err := convertor.Convert(data, &out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkConvertSmall(b *testing.B) {
+	benchmarkConvert(b, i2s.Instance(), benchData(1, 1))
+}
+
+func BenchmarkConvertLarge(b *testing.B) {
+	benchmarkConvert(b, i2s.Instance(), benchData(50, 20))
+}
+
+func BenchmarkConvertLargeSnakeCase(b *testing.B) {
+	benchmarkConvert(b, i2s.New(i2s.WithNameMapping(i2s.SnakeCaseMapping)), benchData(50, 20))
+}
+
+func BenchmarkConvertLargeParallel(b *testing.B) {
+	convertor := i2s.Instance()
+	data := benchData(50, 20)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			var out benchConfig
+
+			if err := convertor.Convert(data, &out); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
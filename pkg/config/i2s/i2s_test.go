@@ -1,7 +1,12 @@
 package i2s_test
 
 import (
+	"net"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -267,52 +272,60 @@ func TestErrors(t *testing.T) {
 			err:  i2s.ErrOutputNotPointer,
 		},
 		{
-			name: "String2Int",
-			in:   map[string]interface{}{"key": "value"},
-			out:  &struct{ Key int }{},
-			err:  i2s.ErrMismatchedTypes,
+			name:    "String2Int",
+			in:      map[string]interface{}{"key": "value"},
+			out:     &struct{ Key int }{},
+			err:     i2s.ErrMismatchedTypes,
+			wrapped: true,
 		},
 		{
-			name: "Int2String",
-			in:   map[string]interface{}{"key": 5},
-			out:  &struct{ Key string }{},
-			err:  i2s.ErrMismatchedTypes,
+			name:    "Int2String",
+			in:      map[string]interface{}{"key": 5},
+			out:     &struct{ Key string }{},
+			err:     i2s.ErrMismatchedTypes,
+			wrapped: true,
 		},
 		{
-			name: "Float2String",
-			in:   map[string]interface{}{"key": 5.},
-			out:  &struct{ Key string }{},
-			err:  i2s.ErrMismatchedTypes,
+			name:    "Float2String",
+			in:      map[string]interface{}{"key": 5.},
+			out:     &struct{ Key string }{},
+			err:     i2s.ErrMismatchedTypes,
+			wrapped: true,
 		},
 		{
-			name: "Bool2String",
-			in:   map[string]interface{}{"key": true},
-			out:  &struct{ Key string }{},
-			err:  i2s.ErrMismatchedTypes,
+			name:    "Bool2String",
+			in:      map[string]interface{}{"key": true},
+			out:     &struct{ Key string }{},
+			err:     i2s.ErrMismatchedTypes,
+			wrapped: true,
 		},
 		{
-			name: "Map2String",
-			in:   map[string]interface{}{"key": map[string]interface{}{}},
-			out:  &struct{ Key string }{},
-			err:  i2s.ErrMismatchedTypes,
+			name:    "Map2String",
+			in:      map[string]interface{}{"key": map[string]interface{}{}},
+			out:     &struct{ Key string }{},
+			err:     i2s.ErrMismatchedTypes,
+			wrapped: true,
 		},
 		{
-			name: "Map2Map",
-			in:   map[string]interface{}{"key": "value"},
-			out:  &map[string]string{},
-			err:  i2s.ErrMismatchedTypes,
+			name:    "Map2Map",
+			in:      map[string]interface{}{"key": "value"},
+			out:     &map[string]int{},
+			err:     i2s.ErrMismatchedTypes,
+			wrapped: true,
 		},
 		{
-			name: "Slice2String",
-			in:   map[string]interface{}{"key": []interface{}{}},
-			out:  &struct{ Key string }{},
-			err:  i2s.ErrMismatchedTypes,
+			name:    "Slice2String",
+			in:      map[string]interface{}{"key": []interface{}{}},
+			out:     &struct{ Key string }{},
+			err:     i2s.ErrMismatchedTypes,
+			wrapped: true,
 		},
 		{
-			name: "IntMapKey",
-			in:   map[string]interface{}{"key": map[int]interface{}{0: "data"}},
-			out:  &struct{ Key struct{} }{},
-			err:  i2s.ErrMapKeyNotString,
+			name:    "IntMapKey",
+			in:      map[string]interface{}{"key": map[int]interface{}{0: "data"}},
+			out:     &struct{ Key struct{} }{},
+			err:     i2s.ErrMapKeyNotString,
+			wrapped: true,
 		},
 		{
 			name:    "MissedField",
@@ -351,3 +364,416 @@ func TestErrors(t *testing.T) {
 		}(test)
 	}
 }
+
+func TestTags(t *testing.T) {
+	convertor := i2s.Instance()
+
+	type Inner struct {
+		Port    int    `default:"8080"`
+		Host    string `config:"host_name" default:"localhost"`
+		Enabled bool   `default:"true"`
+	}
+
+	type Embedded struct {
+		Weight float64 `config:"weight" default:"1.5"`
+	}
+
+	type Out struct {
+		Embedded
+		ReadTimeout int    `config:"read_timeout"`
+		Address     string `config:",required"`
+		Name        string `default:"server"`
+		Internal    string `config:"-"`
+		Inner       Inner
+	}
+
+	var out Out
+
+	err := convertor.Convert(map[string]interface{}{
+		"read_timeout": 5,
+		"address":      "127.0.0.1",
+	}, &out)
+	require.NoError(t, err)
+	require.Equal(t, Out{
+		Embedded:    Embedded{Weight: 1.5},
+		ReadTimeout: 5,
+		Address:     "127.0.0.1",
+		Name:        "server",
+		Inner:       Inner{Port: 8080, Host: "localhost", Enabled: true},
+	}, out)
+
+	out = Out{}
+
+	err = convertor.Convert(map[string]interface{}{
+		"address": "127.0.0.1",
+		"name":    "custom",
+		"weight":  2.,
+		"inner":   map[string]interface{}{"host_name": "example.com"},
+	}, &out)
+	require.NoError(t, err)
+	require.Equal(t, Out{
+		Embedded: Embedded{Weight: 2},
+		Address:  "127.0.0.1",
+		Name:     "custom",
+		Inner:    Inner{Port: 8080, Host: "example.com", Enabled: true},
+	}, out)
+}
+
+func TestNullValues(t *testing.T) {
+	convertor := i2s.Instance()
+
+	type Inner struct {
+		Host string `default:"localhost"`
+	}
+
+	type Out struct {
+		Port    int    `default:"8080"`
+		Address string `config:",required"`
+		Name    string
+		Inner   Inner
+		Limits  []int
+	}
+
+	out := Out{Name: "server"}
+
+	err := convertor.Convert(map[string]interface{}{
+		"port":    nil,
+		"address": "127.0.0.1",
+		"name":    nil,
+		"inner":   nil,
+		"limits":  []interface{}{1, nil},
+	}, &out)
+	require.NoError(t, err)
+	require.Equal(t, Out{
+		Port:    8080,
+		Address: "127.0.0.1",
+		Name:    "server",
+		Inner:   Inner{Host: "localhost"},
+		Limits:  []int{1, 0},
+	}, out)
+
+	err = convertor.ConvertWithPath("http", map[string]interface{}{"address": nil}, &Out{})
+	require.ErrorIs(t, err, i2s.ErrRequiredField)
+	require.ErrorContains(t, err, "'http.address'")
+}
+
+func TestTagsErrors(t *testing.T) {
+	convertor := i2s.Instance()
+
+	type Inner struct {
+		Address string `config:",required"`
+	}
+
+	var out struct {
+		Inner Inner
+	}
+
+	err := convertor.ConvertWithPath("http", map[string]interface{}{
+		"inner": map[string]interface{}{},
+	}, &out)
+	require.ErrorIs(t, err, i2s.ErrRequiredField)
+	require.ErrorContains(t, err, "'http.inner.address'")
+
+	err = convertor.Convert(map[string]interface{}{}, &out)
+	require.ErrorIs(t, err, i2s.ErrRequiredField)
+	require.ErrorContains(t, err, "'inner.address'")
+
+	err = convertor.Convert(map[string]interface{}{"readTimeout": 5}, &struct {
+		ReadTimeout int `config:"read_timeout"`
+	}{})
+	require.ErrorIs(t, err, i2s.ErrUnknownField)
+
+	err = convertor.Convert(map[string]interface{}{"internal": "value"}, &struct {
+		Internal string `config:"-"`
+	}{})
+	require.ErrorIs(t, err, i2s.ErrUnknownField)
+
+	err = convertor.Convert(map[string]interface{}{}, &struct {
+		Port int `default:"port"`
+	}{})
+	require.ErrorIs(t, err, i2s.ErrInvalidDefault)
+	require.ErrorContains(t, err, "'port'")
+}
+
+type upperString string
+
+func (s *upperString) UnmarshalI2S(data interface{}) error {
+	str, ok := data.(string)
+	if !ok {
+		return i2s.ErrMismatchedTypes
+	}
+
+	*s = upperString(strings.ToUpper(str))
+
+	return nil
+}
+
+func TestTypes(t *testing.T) {
+	convertor := i2s.Instance()
+
+	type Out struct {
+		Timeout  time.Duration
+		Delay    time.Duration `default:"1500ms"`
+		Created  time.Time
+		Port     uint16
+		Size     uint64
+		Count    *int
+		Name     *string `default:"server"`
+		Limits   map[string]int
+		Children map[string]struct{ Age int }
+		IP       net.IP
+		Upper    upperString
+		Any      interface{}
+	}
+
+	var out Out
+
+	err := convertor.Convert(map[string]interface{}{
+		"timeout":  "2m",
+		"created":  "2023-04-01T10:20:30Z",
+		"port":     8080,
+		"size":     uint64(1 << 40),
+		"count":    5,
+		"limits":   map[string]interface{}{"cpu": 2, "memory": 512.},
+		"children": map[string]interface{}{"george": map[string]interface{}{"age": 5}},
+		"IP":       "127.0.0.1",
+		"upper":    "value",
+		"any":      []interface{}{"a", 1},
+	}, &out)
+	require.NoError(t, err)
+
+	count, name := 5, "server"
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var native struct{ Created time.Time }
+
+	require.NoError(t, convertor.Convert(map[string]interface{}{"created": created}, &native))
+	require.Equal(t, created, native.Created)
+
+	require.Equal(t, Out{
+		Timeout:  2 * time.Minute,
+		Delay:    1500 * time.Millisecond,
+		Created:  time.Date(2023, 4, 1, 10, 20, 30, 0, time.UTC),
+		Port:     8080,
+		Size:     1 << 40,
+		Count:    &count,
+		Name:     &name,
+		Limits:   map[string]int{"cpu": 2, "memory": 512},
+		Children: map[string]struct{ Age int }{"george": {Age: 5}},
+		IP:       net.ParseIP("127.0.0.1"),
+		Upper:    "VALUE",
+		Any:      []interface{}{"a", 1},
+	}, out)
+}
+
+func TestTypesErrors(t *testing.T) {
+	convertor := i2s.Instance()
+
+	err := convertor.Convert(map[string]interface{}{"port": 70000}, &struct{ Port uint16 }{})
+	require.ErrorIs(t, err, i2s.ErrOutOfRange)
+	require.ErrorContains(t, err, "'port'")
+
+	err = convertor.Convert(map[string]interface{}{"port": -1}, &struct{ Port uint }{})
+	require.ErrorIs(t, err, i2s.ErrOutOfRange)
+
+	err = convertor.Convert(map[string]interface{}{"port": 300.}, &struct{ Port int8 }{})
+	require.ErrorIs(t, err, i2s.ErrOutOfRange)
+
+	err = convertor.Convert(map[string]interface{}{"port": uint64(1 << 63)}, &struct{ Port int64 }{})
+	require.ErrorIs(t, err, i2s.ErrOutOfRange)
+
+	err = convertor.Convert(map[string]interface{}{"timeout": "soon"}, &struct{ Timeout time.Duration }{})
+	require.ErrorIs(t, err, i2s.ErrInvalidValue)
+	require.ErrorContains(t, err, "'timeout'")
+
+	for _, timeout := range []interface{}{5, uint64(5), 5.} {
+		err = convertor.Convert(map[string]interface{}{"timeout": timeout}, &struct{ Timeout time.Duration }{})
+		require.ErrorIs(t, err, i2s.ErrMismatchedTypes)
+		require.ErrorContains(t, err, "expected time.Duration")
+	}
+
+	err = convertor.Convert(map[string]interface{}{"created": "yesterday"}, &struct{ Created time.Time }{})
+	require.ErrorIs(t, err, i2s.ErrInvalidValue)
+
+	err = convertor.Convert(map[string]interface{}{"created": 5}, &struct{ Created time.Time }{})
+	require.ErrorIs(t, err, i2s.ErrMismatchedTypes)
+
+	err = convertor.Convert(map[string]interface{}{"upper": 5}, &struct{ Upper upperString }{})
+	require.ErrorIs(t, err, i2s.ErrInvalidValue)
+
+	err = convertor.Convert(map[string]interface{}{"limits": map[string]interface{}{"cpu": "all"}},
+		&struct{ Limits map[string]int }{})
+	require.ErrorIs(t, err, i2s.ErrMismatchedTypes)
+
+	err = convertor.Convert(map[string]interface{}{}, &struct {
+		Timeout time.Duration `default:"soon"`
+	}{})
+	require.ErrorIs(t, err, i2s.ErrInvalidDefault)
+}
+
+func TestStringConversion(t *testing.T) {
+	type Out struct {
+		Port    uint16
+		Retries int
+		Ratio   float32
+		Debug   bool
+	}
+
+	data := map[string]interface{}{"port": "8080", "retries": "-1", "ratio": "0.5", "debug": "true"}
+
+	err := i2s.Instance().Convert(data, &Out{})
+	require.ErrorIs(t, err, i2s.ErrMismatchedTypes)
+	require.ErrorContains(t, err, "got string")
+
+	convertor := i2s.New(i2s.WithNameMapping(i2s.CaseInsensitiveMapping)).With(i2s.WithStringConversion())
+
+	var out Out
+
+	require.NoError(t, convertor.Convert(data, &out))
+	require.Equal(t, Out{Port: 8080, Retries: -1, Ratio: 0.5, Debug: true}, out)
+	require.Equal(t, i2s.CaseInsensitiveMapping, convertor.NameMapping())
+
+	err = convertor.Convert(map[string]interface{}{"port": "70000"}, &struct{ Port uint16 }{})
+	require.ErrorIs(t, err, i2s.ErrOutOfRange)
+
+	for _, value := range []string{"inf", "NaN", "1e", "yes"} {
+		err = convertor.Convert(map[string]interface{}{"ratio": value}, &struct{ Ratio float64 }{})
+		require.ErrorIs(t, err, i2s.ErrMismatchedTypes, value)
+	}
+
+	err = convertor.Convert(map[string]interface{}{"debug": "yes"}, &struct{ Debug bool }{})
+	require.ErrorIs(t, err, i2s.ErrMismatchedTypes)
+}
+
+func TestFieldErrors(t *testing.T) {
+	convertor := i2s.Instance()
+
+	var out struct {
+		Server struct {
+			TLS struct {
+				CertFile string
+			}
+		}
+	}
+
+	err := convertor.ConvertWithPath("http", map[string]interface{}{
+		"server": map[string]interface{}{
+			"tLS": map[string]interface{}{"certFile": 5},
+		},
+	}, &out)
+
+	var fieldErr *i2s.FieldError
+
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, "http.server.tLS.certFile", fieldErr.Path)
+	require.Equal(t, "string", fieldErr.Expected)
+	require.Equal(t, "int", fieldErr.Actual)
+	require.ErrorIs(t, err, i2s.ErrMismatchedTypes)
+	require.EqualError(t, err, "mismatched types at 'http.server.tLS.certFile' (expected string, got int)")
+
+	err = convertor.Convert(map[string]interface{}{
+		"children": []interface{}{map[string]interface{}{"age": "five"}},
+	}, &struct{ Children []struct{ Age int } }{})
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, "children.0.age", fieldErr.Path)
+
+	err = convertor.Convert(map[string]interface{}{"key": func() {}}, &struct{ Key func() }{})
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, "key", fieldErr.Path)
+	require.Equal(t, "func()", fieldErr.Actual)
+}
+
+func TestCollectErrors(t *testing.T) {
+	convertor := i2s.New(i2s.WithCollectErrors())
+
+	var out struct {
+		Name     string
+		Age      int
+		Address  string `config:",required"`
+		Children []struct{ Age int }
+		Limits   map[string]int
+	}
+
+	err := convertor.Convert(map[string]interface{}{
+		"name":     5,
+		"age":      30,
+		"unknown":  true,
+		"children": []interface{}{map[string]interface{}{"age": "five"}, map[string]interface{}{"age": 5}},
+		"limits":   map[string]interface{}{"cpu": "all"},
+	}, &out)
+
+	var multiErr *i2s.MultiError
+
+	require.ErrorAs(t, err, &multiErr)
+	require.Len(t, multiErr.Errors, 5)
+	require.ErrorIs(t, err, i2s.ErrMismatchedTypes)
+	require.ErrorIs(t, err, i2s.ErrUnknownField)
+	require.ErrorIs(t, err, i2s.ErrRequiredField)
+
+	paths := make([]string, 0, len(multiErr.Errors))
+
+	for _, e := range multiErr.Errors {
+		var fieldErr *i2s.FieldError
+
+		require.ErrorAs(t, e, &fieldErr)
+		paths = append(paths, fieldErr.Path)
+	}
+
+	require.ElementsMatch(t, []string{"name", "unknown", "children.0.age", "limits.cpu", "address"}, paths)
+	require.Equal(t, 30, out.Age)
+	require.Equal(t, 5, out.Children[1].Age)
+
+	require.NoError(t, convertor.Convert(map[string]interface{}{"address": "127.0.0.1"}, &out))
+}
+
+func TestConcurrentConvert(t *testing.T) {
+	convertors := []*i2s.Convertor{i2s.Instance(), i2s.New(i2s.WithNameMapping(i2s.SnakeCaseMapping))}
+	data := []map[string]interface{}{
+		{"readTimeout": "1s", "servers": []interface{}{map[string]interface{}{"maxConns": 5}}},
+		{"read_timeout": "1s", "servers": []interface{}{map[string]interface{}{"max_conns": 5}}},
+	}
+
+	type server struct {
+		MaxConns int
+		Name     string `default:"main"`
+	}
+
+	type config struct {
+		ReadTimeout time.Duration
+		Servers     []server
+	}
+
+	expected := config{ReadTimeout: time.Second, Servers: []server{{MaxConns: 5, Name: "main"}}}
+	errs := make(chan error, 100)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			var out config
+
+			if err := convertors[i%2].Convert(data[i%2], &out); err != nil {
+				errs <- err
+				return
+			}
+
+			if !reflect.DeepEqual(expected, out) {
+				errs <- errors.New("unexpected result")
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	require.ErrorIs(t, i2s.Instance().Convert(data[1], &config{}), i2s.ErrUnknownField)
+}
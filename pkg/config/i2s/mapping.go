@@ -0,0 +1,166 @@
+package i2s
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NameMapping is a strategy of matching data keys with names of structure fields. It applies only to fields without
+// name in 'config' tag, such fields always match exactly the same key.
+type NameMapping int
+
+const (
+	// FirstLetterMapping matches keys that are equal to field names with the first letter capitalized, so key
+	// 'readTimeout' fills field 'ReadTimeout'. It is the default strategy.
+	FirstLetterMapping NameMapping = iota
+	// ExactMapping matches only keys that are exactly equal to field names, so key 'ReadTimeout' fills field
+	// 'ReadTimeout'.
+	ExactMapping
+	// CaseInsensitiveMapping matches keys that are equal to field names ignoring case, so keys 'readTimeout',
+	// 'readtimeout' and 'READTIMEOUT' fill field 'ReadTimeout'.
+	CaseInsensitiveMapping
+	// SnakeCaseMapping matches keys in snake_case, kebab-case or camelCase. Words of the key are joined and matched
+	// ignoring case, so keys 'read_header_timeout', 'read-header-timeout' and 'readHeaderTimeout' fill field
+	// 'ReadHeaderTimeout', and key 'tls_ca_file' fills field 'TLSCAFile'. Field names are converted into snake_case
+	// keys in error messages.
+	SnakeCaseMapping
+	// KebabCaseMapping matches keys the same way as SnakeCaseMapping, but field names are converted into kebab-case
+	// keys in error messages.
+	KebabCaseMapping
+)
+
+// WithNameMapping option sets the strategy of matching data keys with structure field names. Default is
+// FirstLetterMapping.
+func WithNameMapping(mapping NameMapping) Option {
+	return func(c *Convertor) {
+		c.nameMapping = mapping
+	}
+}
+
+// NameMapping method retrieves the strategy of matching data keys with structure field names, see WithNameMapping
+// option.
+func (c *Convertor) NameMapping() NameMapping {
+	return c.nameMapping
+}
+
+// String method retrieves name of the strategy.
+func (mapping NameMapping) String() string {
+	switch mapping {
+	case FirstLetterMapping:
+		return "first-letter"
+	case ExactMapping:
+		return "exact"
+	case CaseInsensitiveMapping:
+		return "case-insensitive"
+	case SnakeCaseMapping:
+		return "snake-case"
+	case KebabCaseMapping:
+		return "kebab-case"
+	default:
+		return "unknown"
+	}
+}
+
+// Matches method checks if data key matches structure field name according to the strategy.
+func (mapping NameMapping) Matches(fieldName string, key string) bool {
+	return mapping.normalizeField(fieldName) == mapping.normalizeKey(key)
+}
+
+// KeyName method retrieves canonical data key for structure field name according to the strategy.
+func (mapping NameMapping) KeyName(fieldName string) string {
+	switch mapping {
+	case ExactMapping:
+		return fieldName
+	case SnakeCaseMapping:
+		return joinWords(fieldName, '_')
+	case KebabCaseMapping:
+		return joinWords(fieldName, '-')
+	case FirstLetterMapping, CaseInsensitiveMapping:
+		return uncapitalize(fieldName)
+	default:
+		return uncapitalize(fieldName)
+	}
+}
+
+// normalizeField method converts structure field name into the form that is compared with normalized data keys.
+func (mapping NameMapping) normalizeField(fieldName string) string {
+	switch mapping {
+	case CaseInsensitiveMapping, SnakeCaseMapping, KebabCaseMapping:
+		return strings.ToLower(fieldName)
+	case FirstLetterMapping, ExactMapping:
+		return fieldName
+	default:
+		return fieldName
+	}
+}
+
+// normalizeKey method converts data key into the form that is compared with normalized structure field names.
+func (mapping NameMapping) normalizeKey(key string) string {
+	return string(mapping.appendKey(nil, key))
+}
+
+// appendKey method appends normalized data key to the buffer, so it can be looked up without allocations.
+func (mapping NameMapping) appendKey(buf []byte, key string) []byte {
+	switch mapping {
+	case ExactMapping:
+		return append(buf, key...)
+	case CaseInsensitiveMapping, SnakeCaseMapping, KebabCaseMapping:
+		for _, r := range key {
+			if mapping != CaseInsensitiveMapping && (r == '_' || r == '-') {
+				continue
+			}
+
+			buf = utf8.AppendRune(buf, unicode.ToLower(r))
+		}
+
+		return buf
+	case FirstLetterMapping:
+		return appendCapitalized(buf, key)
+	default:
+		return appendCapitalized(buf, key)
+	}
+}
+
+// joinWords function splits CamelCase name into lower case words and joins them with separator. Sequences of upper
+// case letters are treated as acronyms, so 'TLSCAFile' becomes 'tlsca_file' and 'HTTPServer' becomes 'http_server'.
+func joinWords(name string, separator rune) string {
+	runes := []rune(name)
+
+	var builder strings.Builder
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				builder.WriteRune(separator)
+			}
+		}
+
+		builder.WriteRune(unicode.ToLower(r))
+	}
+
+	return builder.String()
+}
+
+// appendCapitalized function appends string with the first letter made upper case to the buffer.
+func appendCapitalized(buf []byte, s string) []byte {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return append(buf, s...)
+	}
+
+	return append(utf8.AppendRune(buf, unicode.ToUpper(r)), s[size:]...)
+}
+
+// uncapitalize function makes the first letter of the string lower case.
+func uncapitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+
+	return string(unicode.ToLower(r)) + s[size:]
+}
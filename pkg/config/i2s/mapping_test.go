@@ -0,0 +1,220 @@
+package i2s_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config/i2s"
+)
+
+type mappingServer struct {
+	ReadHeaderTimeout time.Duration
+	TLSCAFile         string
+	MaxConns          int
+	Name              string `config:"server_name"`
+}
+
+func TestNameMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		mapping  i2s.NameMapping
+		in       map[string]interface{}
+		expected mappingServer
+	}{
+		{
+			name:     "FirstLetter",
+			mapping:  i2s.FirstLetterMapping,
+			in:       map[string]interface{}{"readHeaderTimeout": "2s", "tLSCAFile": "ca.pem", "MaxConns": 5},
+			expected: mappingServer{ReadHeaderTimeout: 2 * time.Second, TLSCAFile: "ca.pem", MaxConns: 5},
+		},
+		{
+			name:     "Exact",
+			mapping:  i2s.ExactMapping,
+			in:       map[string]interface{}{"ReadHeaderTimeout": "2s", "TLSCAFile": "ca.pem"},
+			expected: mappingServer{ReadHeaderTimeout: 2 * time.Second, TLSCAFile: "ca.pem"},
+		},
+		{
+			name:     "CaseInsensitive",
+			mapping:  i2s.CaseInsensitiveMapping,
+			in:       map[string]interface{}{"READHEADERTIMEOUT": "2s", "tlscafile": "ca.pem", "maxConns": 5},
+			expected: mappingServer{ReadHeaderTimeout: 2 * time.Second, TLSCAFile: "ca.pem", MaxConns: 5},
+		},
+		{
+			name:    "SnakeCase",
+			mapping: i2s.SnakeCaseMapping,
+			in: map[string]interface{}{
+				"read_header_timeout": "2s", "tls_ca_file": "ca.pem", "maxConns": 5, "server_name": "app",
+			},
+			expected: mappingServer{ReadHeaderTimeout: 2 * time.Second, TLSCAFile: "ca.pem", MaxConns: 5, Name: "app"},
+		},
+		{
+			name:     "KebabCase",
+			mapping:  i2s.KebabCaseMapping,
+			in:       map[string]interface{}{"read-header-timeout": "2s", "tls-ca-file": "ca.pem", "max_conns": 5},
+			expected: mappingServer{ReadHeaderTimeout: 2 * time.Second, TLSCAFile: "ca.pem", MaxConns: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var out mappingServer
+
+			require.NoError(t, i2s.New(i2s.WithNameMapping(tt.mapping)).Convert(tt.in, &out))
+			require.Equal(t, tt.expected, out)
+		})
+	}
+}
+
+func TestNameMappingErrors(t *testing.T) {
+	var out mappingServer
+
+	err := i2s.New(i2s.WithNameMapping(i2s.ExactMapping)).Convert(map[string]interface{}{"maxConns": 5}, &out)
+	require.ErrorIs(t, err, i2s.ErrUnknownField)
+
+	err = i2s.New(i2s.WithNameMapping(i2s.CaseInsensitiveMapping)).Convert(map[string]interface{}{
+		"max_conns": 5,
+	}, &out)
+	require.ErrorIs(t, err, i2s.ErrUnknownField)
+
+	err = i2s.New(i2s.WithNameMapping(i2s.SnakeCaseMapping)).Convert(map[string]interface{}{"name": "app"}, &out)
+	require.ErrorIs(t, err, i2s.ErrUnknownField)
+
+	err = i2s.New(i2s.WithNameMapping(i2s.SnakeCaseMapping)).Convert(map[string]interface{}{
+		"server": map[string]interface{}{},
+	}, &struct {
+		Server struct {
+			ReadTimeout int `config:",required"`
+		}
+	}{})
+	require.ErrorIs(t, err, i2s.ErrRequiredField)
+	require.Contains(t, err.Error(), "'server.read_timeout'")
+}
+
+func TestAmbiguousFields(t *testing.T) {
+	convertor := i2s.New(i2s.WithNameMapping(i2s.CaseInsensitiveMapping))
+
+	err := convertor.Convert(map[string]interface{}{"url": "a"}, &struct {
+		URL string
+		Url string //nolint:revive,stylecheck // it's needed for test
+	}{})
+	require.ErrorIs(t, err, i2s.ErrAmbiguousField)
+	require.EqualError(t, err, "ambiguous field at 'url' (key matches fields URL, Url of struct { URL string; "+
+		"Url string })")
+
+	convertor = i2s.New(i2s.WithNameMapping(i2s.SnakeCaseMapping))
+
+	err = convertor.ConvertWithPath("http", map[string]interface{}{
+		"read_timeout": 1,
+		"readTimeout":  2,
+	}, &struct{ ReadTimeout int }{})
+	require.ErrorIs(t, err, i2s.ErrAmbiguousField)
+	require.EqualError(t, err, "ambiguous field at 'http' (keys 'readTimeout' and 'read_timeout' match the same "+
+		"field ReadTimeout of struct { ReadTimeout int })")
+}
+
+func TestEmbeddedFieldShadowing(t *testing.T) {
+	type Base struct {
+		Name string `config:",required"`
+		Port int    `default:"8080"`
+	}
+
+	type Meta struct {
+		Name string
+	}
+
+	type Out struct {
+		Base
+		Name string
+	}
+
+	convertor := i2s.Instance()
+
+	var out Out
+
+	require.NoError(t, convertor.Convert(map[string]interface{}{"name": "outer"}, &out))
+	require.Equal(t, Out{Base: Base{Port: 8080}, Name: "outer"}, out)
+
+	data, err := convertor.ToMap(out)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"name": "outer", "port": 8080}, data)
+
+	snakeOut := struct {
+		Base
+		NAME string `config:"name"`
+	}{}
+
+	require.NoError(t, i2s.New(i2s.WithNameMapping(i2s.SnakeCaseMapping)).Convert(map[string]interface{}{
+		"name": "outer",
+	}, &snakeOut))
+	require.Equal(t, "outer", snakeOut.NAME)
+
+	err = convertor.Convert(map[string]interface{}{"name": "inner"}, &struct {
+		Base
+		Meta
+	}{})
+	require.ErrorIs(t, err, i2s.ErrAmbiguousField)
+	require.ErrorContains(t, err, "key matches fields Name, Name")
+}
+
+func TestUnknownFields(t *testing.T) {
+	type server struct {
+		Address string
+		Port    int
+	}
+
+	in := map[string]interface{}{
+		"address": "127.0.0.1",
+		"port":    80,
+		"unknown": true,
+		"nested":  map[string]interface{}{"key": "value"},
+	}
+
+	var out server
+
+	require.ErrorIs(t, i2s.Instance().Convert(in, &out), i2s.ErrUnknownField)
+
+	out = server{}
+
+	require.NoError(t, i2s.New(i2s.WithIgnoreUnknownFields()).Convert(in, &out))
+	require.Equal(t, server{Address: "127.0.0.1", Port: 80}, out)
+
+	var warnings []string
+
+	out = server{}
+
+	require.NoError(t, i2s.New(i2s.WithUnknownFieldHandler(func(err error) {
+		require.ErrorIs(t, err, i2s.ErrUnknownField)
+
+		warnings = append(warnings, err.Error())
+	})).ConvertWithPath("http", in, &out))
+	require.Equal(t, server{Address: "127.0.0.1", Port: 80}, out)
+	require.ElementsMatch(t, []string{
+		"unknown field at 'http.unknown' (no such field in i2s_test.server)",
+		"unknown field at 'http.nested' (no such field in i2s_test.server)",
+	}, warnings)
+}
+
+func TestNameMappingKeyName(t *testing.T) {
+	tests := []struct {
+		mapping  i2s.NameMapping
+		name     string
+		expected string
+	}{
+		{mapping: i2s.FirstLetterMapping, name: "ReadTimeout", expected: "readTimeout"},
+		{mapping: i2s.ExactMapping, name: "ReadTimeout", expected: "ReadTimeout"},
+		{mapping: i2s.CaseInsensitiveMapping, name: "ReadTimeout", expected: "readTimeout"},
+		{mapping: i2s.SnakeCaseMapping, name: "ReadHeaderTimeout", expected: "read_header_timeout"},
+		{mapping: i2s.SnakeCaseMapping, name: "HTTPServer", expected: "http_server"},
+		{mapping: i2s.KebabCaseMapping, name: "MaxIdleConns", expected: "max-idle-conns"},
+		{mapping: i2s.KebabCaseMapping, name: "ID", expected: "id"},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, tt.mapping.KeyName(tt.name), tt.mapping.String()+" "+tt.name)
+		require.True(t, tt.mapping.Matches(tt.name, tt.expected), tt.mapping.String()+" "+tt.name)
+	}
+}
@@ -0,0 +1,106 @@
+package i2s
+
+import (
+	"reflect"
+	"sort"
+)
+
+// keyBufferSize is the size of stack buffer used to normalize data keys, longer keys are normalized on heap.
+const keyBufferSize = 64
+
+// structPlan structure is compiled description of output structure type: its fields and indexes used to find fields
+// matching data keys without walking over all of them. Plans are immutable once built and cached by convertor, so
+// they are safe for concurrent use.
+type structPlan struct {
+	fields []field
+	tagged map[string][]int
+	mapped map[string][]int
+}
+
+// filledKey structure remembers data key that filled structure field, so several keys matching the same field are
+// detected.
+type filledKey struct {
+	key    string
+	filled bool
+}
+
+// structPlan method retrieves cached plan of output structure type, building it on first use.
+func (c *Convertor) structPlan(structType reflect.Type) *structPlan {
+	if plan, ok := c.plans.Load(structType); ok {
+		return plan.(*structPlan) //nolint:forcetypeassert // only plans are stored in the cache
+	}
+
+	plan, _ := c.plans.LoadOrStore(structType, newStructPlan(structType, c.nameMapping))
+
+	return plan.(*structPlan) //nolint:forcetypeassert // only plans are stored in the cache
+}
+
+// newStructPlan function builds plan of output structure type for provided name mapping strategy.
+func newStructPlan(structType reflect.Type, mapping NameMapping) *structPlan {
+	fields := visibleFields(structFields(structType), mapping)
+
+	plan := &structPlan{
+		fields: fields,
+		tagged: make(map[string][]int),
+		mapped: make(map[string][]int, len(fields)),
+	}
+
+	for i := range fields {
+		if fields[i].tagged {
+			plan.tagged[fields[i].name] = append(plan.tagged[fields[i].name], i)
+		} else {
+			name := mapping.normalizeField(fields[i].name)
+			plan.mapped[name] = append(plan.mapped[name], i)
+		}
+	}
+
+	return plan
+}
+
+// visibleFields function drops fields of embedded structures that are shadowed by shallower fields matching the same
+// keys, following Go selector rules. Fields matching the same keys at the same depth are all kept, so such keys are
+// reported as ambiguous.
+func visibleFields(fields []field, mapping NameMapping) []field {
+	depths := make(map[string]int, len(fields))
+
+	for i := range fields {
+		name := fields[i].matchName(mapping)
+		if depth, ok := depths[name]; !ok || len(fields[i].index) < depth {
+			depths[name] = len(fields[i].index)
+		}
+	}
+
+	visible := make([]field, 0, len(fields))
+
+	for i := range fields {
+		if len(fields[i].index) == depths[fields[i].matchName(mapping)] {
+			visible = append(visible, fields[i])
+		}
+	}
+
+	return visible
+}
+
+// find method retrieves indexes of all fields that match provided key in ascending order. Field with name defined in
+// tag matches only exactly the same key. Other fields match keys according to the name mapping strategy.
+func (plan *structPlan) find(key string, mapping NameMapping) []int {
+	var buf [keyBufferSize]byte
+
+	tagged := plan.tagged[key]
+	mapped := plan.mapped[string(mapping.appendKey(buf[:0], key))]
+
+	switch {
+	case len(tagged) == 0:
+		return mapped
+	case len(mapped) == 0:
+		return tagged
+	}
+
+	indexes := make([]int, 0, len(tagged)+len(mapped))
+	indexes = append(indexes, tagged...)
+	indexes = append(indexes, mapped...)
+
+	sort.Ints(indexes)
+
+	return indexes
+}
@@ -0,0 +1,237 @@
+package i2s
+
+import (
+	"encoding"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Marshaler interface can be implemented by types that want to convert themselves into raw data. It is the inverse
+// of Unmarshaler interface, so returned data must be acceptable by UnmarshalI2S method.
+type Marshaler interface {
+	MarshalI2S() (interface{}, error)
+}
+
+// ToMap method converts structure or pointer to it in 'in' parameter into raw data map. It is the inverse of Convert
+// method, so the result converted back into the same structure type gives the same structure.
+//
+// Key names are defined by the same rules: names in 'config' tags or field names converted according to the name
+// mapping strategy, see WithNameMapping option. Ignored and unexported fields are skipped, fields of embedded
+// structures are put into the same map. Fields with 'omitempty' option in 'config' tag are skipped if they have zero
+// values:
+//
+//	type Config struct {
+//	    ReadTimeout time.Duration `config:"read_timeout"` // put under key 'read_timeout'
+//	    Name        string        `config:",omitempty"`   // skipped if empty
+//	}
+//
+// Nil pointers, interfaces, maps and slices are skipped too, so converting them back leaves them nil. Integers become
+// int if they fit into it, floats become float64, time.Duration becomes string like '1m30s', types implementing
+// Marshaler or encoding.TextMarshaler interfaces (such as time.Time) become what they retrieve. Lists and arrays
+// become []interface{}, maps and structures become map[string]interface{}.
+func (c *Convertor) ToMap(in interface{}) (map[string]interface{}, error) {
+	data, err := c.ToRaw(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		return make(map[string]interface{}), nil
+	}
+
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, newFieldError(ErrMismatchedTypes, "", "expected structure or map, got "+
+			reflect.TypeOf(in).String())
+	}
+
+	return dataMap, nil
+}
+
+// ToRaw method converts value of any supported type in 'in' parameter into raw data, see ToMap method for details.
+// It retrieves nil for nil pointers, interfaces, maps and slices.
+func (c *Convertor) ToRaw(in interface{}) (interface{}, error) {
+	return c.toRaw("", reflect.ValueOf(in))
+}
+
+// toRaw method converts value into raw data. Path is the full key path of the value used in error messages.
+//
+//nolint:nilnil // nil data means there is no value, so it is skipped
+func (c *Convertor) toRaw(path string, value reflect.Value) (interface{}, error) {
+	if !value.IsValid() {
+		return nil, nil
+	}
+
+	if (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && value.IsNil() {
+		return nil, nil
+	}
+
+	if ok, data, err := c.marshalerToRaw(path, value); ok {
+		return data, err
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return c.toRaw(path, value.Elem())
+	case reflect.Struct:
+		return c.structToRaw(path, value)
+	case reflect.Map:
+		return c.mapToRaw(path, value)
+	case reflect.Slice, reflect.Array:
+		return c.sliceToRaw(path, value)
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Type() == durationType {
+			return time.Duration(value.Int()).String(), nil
+		}
+
+		return intToRaw(value.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() <= math.MaxInt64 {
+			return intToRaw(int64(value.Uint())), nil
+		}
+
+		return value.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	default:
+		return nil, newFieldError(ErrUnsupportedType, path, "can't convert "+value.Type().String())
+	}
+}
+
+// marshalerToRaw method converts value using its own Marshaler or encoding.TextMarshaler implementation if there is
+// any. It returns true if such implementation was found.
+func (c *Convertor) marshalerToRaw(path string, value reflect.Value) (bool, interface{}, error) {
+	if !value.CanInterface() {
+		return false, nil, nil
+	}
+
+	in := value.Interface()
+
+	if _, ok := in.(Marshaler); !ok && value.Kind() != reflect.Ptr && value.CanAddr() {
+		in = value.Addr().Interface()
+	}
+
+	var data interface{}
+	var err error
+
+	switch typedIn := in.(type) {
+	case Marshaler:
+		data, err = typedIn.MarshalI2S()
+	case encoding.TextMarshaler:
+		var text []byte
+
+		text, err = typedIn.MarshalText()
+		data = string(text)
+	default:
+		return false, nil, nil
+	}
+
+	if err != nil {
+		return true, nil, newFieldError(ErrInvalidValue, path, err.Error())
+	}
+
+	return true, data, nil
+}
+
+// structToRaw method converts structure into map using the same key names as Convert method.
+func (c *Convertor) structToRaw(path string, value reflect.Value) (interface{}, error) {
+	fields := c.structPlan(value.Type()).fields
+	result := make(map[string]interface{}, len(fields))
+	fieldNamesByKey := make(map[string]string, len(fields))
+
+	for i := range fields {
+		fieldValue := value.FieldByIndex(fields[i].index)
+		if fields[i].omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+
+		key := fields[i].keyName(c.nameMapping)
+		fieldName := value.Type().FieldByIndex(fields[i].index).Name
+
+		if otherName, ok := fieldNamesByKey[key]; ok {
+			return nil, newFieldError(ErrAmbiguousField, joinPath(path, key), "fields "+otherName+", "+fieldName+
+				" of "+value.Type().String()+" have the same key")
+		}
+
+		fieldNamesByKey[key] = fieldName
+
+		data, err := c.toRaw(joinPath(path, key), fieldValue)
+		if err != nil {
+			return nil, err
+		}
+
+		if data != nil {
+			result[key] = data
+		}
+	}
+
+	return result, nil
+}
+
+// mapToRaw method converts map with string keys into map with elements converted into raw data.
+//
+//nolint:nilnil // nil data means there is no value, so it is skipped
+func (c *Convertor) mapToRaw(path string, value reflect.Value) (interface{}, error) {
+	if value.IsNil() {
+		return nil, nil
+	}
+
+	if value.Type().Key().Kind() != reflect.String {
+		return nil, newFieldError(ErrMapKeyNotString, path, "got "+value.Type().Key().String())
+	}
+
+	result := make(map[string]interface{}, value.Len())
+
+	mapIter := value.MapRange()
+	for mapIter.Next() {
+		key := mapIter.Key().String()
+
+		data, err := c.toRaw(joinPath(path, key), mapIter.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		if data != nil {
+			result[key] = data
+		}
+	}
+
+	return result, nil
+}
+
+// sliceToRaw method converts slice or array into list of raw data elements.
+//
+//nolint:nilnil // nil data means there is no value, so it is skipped
+func (c *Convertor) sliceToRaw(path string, value reflect.Value) (interface{}, error) {
+	if value.Kind() == reflect.Slice && value.IsNil() {
+		return nil, nil
+	}
+
+	result := make([]interface{}, 0, value.Len())
+
+	for i := 0; i < value.Len(); i++ {
+		data, err := c.toRaw(joinPath(path, strconv.Itoa(i)), value.Index(i))
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, data)
+	}
+
+	return result, nil
+}
+
+// intToRaw function converts integer into int if it fits, so raw data looks like the one produced by encoders.
+func intToRaw(value int64) interface{} {
+	if value < math.MinInt || value > math.MaxInt {
+		return value
+	}
+
+	return int(value)
+}
@@ -0,0 +1,169 @@
+package i2s_test
+
+import (
+	"math"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+type tomapLevel int
+
+func (level tomapLevel) MarshalI2S() (interface{}, error) {
+	if level < 0 {
+		return nil, errors.New("negative level")
+	}
+
+	return strings.Repeat("*", int(level)), nil
+}
+
+func (level *tomapLevel) UnmarshalI2S(data interface{}) error {
+	stars, ok := data.(string)
+	if !ok {
+		return errors.New("level must be a string")
+	}
+
+	*level = tomapLevel(len(stars))
+
+	return nil
+}
+
+type tomapBase struct {
+	ID      int
+	Created time.Time
+}
+
+type tomapBackend struct {
+	Host   string
+	Port   uint16
+	Weight float32
+}
+
+type tomapConfig struct {
+	tomapBase
+	Name        string        `config:"app_name"`
+	Description string        `config:",omitempty"`
+	ReadTimeout time.Duration `default:"3s"`
+	Level       tomapLevel
+	IP          net.IP
+	Debug       *bool
+	Tags        []string
+	Ports       []int
+	Limits      map[string]uint64
+	Backends    []tomapBackend
+	Primary     *tomapBackend
+	Extra       interface{}
+	Ignored     string `config:"-"`
+	internal    string
+}
+
+func TestToMap(t *testing.T) {
+	debug := true
+	created := time.Date(2023, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	in := tomapConfig{
+		tomapBase:   tomapBase{ID: 7, Created: created},
+		Name:        "app",
+		ReadTimeout: 90 * time.Second,
+		Level:       3,
+		IP:          net.ParseIP("10.0.0.1"),
+		Debug:       &debug,
+		Tags:        []string{"a", "b"},
+		Ports:       []int{80, 443},
+		Limits:      map[string]uint64{"cpu": 2, "huge": math.MaxUint64},
+		Backends:    []tomapBackend{{Host: "b1", Port: 8080, Weight: 0.5}},
+		Extra:       map[string]interface{}{"key": []interface{}{1, "x"}},
+		Ignored:     "ignored",
+		internal:    "internal",
+	}
+
+	data, err := i2s.Instance().ToMap(&in)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"iD":          7,
+		"created":     "2023-05-01T10:30:00Z",
+		"app_name":    "app",
+		"readTimeout": "1m30s",
+		"level":       "***",
+		"iP":          "10.0.0.1",
+		"debug":       true,
+		"tags":        []interface{}{"a", "b"},
+		"ports":       []interface{}{80, 443},
+		"limits":      map[string]interface{}{"cpu": 2, "huge": uint64(math.MaxUint64)},
+		"backends": []interface{}{
+			map[string]interface{}{"host": "b1", "port": 8080, "weight": 0.5},
+		},
+		"extra": map[string]interface{}{"key": []interface{}{1, "x"}},
+	}, data)
+
+	var out tomapConfig
+
+	require.NoError(t, i2s.Instance().Convert(data, &out))
+
+	in.Ignored, in.internal = "", ""
+
+	require.Equal(t, in, out)
+}
+
+func TestToMapNameMapping(t *testing.T) {
+	in := struct {
+		ReadHeaderTimeout time.Duration
+		HTTPServer        struct{ MaxConns int }
+		Name              string `config:"server_name"`
+	}{ReadHeaderTimeout: time.Second, Name: "main"}
+	in.HTTPServer.MaxConns = 5
+
+	convertor := i2s.New(i2s.WithNameMapping(i2s.SnakeCaseMapping))
+
+	data, err := convertor.ToMap(in)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"read_header_timeout": "1s",
+		"http_server":         map[string]interface{}{"max_conns": 5},
+		"server_name":         "main",
+	}, data)
+
+	out := in
+	out.ReadHeaderTimeout, out.HTTPServer.MaxConns, out.Name = 0, 0, ""
+
+	require.NoError(t, convertor.Convert(data, &out))
+	require.Equal(t, in, out)
+}
+
+func TestToMapErrors(t *testing.T) {
+	convertor := i2s.Instance()
+
+	data, err := convertor.ToMap((*tomapConfig)(nil))
+	require.NoError(t, err)
+	require.Empty(t, data)
+
+	_, err = convertor.ToMap(5)
+	require.ErrorIs(t, err, i2s.ErrMismatchedTypes)
+
+	raw, err := convertor.ToRaw([2]uint8{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{1, 2}, raw)
+
+	_, err = convertor.ToMap(struct{ Limits map[int]int }{Limits: map[int]int{1: 1}})
+	require.ErrorIs(t, err, i2s.ErrMapKeyNotString)
+
+	_, err = convertor.ToMap(struct{ Callbacks []func() }{Callbacks: []func(){func() {}}})
+	require.ErrorIs(t, err, i2s.ErrUnsupportedType)
+	require.Contains(t, err.Error(), "'callbacks.0'")
+
+	_, err = convertor.ToMap(struct{ Level tomapLevel }{Level: -1})
+	require.ErrorIs(t, err, i2s.ErrInvalidValue)
+	require.Contains(t, err.Error(), "negative level")
+
+	_, err = convertor.ToMap(struct {
+		Name  string
+		Other string `config:"name"`
+	}{})
+	require.ErrorIs(t, err, i2s.ErrAmbiguousField)
+}
@@ -0,0 +1,71 @@
+package i2s
+
+import (
+	"encoding"
+	"reflect"
+	"time"
+)
+
+// Unmarshaler interface can be implemented by output types that want to convert raw data into themselves.
+// Raw data is passed as is, so it can be a string, a number, a bool, a map or a slice.
+type Unmarshaler interface {
+	UnmarshalI2S(data interface{}) error
+}
+
+//nolint:gochecknoglobals // these are actually read-only, so it's ok to use them.
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// processUnmarshaler method fills output value using its own Unmarshaler or encoding.TextUnmarshaler implementation
+// if there is any. It returns true if such implementation was found.
+func (c *Convertor) processUnmarshaler(path string, dataValue reflect.Value, outValue reflect.Value) (bool, error) {
+	if !outValue.CanAddr() {
+		return false, nil
+	}
+
+	var err error
+
+	switch out := outValue.Addr().Interface().(type) {
+	case Unmarshaler:
+		err = out.UnmarshalI2S(dataValue.Interface())
+	case encoding.TextUnmarshaler:
+		if dataValue.Kind() != reflect.String {
+			return true, newTypedFieldError(ErrMismatchedTypes, path, dataValue, outValue)
+		}
+
+		err = out.UnmarshalText([]byte(dataValue.String()))
+	default:
+		return false, nil
+	}
+
+	if err != nil {
+		return true, newFieldError(ErrInvalidValue, path, err.Error())
+	}
+
+	return true, nil
+}
+
+// isScalarKind function checks if data of provided kind is a single plain value, such as time.Time decoded by TOML
+// encoder, rather than a container that must be copied element by element or unsupported value like function.
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String, reflect.Struct,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTextType function checks if output value is filled from strings despite of its kind.
+func isTextType(outValue reflect.Value) bool {
+	outType := outValue.Type()
+
+	return outType == durationType || reflect.PointerTo(outType).Implements(unmarshalerType) ||
+		reflect.PointerTo(outType).Implements(textUnmarshalerType)
+}
@@ -13,5 +13,5 @@ func NewInner(key string, config *Config) (*Config, error) {
 		return nil, ErrNotMap
 	}
 
-	return NewFromRaw(dataMap), nil
+	return NewFromRaw(dataMap).inherit(config), nil
 }
@@ -0,0 +1,224 @@
+// Package flat provides helpers for encoders and sources that store configuration as flat list of keys, such as
+// .env, INI or properties files, or etcd keys under some prefix. Key paths are turned into nested maps and back,
+// scalar values are type-inferred.
+package flat
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+var (
+	// intPattern matches plain decimal integer without leading zeros.
+	intPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	// floatPattern matches plain decimal number with fraction or exponent.
+	floatPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+)
+
+// Entry structure is one flat key with its value.
+type Entry struct {
+	Path  []string
+	Value interface{}
+}
+
+// Set function sets value by the key path inside nested maps creating missing ones. It fails if some part of the path
+// is already occupied by a scalar value, or if the value would replace nested map.
+func Set(data map[string]interface{}, path []string, value interface{}) error {
+	for i, key := range path {
+		if key == "" {
+			return errors.NewFmt("key '%s' has empty segment", strings.Join(path, "."))
+		}
+
+		if i+1 == len(path) {
+			if _, ok := data[key].(map[string]interface{}); ok {
+				return errors.NewFmt("key '%s' is already a section", strings.Join(path, "."))
+			}
+
+			data[key] = value
+
+			return nil
+		}
+
+		inner, ok := data[key]
+		if !ok {
+			inner = make(map[string]interface{})
+			data[key] = inner
+		}
+
+		innerMap, ok := inner.(map[string]interface{})
+		if !ok {
+			return errors.NewFmt("key '%s' is already a value", strings.Join(path[:i+1], "."))
+		}
+
+		data = innerMap
+	}
+
+	return nil
+}
+
+// Section function retrieves nested map by the key path creating missing ones.
+func Section(data map[string]interface{}, path []string) (map[string]interface{}, error) {
+	for i, key := range path {
+		if key == "" {
+			return nil, errors.NewFmt("section '%s' has empty segment", strings.Join(path, "."))
+		}
+
+		inner, ok := data[key]
+		if !ok {
+			inner = make(map[string]interface{})
+			data[key] = inner
+		}
+
+		innerMap, ok := inner.(map[string]interface{})
+		if !ok {
+			return nil, errors.NewFmt("key '%s' is already a value", strings.Join(path[:i+1], "."))
+		}
+
+		data = innerMap
+	}
+
+	return data, nil
+}
+
+// Normalize function recursively converts nested maps with keys '0', '1', ... 'N' into slices, so keys like
+// 'servers.0.address' become lists. It retrieves normalized value.
+func Normalize(value interface{}) interface{} {
+	valueMap, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	for key, elem := range valueMap {
+		valueMap[key] = Normalize(elem)
+	}
+
+	if len(valueMap) == 0 {
+		return valueMap
+	}
+
+	list := make([]interface{}, len(valueMap))
+
+	for key, elem := range valueMap {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(list) || strconv.Itoa(index) != key {
+			return valueMap
+		}
+
+		list[index] = elem
+	}
+
+	return list
+}
+
+// NormalizeMap function normalizes all values of the map in place, see Normalize function. The map itself always
+// stays a map.
+func NormalizeMap(data map[string]interface{}) {
+	for key, elem := range data {
+		data[key] = Normalize(elem)
+	}
+}
+
+// Infer function guesses type of the scalar value: int, float, bool or string. Only plain decimal literals like '42',
+// '-7', '0.5' or '1e3' are numbers, so values like '0123', '+1', '0x1F', 'inf' or 'NaN' stay strings. Integers that
+// don't fit into int64 stay strings too, so no precision is lost.
+func Infer(value string) interface{} {
+	if intPattern.MatchString(value) {
+		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return int(intValue)
+		}
+
+		return value
+	}
+
+	if floatPattern.MatchString(value) {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+
+		return value
+	}
+
+	if strings.EqualFold(value, "true") || strings.EqualFold(value, "false") {
+		return strings.EqualFold(value, "true")
+	}
+
+	return value
+}
+
+// InferJSON function works like Infer function, but also parses JSON literals of lists and maps, such as '[1,2]' or
+// '{"a":1}'. Invalid JSON literals stay strings.
+func InferJSON(value string) interface{} {
+	if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") {
+		var result interface{}
+
+		if err := json.Unmarshal([]byte(value), &result); err == nil {
+			return result
+		}
+	}
+
+	return Infer(value)
+}
+
+// Flatten function converts nested maps and slices into the list of scalar entries sorted by their key paths.
+// Slice elements get their indexes as key segments. Empty maps and slices are skipped.
+func Flatten(data map[string]interface{}) []Entry {
+	entries := flatten(nil, data, nil)
+
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.Join(entries[i].Path, "\x00") < strings.Join(entries[j].Path, "\x00")
+	})
+
+	return entries
+}
+
+// Format function converts scalar value into its string representation.
+func Format(value interface{}) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typedValue
+	case float64:
+		return strconv.FormatFloat(typedValue, 'g', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(typedValue), 'g', -1, 32)
+	default:
+		return fmt.Sprint(typedValue)
+	}
+}
+
+// flatten function appends scalar entries of the value to the list.
+func flatten(path []string, value interface{}, entries []Entry) []Entry {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range typedValue {
+			entries = flatten(appendPath(path, key), elem, entries)
+		}
+	case []interface{}:
+		for index, elem := range typedValue {
+			entries = flatten(appendPath(path, strconv.Itoa(index)), elem, entries)
+		}
+	case []map[string]interface{}:
+		for index, elem := range typedValue {
+			entries = flatten(appendPath(path, strconv.Itoa(index)), elem, entries)
+		}
+	default:
+		entries = append(entries, Entry{Path: path, Value: value})
+	}
+
+	return entries
+}
+
+// appendPath function creates new key path with the key appended.
+func appendPath(path []string, key string) []string {
+	result := make([]string, len(path), len(path)+1)
+	copy(result, path)
+
+	return append(result, key)
+}
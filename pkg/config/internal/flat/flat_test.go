@@ -0,0 +1,75 @@
+package flat_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config/internal/flat"
+)
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{name: "Zero", value: "0", expected: 0},
+		{name: "Int", value: "8080", expected: 8080},
+		{name: "NegativeInt", value: "-7", expected: -7},
+		{name: "Float", value: "0.5", expected: 0.5},
+		{name: "NegativeFloat", value: "-12.25", expected: -12.25},
+		{name: "Exponent", value: "1e3", expected: 1000.0},
+		{name: "SignedExponent", value: "2.5E-2", expected: 0.025},
+		{name: "True", value: "true", expected: true},
+		{name: "FalseUpperCase", value: "FALSE", expected: false},
+		{name: "String", value: "George", expected: "George"},
+		{name: "Empty", value: "", expected: ""},
+		{name: "LeadingZero", value: "0123", expected: "0123"},
+		{name: "LeadingZeroFloat", value: "00.5", expected: "00.5"},
+		{name: "LeadingPlus", value: "+1", expected: "+1"},
+		{name: "Hex", value: "0x1F", expected: "0x1F"},
+		{name: "Underscores", value: "1_000", expected: "1_000"},
+		{name: "NoIntegerPart", value: ".5", expected: ".5"},
+		{name: "NoFractionPart", value: "5.", expected: "5."},
+		{name: "Inf", value: "inf", expected: "inf"},
+		{name: "InfSigned", value: "-Inf", expected: "-Inf"},
+		{name: "Infinity", value: "Infinity", expected: "Infinity"},
+		{name: "NaN", value: "NaN", expected: "NaN"},
+		{name: "IntOverflow", value: "12345678901234567890", expected: "12345678901234567890"},
+		{name: "FloatOverflow", value: "1e400", expected: "1e400"},
+		{name: "Spaces", value: " 42", expected: " 42"},
+		{name: "JSONList", value: "[1,2]", expected: "[1,2]"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, flat.Infer(tt.value))
+		})
+	}
+}
+
+func TestInferJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{name: "List", value: "[1,2]", expected: []interface{}{1.0, 2.0}},
+		{name: "Map", value: `{"a":"b"}`, expected: map[string]interface{}{"a": "b"}},
+		{name: "InvalidList", value: "[1,2", expected: "[1,2"},
+		{name: "Int", value: "42", expected: 42},
+		{name: "LeadingZero", value: "0123", expected: "0123"},
+		{name: "NaN", value: "nan", expected: "nan"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, flat.InferJSON(tt.value))
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+const (
+	// EnvResolverName is the name of resolver that retrieves values of environment variables: '${env:NAME}'.
+	EnvResolverName = "env"
+	// FileResolverName is the name of resolver that retrieves contents of files: '${file:/run/secrets/name}'.
+	FileResolverName = "file"
+	// KeyResolverName is the name of built-in resolver that retrieves other values of the same configuration:
+	// '${key:other.path}'. It can't be overridden.
+	KeyResolverName = "key"
+)
+
+// Resolver is a function that resolves reference argument into a string value. It returns false as the second value
+// if there is no such value at all, so default value of the reference is used instead.
+type Resolver func(arg string) (string, bool, error)
+
+// DefResolvers function retrieves map of predefined resolvers by their names: 'env' and 'file'. You can add your own
+// resolvers to it and pass it to NewWithInterpolation or Interpolate functions.
+func DefResolvers() map[string]Resolver {
+	return map[string]Resolver{
+		EnvResolverName:  EnvResolver,
+		FileResolverName: FileResolver,
+	}
+}
+
+// EnvResolver function resolves reference to the value of environment variable.
+func EnvResolver(name string) (string, bool, error) {
+	value, ok := os.LookupEnv(name)
+
+	return value, ok, nil
+}
+
+// FileResolver function resolves reference to the contents of file, trailing line breaks are trimmed.
+func FileResolver(name string) (string, bool, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", false, nil
+		}
+
+		return "", false, err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
+
+// NewWithInterpolation function creates new configuration service with data of provided one, where references inside
+// string values are resolved. See Interpolate function for details. If resolvers are nil, DefResolvers are used.
+//
+// Created configuration service fills numeric and bool fields from strings like '8080', so references resolved as
+// strings can be read into such fields. Its convertor is the one of provided service with i2s.WithStringConversion
+// option added.
+//
+// Created configuration service follows updates of the provided one. Updates that can't be interpolated are ignored
+// and reported to the error function, see WithErrorFunc method.
+func NewWithInterpolation(config *Config, resolvers map[string]Resolver) (*Config, error) {
+	data, err := Interpolate(config.GetRaw(), resolvers)
+	if err != nil {
+		return nil, err
+	}
+
+	interpolated := NewFromRaw(data).inherit(config)
+	interpolated.WithConvertor(interpolated.convertor().With(i2s.WithStringConversion()))
+
+	config.follow(interpolated, func(data map[string]interface{}) (map[string]interface{}, error) {
+		return Interpolate(data, resolvers)
+	})
+
+	return interpolated, nil
+}
+
+// Interpolate function resolves references inside string values of provided data and retrieves resolved copy of it.
+// Provided data itself is not modified. If resolvers are nil, DefResolvers are used.
+//
+// Reference has form '${name:arg}' or '${name:arg:-default}', where 'name' is the name of resolver, and 'default' is
+// the value used if resolver has no value for the 'arg'. Built-in resolver 'key' retrieves other value of the same
+// configuration by composite key like 'key1.key2.key3'. Use '$${' to write literal '${'.
+//
+// If the whole string value is a single 'key' reference, it retrieves the original value as is, keeping its type.
+// Other references are always resolved as strings and concatenated with the rest of the value, so numeric password
+// stays a string. Configuration service created with NewWithInterpolation function converts strings like '8080' into
+// numeric fields when configuration is read into structure.
+//
+// All unresolved references and reference cycles are reported at once inside i2s.MultiError, each with the key path
+// of the value containing the reference.
+func Interpolate(data map[string]interface{}, resolvers map[string]Resolver) (map[string]interface{}, error) {
+	if resolvers == nil {
+		resolvers = DefResolvers()
+	}
+
+	interp := &interpolator{
+		source:    NewFromRaw(data),
+		resolvers: resolvers,
+		resolved:  make(map[string]interface{}),
+	}
+
+	result := interp.walk("", data).(map[string]interface{})
+
+	if len(interp.errs) > 0 {
+		return nil, &i2s.MultiError{Errors: interp.errs}
+	}
+
+	return result, nil
+}
+
+// interpolator structure holds the state of single interpolation.
+type interpolator struct {
+	source    *Config
+	resolvers map[string]Resolver
+	resolved  map[string]interface{}
+	resolving []string
+	errs      []error
+}
+
+// reference structure describes single parsed reference.
+type reference struct {
+	name       string
+	arg        string
+	defValue   string
+	hasDefault bool
+}
+
+// walk method resolves all references inside value collecting errors instead of stopping on them. Map keys are
+// walked in sorted order, so errors are always reported in the same order.
+func (interp *interpolator) walk(path string, value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typedValue))
+		for _, key := range sortedKeys(typedValue) {
+			result[key] = interp.walk(joinKey(path, key), typedValue[key])
+		}
+
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(typedValue))
+		for index, elem := range typedValue {
+			result = append(result, interp.walk(joinKey(path, index), elem))
+		}
+
+		return result
+	case []map[string]interface{}:
+		result := make([]map[string]interface{}, 0, len(typedValue))
+		for index, elem := range typedValue {
+			result = append(result, interp.walk(joinKey(path, index), elem).(map[string]interface{}))
+		}
+
+		return result
+	case string:
+		result, err := interp.string(path, typedValue)
+		if err != nil {
+			interp.errs = append(interp.errs, err)
+			return typedValue
+		}
+
+		return result
+	default:
+		return value
+	}
+}
+
+// value method resolves all references inside value stopping on the first error.
+func (interp *interpolator) value(path string, value interface{}) (interface{}, error) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typedValue))
+		for key, elem := range typedValue {
+			resolvedElem, err := interp.value(joinKey(path, key), elem)
+			if err != nil {
+				return nil, err
+			}
+
+			result[key] = resolvedElem
+		}
+
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, 0, len(typedValue))
+		for index, elem := range typedValue {
+			resolvedElem, err := interp.value(joinKey(path, index), elem)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, resolvedElem)
+		}
+
+		return result, nil
+	case []map[string]interface{}:
+		result := make([]map[string]interface{}, 0, len(typedValue))
+		for index, elem := range typedValue {
+			resolvedElem, err := interp.value(joinKey(path, index), elem)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, resolvedElem.(map[string]interface{}))
+		}
+
+		return result, nil
+	case string:
+		return interp.string(path, typedValue)
+	default:
+		return value, nil
+	}
+}
+
+// string method resolves all references inside string value.
+func (interp *interpolator) string(path string, value string) (interface{}, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+
+	var builder strings.Builder
+
+	rest := value
+
+	for {
+		index := strings.Index(rest, "${")
+		if index < 0 {
+			builder.WriteString(rest)
+			break
+		}
+
+		if index > 0 && rest[index-1] == '$' {
+			builder.WriteString(rest[:index-1])
+			builder.WriteString("${")
+			rest = rest[index+2:]
+
+			continue
+		}
+
+		end := strings.IndexByte(rest[index:], '}')
+		if end < 0 {
+			return nil, errors.NewFmt("unclosed reference at '%s'", path).WithCause(ErrInvalidReference)
+		}
+
+		ref, err := parseReference(rest[index+2 : index+end])
+		if err != nil {
+			return nil, errors.NewFmt("%s at '%s'", err.Error(), path).WithCause(ErrInvalidReference)
+		}
+
+		resolved, isString, err := interp.resolve(path, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		if index == 0 && end == len(rest)-1 && len(rest) == len(value) && !isString {
+			return resolved, nil
+		}
+
+		switch resolved.(type) {
+		case map[string]interface{}, []interface{}, []map[string]interface{}:
+			return nil, errors.NewFmt("reference '%s:%s' at '%s' is not a scalar and can't be embedded "+
+				"into string", ref.name, ref.arg, path).WithCause(ErrInvalidReference)
+		}
+
+		builder.WriteString(rest[:index])
+		builder.WriteString(fmt.Sprint(resolved))
+		rest = rest[index+end+1:]
+	}
+
+	return builder.String(), nil
+}
+
+// resolve method resolves single reference. It also returns false as the second value if resolved value is the
+// original configuration value that must be used as is.
+func (interp *interpolator) resolve(path string, ref reference) (interface{}, bool, error) {
+	if ref.name == KeyResolverName {
+		value, err := interp.resolveKey(path, keyPath(ref.arg))
+		if errors.Is(err, ErrNoSuchKey) {
+			if !ref.hasDefault {
+				return nil, false, errors.NewFmt("unresolved reference '%s:%s' at '%s'", ref.name, ref.arg, path).
+					WithCause(ErrUnresolvedReference)
+			}
+
+			return ref.defValue, true, nil
+		}
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		return value, false, nil
+	}
+
+	resolver, ok := interp.resolvers[ref.name]
+	if !ok {
+		return nil, false, errors.NewFmt("unknown resolver '%s' at '%s'", ref.name, path).
+			WithCause(ErrUnknownResolver)
+	}
+
+	value, ok, err := resolver(ref.arg)
+	if err != nil {
+		return nil, false, errors.NewFmt("can't resolve reference '%s:%s' at '%s' (%s)", ref.name, ref.arg, path,
+			err.Error()).WithCause(err)
+	}
+
+	if !ok {
+		if !ref.hasDefault {
+			return nil, false, errors.NewFmt("unresolved reference '%s:%s' at '%s'", ref.name, ref.arg, path).
+				WithCause(ErrUnresolvedReference)
+		}
+
+		value = ref.defValue
+	}
+
+	return value, true, nil
+}
+
+// resolveKey method resolves reference to other value of the same configuration detecting reference cycles.
+func (interp *interpolator) resolveKey(path string, key string) (interface{}, error) {
+	if value, ok := interp.resolved[key]; ok {
+		return value, nil
+	}
+
+	for index, resolvingKey := range interp.resolving {
+		if resolvingKey == key {
+			cycle := append(append([]string{}, interp.resolving[index:]...), key)
+
+			return nil, errors.NewFmt("reference cycle '%s' at '%s'", strings.Join(cycle, " -> "), path).
+				WithCause(ErrReferenceCycle)
+		}
+	}
+
+	raw, err := interp.source.GetRawByKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	interp.resolving = append(interp.resolving, key)
+	value, err := interp.value(key, raw)
+	interp.resolving = interp.resolving[:len(interp.resolving)-1]
+
+	if err != nil {
+		return nil, err
+	}
+
+	interp.resolved[key] = value
+
+	return value, nil
+}
+
+// sortedKeys function retrieves sorted keys of the map.
+func sortedKeys(data map[string]interface{}) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// joinKey function appends map key or slice index to the key path.
+func joinKey(path string, key interface{}) string {
+	segment := quoteKeySegment(fmt.Sprint(key))
+
+	if path == "" {
+		return segment
+	}
+
+	return path + "." + segment
+}
+
+// parseReference function parses reference body 'name:arg' or 'name:arg:-default'.
+func parseReference(body string) (reference, error) {
+	name, arg, ok := strings.Cut(body, ":")
+	if !ok || name == "" {
+		return reference{}, errors.NewFmt("reference '%s' has no resolver name", body)
+	}
+
+	ref := reference{name: name, arg: arg}
+
+	if arg, defValue, ok := strings.Cut(arg, ":-"); ok {
+		ref.arg = arg
+		ref.defValue = defValue
+		ref.hasDefault = true
+	}
+
+	if ref.arg == "" {
+		return reference{}, errors.NewFmt("reference '%s' has empty argument", body)
+	}
+
+	return ref, nil
+}
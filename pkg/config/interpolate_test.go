@@ -0,0 +1,144 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/i2s"
+)
+
+func TestInterpolate(t *testing.T) {
+	secretPath := filepath.Join(t.TempDir(), "mongo")
+	require.NoError(t, os.WriteFile(secretPath, []byte("mongo-secret\n"), 0o600))
+
+	t.Setenv("TEST_REDIS_PASSWORD", "redis-secret")
+	t.Setenv("TEST_TIMEOUT", "15")
+	t.Setenv("TEST_TOKEN", "0123")
+
+	base := config.NewFromRaw(map[string]interface{}{
+		"host": "example.com",
+		"port": 8080,
+		"redis": map[string]interface{}{
+			"password": "${env:TEST_REDIS_PASSWORD}",
+			"timeout":  "${env:TEST_TIMEOUT}",
+			"maxIdle":  "${env:TEST_MAX_IDLE:-3}",
+			"name":     "${env:TEST_REDIS_NAME:-}",
+			"token":    "${env:TEST_TOKEN}",
+		},
+		"mongo": map[string]interface{}{
+			"password": "${file:" + secretPath + "}",
+			"address":  "${key:host}:${key:port}",
+		},
+		"http": map[string]interface{}{
+			"url":    "http://${key:mongo.address}/path",
+			"port":   "${key:port}",
+			"limits": []interface{}{"${key:redis.maxIdle}", "$${env:TEST_TIMEOUT}"},
+		},
+		"copy": "${key:redis}",
+	})
+
+	cfg, err := config.NewWithInterpolation(base, nil)
+	require.NoError(t, err)
+
+	redis := map[string]interface{}{
+		"password": "redis-secret",
+		"timeout":  "15",
+		"maxIdle":  "3",
+		"name":     "",
+		"token":    "0123",
+	}
+
+	require.Equal(t, map[string]interface{}{
+		"host":  "example.com",
+		"port":  8080,
+		"redis": redis,
+		"mongo": map[string]interface{}{
+			"password": "mongo-secret",
+			"address":  "example.com:8080",
+		},
+		"http": map[string]interface{}{
+			"url":    "http://example.com:8080/path",
+			"port":   8080,
+			"limits": []interface{}{"3", "${env:TEST_TIMEOUT}"},
+		},
+		"copy": redis,
+	}, cfg.GetRaw())
+
+	var redisConfig struct {
+		Password string
+		Timeout  int
+		MaxIdle  uint
+		Name     string
+		Token    string
+	}
+
+	require.NoError(t, cfg.GetByKey("redis", &redisConfig))
+	require.Equal(t, 15, redisConfig.Timeout)
+	require.Equal(t, uint(3), redisConfig.MaxIdle)
+	require.Equal(t, "0123", redisConfig.Token)
+
+	base.Update(map[string]interface{}{"name": "${env:TEST_REDIS_PASSWORD}"})
+	require.Equal(t, map[string]interface{}{"name": "redis-secret"}, cfg.GetRaw())
+
+	base.Update(map[string]interface{}{"name": "${env:TEST_UNKNOWN}"})
+	require.Equal(t, map[string]interface{}{"name": "redis-secret"}, cfg.GetRaw())
+}
+
+func TestInterpolateCustomResolver(t *testing.T) {
+	resolvers := config.DefResolvers()
+	resolvers["vault"] = func(arg string) (string, bool, error) {
+		if arg == "db/password" {
+			return "vault-secret", true, nil
+		}
+
+		return "", false, nil
+	}
+
+	data, err := config.Interpolate(map[string]interface{}{
+		"password": "${vault:db/password}",
+		"user":     "${vault:db/user:-admin}",
+	}, resolvers)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"password": "vault-secret", "user": "admin"}, data)
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	_, err := config.Interpolate(map[string]interface{}{
+		"redis": map[string]interface{}{
+			"password": "${env:TEST_UNKNOWN}",
+			"address":  "${key:redis.host}",
+		},
+		"a": "${key:b}",
+		"b": map[string]interface{}{"c": "x${key:a}"},
+	}, nil)
+
+	var multiErr *i2s.MultiError
+
+	require.ErrorAs(t, err, &multiErr)
+	require.Len(t, multiErr.Errors, 4)
+	require.ErrorIs(t, err, config.ErrUnresolvedReference)
+	require.ErrorIs(t, err, config.ErrReferenceCycle)
+	require.ErrorContains(t, err, "unresolved reference 'env:TEST_UNKNOWN' at 'redis.password'")
+	require.ErrorContains(t, err, "unresolved reference 'key:redis.host' at 'redis.address'")
+	require.ErrorContains(t, err, "reference cycle 'b -> a -> b' at 'a'")
+	require.ErrorContains(t, err, "reference cycle 'a -> b -> a' at 'b.c'")
+
+	for _, value := range []string{"${env}", "${:x}", "${env:}", "${env:X", "${key:redis}x"} {
+		_, err = config.Interpolate(map[string]interface{}{
+			"key":   value,
+			"redis": map[string]interface{}{"host": "localhost"},
+		}, nil)
+		require.ErrorIs(t, err, config.ErrInvalidReference, value)
+	}
+
+	_, err = config.Interpolate(map[string]interface{}{"key": "${vault:x}"}, nil)
+	require.ErrorIs(t, err, config.ErrUnknownResolver)
+	require.ErrorContains(t, err, "at 'key'")
+
+	_, err = config.Interpolate(map[string]interface{}{"key": "${file:" + t.TempDir() + "}"}, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,338 @@
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// KeyWildcard is the unquoted key segment that matches all elements of a map or a slice.
+const KeyWildcard = "*"
+
+// KeySegment structure is one parsed segment of composite key.
+type KeySegment struct {
+	// Name is the map key or the slice index.
+	Name string
+	// Wildcard is true if the segment matches all elements of a map or a slice.
+	Wildcard bool
+}
+
+// ParseKey function parses composite key into segments. Key grammar is the following:
+//
+//   - segments are separated by dots: 'http.server.address', empty segments are ignored;
+//   - segment can be put into double quotes to contain dots: 'hosts."db.example.com".port';
+//   - any character can be escaped with backslash both inside and outside quotes: 'hosts.db\.example\.com.port';
+//   - segment that is a number addresses slice element: 'servers.0.address';
+//   - unquoted segment '*' matches all elements of a map or a slice: 'servers.*.address'.
+//
+// It is used by GetRawByKey method, so the same grammar works for GetByKey method and NewInner function.
+func ParseKey(key string) ([]KeySegment, error) {
+	var segments []KeySegment
+
+	var builder strings.Builder
+
+	quoted, inQuotes, escaped, closed := false, false, false, false
+
+	finish := func() {
+		name := builder.String()
+
+		if name != "" || quoted {
+			segments = append(segments, KeySegment{Name: name, Wildcard: !quoted && !escaped && name == KeyWildcard})
+		}
+
+		builder.Reset()
+
+		quoted, escaped, closed = false, false, false
+	}
+
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+
+		switch {
+		case c == '\\':
+			if i+1 == len(key) {
+				return nil, invalidKeyError(key, "trailing backslash")
+			}
+
+			i++
+
+			builder.WriteByte(key[i])
+
+			escaped = true
+		case inQuotes:
+			if c == '"' {
+				inQuotes, closed = false, true
+			} else {
+				builder.WriteByte(c)
+			}
+		case c == '.':
+			finish()
+		case closed:
+			return nil, invalidKeyError(key, "unexpected character after closing quote")
+		case c == '"':
+			if builder.Len() > 0 || escaped {
+				return nil, invalidKeyError(key, "unexpected quote inside segment")
+			}
+
+			quoted, inQuotes = true, true
+		default:
+			builder.WriteByte(c)
+		}
+	}
+
+	if inQuotes {
+		return nil, invalidKeyError(key, "unclosed quote")
+	}
+
+	finish()
+
+	return segments, nil
+}
+
+// JoinKey function joins segments into composite key quoting segments that contain dots, quotes, backslashes, are
+// empty or equal to wildcard, so the key addresses exactly these segments.
+func JoinKey(segments ...string) string {
+	quotedSegments := make([]string, 0, len(segments))
+
+	for _, segment := range segments {
+		quotedSegments = append(quotedSegments, quoteKeySegment(segment))
+	}
+
+	return strings.Join(quotedSegments, ".")
+}
+
+// quoteKeySegment function puts key segment into quotes if it is needed.
+func quoteKeySegment(segment string) string {
+	if segment != "" && segment != KeyWildcard && !strings.ContainsAny(segment, `."\`) {
+		return segment
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	return `"` + replacer.Replace(segment) + `"`
+}
+
+// keyPath function normalizes composite key, so it can be used in error messages.
+func keyPath(key string) string {
+	segments, err := ParseKey(key)
+	if err != nil {
+		return key
+	}
+
+	quotedSegments := make([]string, 0, len(segments))
+
+	for _, segment := range segments {
+		if segment.Wildcard {
+			quotedSegments = append(quotedSegments, KeyWildcard)
+		} else {
+			quotedSegments = append(quotedSegments, quoteKeySegment(segment.Name))
+		}
+	}
+
+	return strings.Join(quotedSegments, ".")
+}
+
+// getByKey function retrieves value lying under composite key inside data. If key contains wildcards, all matching
+// values are retrieved as a slice.
+func getByKey(data interface{}, key string) (interface{}, error) {
+	segments, err := ParseKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	hasWildcard := false
+
+	for _, segment := range segments {
+		if segment.Wildcard {
+			hasWildcard = true
+			break
+		}
+	}
+
+	if !hasWildcard {
+		for _, segment := range segments {
+			var ok bool
+
+			if data, ok = childValue(data, segment.Name); !ok {
+				return nil, ErrNoSuchKey
+			}
+		}
+
+		return data, nil
+	}
+
+	matches := matchValues(data, segments, nil)
+	if len(matches) == 0 {
+		return nil, ErrNoSuchKey
+	}
+
+	return matches, nil
+}
+
+// SetRawByKey function retrieves copy of raw configuration data where value under composite key is replaced with the
+// provided one. Key grammar is the same as for ParseKey function, but wildcards are not allowed. Missing map keys are
+// created, while slice elements must exist, and scalars can't be traversed. Provided data itself is not modified,
+// only maps and slices on the key path are copied.
+func SetRawByKey(data map[string]interface{}, key string, value interface{}) (map[string]interface{}, error) {
+	segments, err := ParseKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(segments) == 0 {
+		valueMap, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, ErrNotMap
+		}
+
+		return valueMap, nil
+	}
+
+	for _, segment := range segments {
+		if segment.Wildcard {
+			return nil, invalidKeyError(key, "wildcard is not allowed")
+		}
+	}
+
+	result, err := setValue(data, segments, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(map[string]interface{}), nil
+}
+
+// setValue function retrieves copy of data where value under key segments is replaced with the provided one.
+func setValue(data interface{}, segments []KeySegment, value interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	name := segments[0].Name
+
+	switch typedData := data.(type) {
+	case nil:
+		child, err := setValue(nil, segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{name: child}, nil
+	case map[string]interface{}:
+		child, err := setValue(typedData[name], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make(map[string]interface{}, len(typedData)+1)
+		for key, elem := range typedData {
+			result[key] = elem
+		}
+
+		result[name] = child
+
+		return result, nil
+	case []interface{}, []map[string]interface{}:
+		index, ok := sliceIndex(name, len(childValues(typedData)))
+		if !ok {
+			return nil, ErrNoSuchKey
+		}
+
+		result := childValues(typedData)
+
+		child, err := setValue(result[index], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		result[index] = child
+
+		return result, nil
+	default:
+		return nil, ErrNotMap
+	}
+}
+
+// matchValues function appends all values matching key segments to the list.
+func matchValues(data interface{}, segments []KeySegment, matches []interface{}) []interface{} {
+	if len(segments) == 0 {
+		return append(matches, data)
+	}
+
+	if !segments[0].Wildcard {
+		if child, ok := childValue(data, segments[0].Name); ok {
+			matches = matchValues(child, segments[1:], matches)
+		}
+
+		return matches
+	}
+
+	for _, child := range childValues(data) {
+		matches = matchValues(child, segments[1:], matches)
+	}
+
+	return matches
+}
+
+// childValue function retrieves value of the map by key or value of the slice by index.
+func childValue(data interface{}, name string) (interface{}, bool) {
+	switch typedData := data.(type) {
+	case map[string]interface{}:
+		value, ok := typedData[name]
+		return value, ok
+	case []interface{}:
+		if index, ok := sliceIndex(name, len(typedData)); ok {
+			return typedData[index], true
+		}
+	case []map[string]interface{}:
+		if index, ok := sliceIndex(name, len(typedData)); ok {
+			return typedData[index], true
+		}
+	}
+
+	return nil, false
+}
+
+// childValues function retrieves all values of the map sorted by their keys, or all values of the slice.
+func childValues(data interface{}) []interface{} {
+	var values []interface{}
+
+	switch typedData := data.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typedData))
+		for key := range typedData {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			values = append(values, typedData[key])
+		}
+	case []interface{}:
+		values = append(values, typedData...)
+	case []map[string]interface{}:
+		for _, elem := range typedData {
+			values = append(values, elem)
+		}
+	}
+
+	return values
+}
+
+// sliceIndex function parses key segment as a slice index.
+func sliceIndex(name string, length int) (int, bool) {
+	index, err := strconv.Atoi(name)
+	if err != nil || index < 0 || index >= length {
+		return 0, false
+	}
+
+	return index, true
+}
+
+// invalidKeyError function creates error about malformed composite key.
+func invalidKeyError(key string, detail string) error {
+	return errors.NewFmt("invalid key '%s' (%s)", key, detail).WithCause(ErrInvalidKey)
+}
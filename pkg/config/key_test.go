@@ -0,0 +1,203 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder/json"
+	"github.com/lightstar/golib/pkg/config/encoder/toml"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		expected []config.KeySegment
+	}{
+		{name: "Empty", key: "", expected: nil},
+		{name: "Plain", key: "a.b.c", expected: []config.KeySegment{{Name: "a"}, {Name: "b"}, {Name: "c"}}},
+		{name: "EmptySegments", key: ".a..b.", expected: []config.KeySegment{{Name: "a"}, {Name: "b"}}},
+		{name: "Index", key: "servers.0", expected: []config.KeySegment{{Name: "servers"}, {Name: "0"}}},
+		{
+			name:     "Quoted",
+			key:      `hosts."db.example.com".port`,
+			expected: []config.KeySegment{{Name: "hosts"}, {Name: "db.example.com"}, {Name: "port"}},
+		},
+		{
+			name:     "Escaped",
+			key:      `hosts.db\.example\.com."a\"b"`,
+			expected: []config.KeySegment{{Name: "hosts"}, {Name: "db.example.com"}, {Name: `a"b`}},
+		},
+		{name: "QuotedEmpty", key: `a.""`, expected: []config.KeySegment{{Name: "a"}, {Name: ""}}},
+		{
+			name:     "Wildcard",
+			key:      `servers.*."*".\*`,
+			expected: []config.KeySegment{{Name: "servers"}, {Name: "*", Wildcard: true}, {Name: "*"}, {Name: "*"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			segments, err := config.ParseKey(tt.key)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, segments)
+		})
+	}
+}
+
+func TestParseKeyErrors(t *testing.T) {
+	for _, key := range []string{`a."b`, `a\`, `a."b"c`, `a.b"c"`} {
+		_, err := config.ParseKey(key)
+		require.Error(t, err, key)
+		require.True(t, errors.Is(err, config.ErrInvalidKey), key)
+	}
+}
+
+func TestJoinKey(t *testing.T) {
+	key := config.JoinKey("hosts", "db.example.com", `a"b`, "*", "", "port")
+	require.Equal(t, `hosts."db.example.com"."a\"b"."*"."".port`, key)
+
+	segments, err := config.ParseKey(key)
+	require.NoError(t, err)
+	require.Equal(t, []config.KeySegment{
+		{Name: "hosts"}, {Name: "db.example.com"}, {Name: `a"b`}, {Name: "*"}, {Name: ""}, {Name: "port"},
+	}, segments)
+}
+
+func TestGetRawByKeyPaths(t *testing.T) {
+	cfg, err := config.NewFromBytes([]byte(`{
+		"servers": [
+			{"address": "10.0.0.1", "port": 80},
+			{"address": "10.0.0.2", "port": 81}
+		],
+		"hosts": {
+			"db.example.com": {"port": 5432},
+			"*": {"port": 1}
+		}
+	}`), json.Encoder)
+	require.NoError(t, err)
+
+	value, err := cfg.GetRawByKey("servers.1.address")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.2", value)
+
+	value, err = cfg.GetRawByKey(`hosts."db.example.com".port`)
+	require.NoError(t, err)
+	require.Equal(t, float64(5432), value)
+
+	value, err = cfg.GetRawByKey(`hosts.db\.example\.com.port`)
+	require.NoError(t, err)
+	require.Equal(t, float64(5432), value)
+
+	value, err = cfg.GetRawByKey(`hosts."*".port`)
+	require.NoError(t, err)
+	require.Equal(t, float64(1), value)
+
+	value, err = cfg.GetRawByKey("servers.*.address")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"10.0.0.1", "10.0.0.2"}, value)
+
+	value, err = cfg.GetRawByKey("hosts.*.port")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{float64(1), float64(5432)}, value)
+
+	for _, key := range []string{"servers.2.address", "servers.-1", "servers.first", "servers.*.unknown"} {
+		_, err = cfg.GetRawByKey(key)
+		require.Same(t, config.ErrNoSuchKey, err, key)
+	}
+
+	_, err = cfg.GetRawByKey(`hosts."db`)
+	require.True(t, errors.Is(err, config.ErrInvalidKey))
+
+	var addresses []string
+
+	require.NoError(t, cfg.GetByKey("servers.*.address", &addresses))
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, addresses)
+
+	var port int
+
+	require.NoError(t, cfg.GetByKey(`hosts."db.example.com".port`, &port))
+	require.Equal(t, 5432, port)
+
+	var address string
+
+	err = cfg.GetByKey("servers.0.port", &address)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "servers.0.port")
+
+	cfgInner, err := config.NewInner("servers.1", cfg)
+	require.NoError(t, err)
+
+	value, err = cfgInner.GetRawByKey("port")
+	require.NoError(t, err)
+	require.Equal(t, float64(81), value)
+
+	_, err = config.NewInner("servers.*", cfg)
+	require.Same(t, config.ErrNotMap, err)
+}
+
+func TestGetRawByKeyTOMLTables(t *testing.T) {
+	cfg, err := config.NewFromBytes(configtest.SampleConfigDataTOML, toml.Encoder)
+	require.NoError(t, err)
+
+	value, err := cfg.GetRawByKey("profile.children.1.name")
+	require.NoError(t, err)
+	require.Equal(t, "Olivia", value)
+
+	var child configtest.ChildProfile
+
+	require.NoError(t, cfg.GetByKey("profile.children.0", &child))
+	require.Equal(t, configtest.ExpectedSampleConfig.Profile.Children[0], child)
+
+	var names []string
+
+	require.NoError(t, cfg.GetByKey("profile.children.*.name", &names))
+	require.Equal(t, []string{"George", "Olivia"}, names)
+}
+
+func TestSetRawByKey(t *testing.T) {
+	data := map[string]interface{}{
+		"http":    map[string]interface{}{"address": "127.0.0.1:8080"},
+		"servers": []interface{}{map[string]interface{}{"port": 80}},
+		"name":    "app",
+	}
+
+	result, err := config.SetRawByKey(data, "http.timeout", 3)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"address": "127.0.0.1:8080", "timeout": 3}, result["http"])
+	require.Equal(t, map[string]interface{}{"address": "127.0.0.1:8080"}, data["http"])
+
+	result, err = config.SetRawByKey(result, `hosts."db.example.com".port`, 5432)
+	require.NoError(t, err)
+
+	value, err := config.NewFromRaw(result).GetRawByKey(`hosts."db.example.com".port`)
+	require.NoError(t, err)
+	require.Equal(t, 5432, value)
+
+	result, err = config.SetRawByKey(data, "servers.0.port", 81)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{map[string]interface{}{"port": 81}}, result["servers"])
+	require.Equal(t, []interface{}{map[string]interface{}{"port": 80}}, data["servers"])
+
+	result, err = config.SetRawByKey(data, "", map[string]interface{}{"name": "other"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"name": "other"}, result)
+
+	_, err = config.SetRawByKey(data, "servers.1.port", 82)
+	require.Same(t, config.ErrNoSuchKey, err)
+
+	_, err = config.SetRawByKey(data, "name.first", "a")
+	require.Same(t, config.ErrNotMap, err)
+
+	_, err = config.SetRawByKey(data, "servers.*.port", 82)
+	require.ErrorIs(t, err, config.ErrInvalidKey)
+
+	_, err = config.SetRawByKey(data, "", 1)
+	require.Same(t, config.ErrNotMap, err)
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"strings"
+)
+
+// RedactedValue is the value that replaces secrets in redacted configuration data.
+const RedactedValue = "******"
+
+// DefSecretPatterns function retrieves default patterns of key names holding secrets.
+func DefSecretPatterns() []string {
+	return []string{"password", "passwd", "token", "secret", "credential", "apikey", "api_key", "private"}
+}
+
+// Marshal method converts configuration data back into bytes using chosen encoder.
+// Most likely you will use one of the predefined encoders: json.Encoder, yaml.Encoder or toml.Encoder.
+func (config *Config) Marshal(encoder Marshaler) ([]byte, error) {
+	return encoder.Marshal(config.GetRaw())
+}
+
+// Redact method retrieves deep copy of configuration data where values of keys containing any of provided patterns
+// (case-insensitively) are replaced with RedactedValue. If no patterns are provided, DefSecretPatterns are used.
+func (config *Config) Redact(patterns ...string) map[string]interface{} {
+	if len(patterns) == 0 {
+		patterns = DefSecretPatterns()
+	}
+
+	lowerPatterns := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		lowerPatterns = append(lowerPatterns, strings.ToLower(pattern))
+	}
+
+	return redactMap(config.GetRaw(), lowerPatterns)
+}
+
+// Dump method converts redacted configuration data into bytes using chosen encoder, so it can be safely written
+// into logs or shown to operators. Patterns are the same as in Redact method.
+func (config *Config) Dump(encoder Marshaler, patterns ...string) ([]byte, error) {
+	return encoder.Marshal(config.Redact(patterns...))
+}
+
+// redactMap function creates redacted deep copy of the map.
+func redactMap(data map[string]interface{}, patterns []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(data))
+
+	for key, value := range data {
+		if isSecretKey(key, patterns) {
+			result[key] = RedactedValue
+		} else {
+			result[key] = redactValue(value, patterns)
+		}
+	}
+
+	return result
+}
+
+// redactValue function creates redacted deep copy of the value if it is a map or a slice.
+func redactValue(value interface{}, patterns []string) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		return redactMap(typedValue, patterns)
+	case []interface{}:
+		result := make([]interface{}, 0, len(typedValue))
+		for _, elem := range typedValue {
+			result = append(result, redactValue(elem, patterns))
+		}
+
+		return result
+	case []map[string]interface{}:
+		result := make([]map[string]interface{}, 0, len(typedValue))
+		for _, elem := range typedValue {
+			result = append(result, redactMap(elem, patterns))
+		}
+
+		return result
+	default:
+		return value
+	}
+}
+
+// isSecretKey function checks if key name contains any of secret patterns.
+func isSecretKey(key string, patterns []string) bool {
+	lowerKey := strings.ToLower(key)
+
+	for _, pattern := range patterns {
+		if strings.Contains(lowerKey, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder/json"
+	"github.com/lightstar/golib/pkg/config/encoder/toml"
+	"github.com/lightstar/golib/pkg/config/encoder/yaml"
+)
+
+func TestMarshal(t *testing.T) {
+	cfg, err := config.NewFromBytes(configtest.SampleConfigDataJSON, json.Encoder)
+	require.NoError(t, err)
+
+	for _, encoder := range []config.Marshaler{json.Encoder, yaml.Encoder, toml.Encoder} {
+		data, err := cfg.Marshal(encoder)
+		require.NoError(t, err)
+
+		marshaledCfg, err := config.NewFromBytes(data, encoder)
+		require.NoError(t, err)
+
+		var sample configtest.SampleConfigType
+
+		require.NoError(t, marshaledCfg.Get(&sample))
+		require.Equal(t, configtest.ExpectedSampleConfig, sample)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	raw := map[string]interface{}{
+		"name": "app",
+		"redis": map[string]interface{}{
+			"address":  "127.0.0.1:6379",
+			"password": "qwerty",
+		},
+		"users": []interface{}{
+			map[string]interface{}{"login": "admin", "apiToken": "abc"},
+		},
+		"clients": []map[string]interface{}{
+			{"id": "web", "clientSecret": map[string]interface{}{"value": "xyz"}},
+		},
+	}
+
+	cfg := config.NewFromRaw(raw)
+
+	require.Equal(t, map[string]interface{}{
+		"name": "app",
+		"redis": map[string]interface{}{
+			"address":  "127.0.0.1:6379",
+			"password": config.RedactedValue,
+		},
+		"users": []interface{}{
+			map[string]interface{}{"login": "admin", "apiToken": config.RedactedValue},
+		},
+		"clients": []map[string]interface{}{
+			{"id": "web", "clientSecret": config.RedactedValue},
+		},
+	}, cfg.Redact())
+
+	require.Equal(t, "qwerty", cfg.GetRaw()["redis"].(map[string]interface{})["password"])
+
+	redacted := cfg.Redact("ADDRESS")
+	require.Equal(t, config.RedactedValue, redacted["redis"].(map[string]interface{})["address"])
+	require.Equal(t, "qwerty", redacted["redis"].(map[string]interface{})["password"])
+
+	data, err := cfg.Dump(json.Encoder)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "qwerty")
+	require.Contains(t, string(data), `"password": "******"`)
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"reflect"
+)
+
+// SliceStrategy defines how slices are merged when the same key holds slice in several configuration layers.
+type SliceStrategy int
+
+const (
+	// SliceReplace strategy makes slice from the later layer completely replace slice from the earlier one.
+	SliceReplace SliceStrategy = iota
+	// SliceAppend strategy makes elements of slice from the later layer appended to slice from the earlier one.
+	SliceAppend
+)
+
+// Merge function creates new configuration service by deep-merging data of provided ones, where later layers
+// override earlier ones. Slices are replaced, use MergeWithStrategy if you need them appended.
+//
+// Merged configuration service follows updates of all layers, so you can use watched sources here. Each update of any
+// layer rebuilds merged data from the current data of all layers. Use Detach method of the merged configuration
+// service when it is no longer needed while layers stay alive.
+func Merge(cfgs ...*Config) *Config {
+	return MergeWithStrategy(SliceReplace, cfgs...)
+}
+
+// MergeWithStrategy function does the same as Merge, but uses provided strategy to merge slices.
+func MergeWithStrategy(strategy SliceStrategy, cfgs ...*Config) *Config {
+	merged := NewFromRaw(mergeConfigs(strategy, cfgs)).inherit(cfgs...)
+
+	for _, cfg := range cfgs {
+		cfg.follow(merged, func(map[string]interface{}) (map[string]interface{}, error) {
+			return mergeConfigs(strategy, cfgs), nil
+		})
+	}
+
+	return merged
+}
+
+// MergeRaw function deep-merges raw configuration data using provided strategy to merge slices.
+// Later data overrides earlier one. Provided maps are not modified.
+func MergeRaw(strategy SliceStrategy, data ...map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+
+	for _, dataElem := range data {
+		result = mergeMaps(strategy, result, dataElem)
+	}
+
+	return result
+}
+
+// mergeConfigs function deep-merges current data of provided configuration services.
+func mergeConfigs(strategy SliceStrategy, cfgs []*Config) map[string]interface{} {
+	data := make([]map[string]interface{}, 0, len(cfgs))
+
+	for _, cfg := range cfgs {
+		data = append(data, cfg.GetRaw())
+	}
+
+	return MergeRaw(strategy, data...)
+}
+
+// mergeMaps function deep-merges 'src' map into the copy of 'dst' one.
+func mergeMaps(strategy SliceStrategy, dst map[string]interface{}, src map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(dst)+len(src))
+
+	for key, value := range dst {
+		result[key] = value
+	}
+
+	for key, srcValue := range src {
+		result[key] = mergeValues(strategy, result[key], srcValue)
+	}
+
+	return result
+}
+
+// mergeValues function merges two values of the same key.
+func mergeValues(strategy SliceStrategy, dst interface{}, src interface{}) interface{} {
+	if srcMap, ok := src.(map[string]interface{}); ok {
+		dstMap, ok := dst.(map[string]interface{})
+		if !ok {
+			dstMap = nil
+		}
+
+		return mergeMaps(strategy, dstMap, srcMap)
+	}
+
+	if strategy == SliceAppend {
+		dstValue, srcValue := reflect.ValueOf(dst), reflect.ValueOf(src)
+
+		if dstValue.Kind() == reflect.Slice && srcValue.Kind() == reflect.Slice {
+			result := make([]interface{}, 0, dstValue.Len()+srcValue.Len())
+
+			for i := 0; i < dstValue.Len(); i++ {
+				result = append(result, dstValue.Index(i).Interface())
+			}
+
+			for i := 0; i < srcValue.Len(); i++ {
+				result = append(result, srcValue.Index(i).Interface())
+			}
+
+			return result
+		}
+	}
+
+	return src
+}
@@ -0,0 +1,141 @@
+package config_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config"
+)
+
+func TestMerge(t *testing.T) {
+	base := config.NewFromRaw(map[string]interface{}{
+		"name": "Peter",
+		"http": map[string]interface{}{
+			"address": "127.0.0.1:8080",
+			"name":    "server",
+		},
+		"tags": []interface{}{"a", "b"},
+	})
+
+	override := config.NewFromRaw(map[string]interface{}{
+		"http": map[string]interface{}{
+			"address": "0.0.0.0:80",
+		},
+		"tags": []string{"c"},
+	})
+
+	cfg := config.Merge(base, override)
+	require.Equal(t, map[string]interface{}{
+		"name": "Peter",
+		"http": map[string]interface{}{
+			"address": "0.0.0.0:80",
+			"name":    "server",
+		},
+		"tags": []string{"c"},
+	}, cfg.GetRaw())
+
+	cfg = config.MergeWithStrategy(config.SliceAppend, base, override)
+	require.Equal(t, []interface{}{"a", "b", "c"}, cfg.GetRaw()["tags"])
+
+	require.Equal(t, map[string]interface{}{
+		"address": "127.0.0.1:8080",
+		"name":    "server",
+	}, base.GetRaw()["http"])
+}
+
+func TestMergeUpdate(t *testing.T) {
+	base := config.NewFromRaw(map[string]interface{}{"name": "Peter", "age": 30})
+	override := config.NewFromRaw(map[string]interface{}{"age": 32})
+
+	cfg := config.Merge(base, override)
+	require.Equal(t, map[string]interface{}{"name": "Peter", "age": 32}, cfg.GetRaw())
+
+	updated := false
+
+	cfg.Subscribe(func(*config.Config) {
+		updated = true
+	})
+
+	base.Update(map[string]interface{}{"name": "George", "age": 30})
+	require.True(t, updated)
+	require.Equal(t, map[string]interface{}{"name": "George", "age": 32}, cfg.GetRaw())
+
+	override.Update(map[string]interface{}{})
+	require.Equal(t, map[string]interface{}{"name": "George", "age": 30}, cfg.GetRaw())
+}
+
+func TestMergeConcurrentUpdates(t *testing.T) {
+	first := config.NewFromRaw(map[string]interface{}{"first": 0})
+	second := config.NewFromRaw(map[string]interface{}{"second": 0})
+
+	cfg := config.Merge(first, second)
+
+	const updates = 100
+
+	var wg sync.WaitGroup
+
+	for _, layer := range []struct {
+		cfg *config.Config
+		key string
+	}{{cfg: first, key: "first"}, {cfg: second, key: "second"}} {
+		layer := layer
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 1; i <= updates; i++ {
+				layer.cfg.Update(map[string]interface{}{layer.key: i})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, map[string]interface{}{"first": updates, "second": updates}, cfg.GetRaw())
+}
+
+func TestMergeDetach(t *testing.T) {
+	base := config.NewFromRaw(map[string]interface{}{"name": "Peter"})
+	override := config.NewFromRaw(map[string]interface{}{"age": 32})
+
+	cfg := config.Merge(base, override)
+
+	cfg.Detach()
+	cfg.Detach()
+
+	base.Update(map[string]interface{}{"name": "George"})
+	require.Equal(t, map[string]interface{}{"name": "Peter", "age": 32}, cfg.GetRaw())
+
+	profiled, err := config.NewWithProfiles(base)
+	require.NoError(t, err)
+
+	interpolated, err := config.NewWithInterpolation(profiled, nil)
+	require.NoError(t, err)
+
+	var errs []error
+
+	interpolated.WithErrorFunc(func(err error) {
+		errs = append(errs, err)
+	})
+
+	interpolated.Detach()
+
+	profiled.Update(map[string]interface{}{"name": "${key:missing}"})
+	require.Equal(t, map[string]interface{}{"name": "George"}, interpolated.GetRaw())
+	require.Empty(t, errs)
+}
+
+func TestMergeRaw(t *testing.T) {
+	require.Equal(t, map[string]interface{}{}, config.MergeRaw(config.SliceReplace))
+
+	require.Equal(t, map[string]interface{}{
+		"key": map[string]interface{}{"foo": "bar"},
+	}, config.MergeRaw(config.SliceReplace,
+		map[string]interface{}{"key": "value"},
+		map[string]interface{}{"key": map[string]interface{}{"foo": "bar"}},
+	))
+}
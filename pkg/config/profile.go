@@ -0,0 +1,134 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// ProfilesKey is the key of configuration section that holds named profiles, see NewWithProfiles function.
+const ProfilesKey = "profiles"
+
+// NewWithProfiles function creates new configuration service with data of provided one, where sections of selected
+// profiles are deep-merged on top of the base data in order. Profiles are defined under 'profiles' key, which is
+// removed from the result even if no profiles are selected:
+//
+//	http:
+//	  address: 127.0.0.1:8080
+//	profiles:
+//	  dev:
+//	    debug: true
+//	  prod:
+//	    http:
+//	      address: 0.0.0.0:80
+//
+// Empty profile names are skipped, so ParseProfiles result can be passed as is. Unknown profile is reported as
+// ErrUnknownProfile. Selected profile names can be retrieved later with Profiles method.
+//
+// Created configuration service follows updates of the provided one. Updates where selected profiles are missing are
+// ignored and reported to the error function, see WithErrorFunc method.
+func NewWithProfiles(config *Config, profiles ...string) (*Config, error) {
+	names := make([]string, 0, len(profiles))
+
+	for _, profile := range profiles {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			names = append(names, profile)
+		}
+	}
+
+	data, err := applyProfiles(config.GetRaw(), names)
+	if err != nil {
+		return nil, err
+	}
+
+	profiled := NewFromRaw(data).inherit(config).addProfiles(names...)
+
+	config.follow(profiled, func(data map[string]interface{}) (map[string]interface{}, error) {
+		return applyProfiles(data, names)
+	})
+
+	return profiled, nil
+}
+
+// ParseProfiles function splits comma-separated list of profile names, such as value of environment variable.
+func ParseProfiles(value string) []string {
+	var profiles []string
+
+	for _, profile := range strings.Split(value, ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+
+	return profiles
+}
+
+// Profiles method retrieves names of active profiles in the order they were applied. Configuration services derived
+// from the one with active profiles, such as ones with environment overrides or merged ones, keep them.
+func (config *Config) Profiles() []string {
+	config.mu.RLock()
+	defer config.mu.RUnlock()
+
+	return append([]string(nil), config.profiles...)
+}
+
+// addProfiles method adds profile names to the active profiles skipping duplicates, and returns the same
+// configuration service.
+func (config *Config) addProfiles(profiles ...string) *Config {
+	config.mu.Lock()
+	defer config.mu.Unlock()
+
+	for _, profile := range profiles {
+		isActive := false
+
+		for _, activeProfile := range config.profiles {
+			if activeProfile == profile {
+				isActive = true
+				break
+			}
+		}
+
+		if !isActive {
+			config.profiles = append(config.profiles, profile)
+		}
+	}
+
+	return config
+}
+
+// applyProfiles function merges sections of selected profiles on top of the base data without 'profiles' section.
+func applyProfiles(data map[string]interface{}, profiles []string) (map[string]interface{}, error) {
+	base := make(map[string]interface{}, len(data))
+
+	for key, value := range data {
+		if key != ProfilesKey {
+			base[key] = value
+		}
+	}
+
+	layers := []map[string]interface{}{base}
+
+	var sections map[string]interface{}
+
+	if rawSections, ok := data[ProfilesKey]; ok {
+		if sections, ok = rawSections.(map[string]interface{}); !ok {
+			return nil, errors.NewFmt("'%s' section is not a map", ProfilesKey).WithCause(ErrNotMap)
+		}
+	}
+
+	for _, profile := range profiles {
+		rawSection, ok := sections[profile]
+		if !ok {
+			return nil, errors.NewFmt("unknown profile '%s'", profile).WithCause(ErrUnknownProfile)
+		}
+
+		section, ok := rawSection.(map[string]interface{})
+		if !ok {
+			return nil, errors.NewFmt("profile '%s' is not a map", profile).WithCause(ErrNotMap)
+		}
+
+		layers = append(layers, section)
+	}
+
+	return MergeRaw(SliceReplace, layers...), nil
+}
@@ -0,0 +1,81 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder/yaml"
+)
+
+func TestProfiles(t *testing.T) {
+	base, err := config.NewFromBytes([]byte(`
+http:
+  address: 127.0.0.1:8080
+  timeout: 3
+tags: [a, b]
+profiles:
+  dev:
+    debug: true
+    tags: [dev]
+  prod:
+    http:
+      address: 0.0.0.0:80
+`), yaml.Encoder)
+	require.NoError(t, err)
+
+	cfg, err := config.NewWithProfiles(base, config.ParseProfiles(" dev , prod,")...)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"http": map[string]interface{}{
+			"address": "0.0.0.0:80",
+			"timeout": 3,
+		},
+		"tags":  []interface{}{"dev"},
+		"debug": true,
+	}, cfg.GetRaw())
+	require.Equal(t, []string{"dev", "prod"}, cfg.Profiles())
+	require.Empty(t, base.Profiles())
+
+	overridden := config.Merge(cfg, config.NewFromRaw(map[string]interface{}{"debug": false}))
+	require.Equal(t, []string{"dev", "prod"}, overridden.Profiles())
+
+	inner, err := config.NewInner("http", overridden)
+	require.NoError(t, err)
+	require.Equal(t, []string{"dev", "prod"}, inner.Profiles())
+
+	base.Update(map[string]interface{}{
+		"http":     map[string]interface{}{"address": "127.0.0.1:9090"},
+		"profiles": map[string]interface{}{"dev": map[string]interface{}{}, "prod": map[string]interface{}{}},
+	})
+	require.Equal(t, map[string]interface{}{
+		"http": map[string]interface{}{"address": "127.0.0.1:9090"},
+	}, cfg.GetRaw())
+
+	base.Update(map[string]interface{}{"name": "app"})
+	require.Equal(t, map[string]interface{}{
+		"http": map[string]interface{}{"address": "127.0.0.1:9090"},
+	}, cfg.GetRaw())
+
+	cfg, err = config.NewWithProfiles(base)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"name": "app"}, cfg.GetRaw())
+	require.Empty(t, cfg.Profiles())
+}
+
+func TestProfilesErrors(t *testing.T) {
+	cfg := config.NewFromRaw(map[string]interface{}{
+		"profiles": map[string]interface{}{"dev": "debug"},
+	})
+
+	_, err := config.NewWithProfiles(cfg, "prod")
+	require.ErrorIs(t, err, config.ErrUnknownProfile)
+
+	_, err = config.NewWithProfiles(cfg, "dev")
+	require.ErrorIs(t, err, config.ErrNotMap)
+
+	_, err = config.NewWithProfiles(config.NewFromRaw(map[string]interface{}{"profiles": 1}))
+	require.ErrorIs(t, err, config.ErrNotMap)
+}
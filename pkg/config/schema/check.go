@@ -0,0 +1,303 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// Parse function parses JSON Schema document, such as the one produced by marshaling generated schema.
+func Parse(data []byte) (*Schema, error) {
+	s := &Schema{}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, errors.NewFmt("json error (%s)", err.Error()).WithCause(err)
+	}
+
+	return s, nil
+}
+
+// Validate method checks raw configuration data against the schema. It supports keywords that Generate function
+// produces: type, format 'uri', pattern, enum, bounds of numbers, strings, lists and maps, items, properties,
+// required and additionalProperties. All violations are reported at once inside i2s.MultiError, each of them matches
+// ErrSchemaViolation and contains the key path of the value.
+func (s *Schema) Validate(value interface{}) error {
+	errs := s.check("", value, nil)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &i2s.MultiError{Errors: errs}
+}
+
+// check method recursively checks value against the schema appending violations to the list.
+func (s *Schema) check(path string, value interface{}, errs []error) []error {
+	if s == nil {
+		return errs
+	}
+
+	if s.Type != "" && !hasType(s.Type, value) {
+		return append(errs, violation(path, "expected %s, got %s", s.Type, typeName(value)))
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		errs = append(errs, violation(path, "value %v is not one of %v", value, s.Enum))
+	}
+
+	switch typedValue := value.(type) {
+	case string:
+		errs = s.checkString(path, typedValue, errs)
+	case map[string]interface{}:
+		errs = s.checkObject(path, typedValue, errs)
+	default:
+		if number, ok := toNumber(value); ok {
+			errs = s.checkNumber(path, number, errs)
+		} else if items, ok := toList(value); ok {
+			errs = s.checkArray(path, items, errs)
+		}
+	}
+
+	return errs
+}
+
+// checkString method checks string value against string keywords.
+func (s *Schema) checkString(path string, value string, errs []error) []error {
+	length := utf8.RuneCountInString(value)
+
+	if s.MinLength != nil && length < *s.MinLength {
+		errs = append(errs, violation(path, "length %d is less than %d", length, *s.MinLength))
+	}
+
+	if s.MaxLength != nil && length > *s.MaxLength {
+		errs = append(errs, violation(path, "length %d is greater than %d", length, *s.MaxLength))
+	}
+
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			errs = append(errs, violation(path, "invalid pattern '%s'", s.Pattern))
+		} else if !re.MatchString(value) {
+			errs = append(errs, violation(path, "'%s' doesn't match pattern '%s'", value, s.Pattern))
+		}
+	}
+
+	if s.Format == "uri" {
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" {
+			errs = append(errs, violation(path, "'%s' is not an absolute uri", value))
+		}
+	}
+
+	return errs
+}
+
+// checkNumber method checks numeric value against bounds.
+func (s *Schema) checkNumber(path string, value float64, errs []error) []error {
+	if s.Minimum != nil && value < *s.Minimum {
+		errs = append(errs, violation(path, "%v is less than %v", value, *s.Minimum))
+	}
+
+	if s.Maximum != nil && value > *s.Maximum {
+		errs = append(errs, violation(path, "%v is greater than %v", value, *s.Maximum))
+	}
+
+	return errs
+}
+
+// checkArray method checks list value against list keywords and its items against items schema.
+func (s *Schema) checkArray(path string, items []interface{}, errs []error) []error {
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		errs = append(errs, violation(path, "%d items is less than %d", len(items), *s.MinItems))
+	}
+
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		errs = append(errs, violation(path, "%d items is greater than %d", len(items), *s.MaxItems))
+	}
+
+	for i, item := range items {
+		errs = s.Items.check(joinPath(path, strconv.Itoa(i)), item, errs)
+	}
+
+	return errs
+}
+
+// checkObject method checks map value against object keywords and its values against property schemas.
+func (s *Schema) checkObject(path string, value map[string]interface{}, errs []error) []error {
+	if s.MinProperties != nil && len(value) < *s.MinProperties {
+		errs = append(errs, violation(path, "%d properties is less than %d", len(value), *s.MinProperties))
+	}
+
+	if s.MaxProperties != nil && len(value) > *s.MaxProperties {
+		errs = append(errs, violation(path, "%d properties is greater than %d", len(value), *s.MaxProperties))
+	}
+
+	for _, name := range s.Required {
+		if _, ok := value[name]; !ok {
+			errs = append(errs, violation(joinPath(path, name), "required property is missing"))
+		}
+	}
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	additional, allowed := s.additionalSchema()
+
+	for _, key := range keys {
+		keyPath := joinPath(path, key)
+
+		if property, ok := s.Properties[key]; ok {
+			errs = property.check(keyPath, value[key], errs)
+		} else if !allowed {
+			errs = append(errs, violation(keyPath, "unknown property"))
+		} else {
+			errs = additional.check(keyPath, value[key], errs)
+		}
+	}
+
+	return errs
+}
+
+// additionalSchema method retrieves schema of additional properties and whether they are allowed at all.
+func (s *Schema) additionalSchema() (*Schema, bool) {
+	switch additional := s.AdditionalProperties.(type) {
+	case bool:
+		return nil, additional
+	case *Schema:
+		return additional, true
+	case map[string]interface{}:
+		data, err := json.Marshal(additional)
+		if err != nil {
+			return nil, true
+		}
+
+		parsed, err := Parse(data)
+		if err != nil {
+			return nil, true
+		}
+
+		return parsed, true
+	default:
+		return nil, true
+	}
+}
+
+// hasType function checks if value has JSON Schema type.
+func hasType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case TypeObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case TypeArray:
+		_, ok := toList(value)
+		return ok
+	case TypeString:
+		_, ok := value.(string)
+		return ok
+	case TypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case TypeNumber:
+		_, ok := toNumber(value)
+		return ok
+	case TypeInteger:
+		number, ok := toNumber(value)
+		return ok && number == math.Trunc(number)
+	default:
+		return true
+	}
+}
+
+// typeName function retrieves JSON Schema type name of the value.
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return TypeObject
+	case string:
+		return TypeString
+	case bool:
+		return TypeBoolean
+	}
+
+	if _, ok := toNumber(value); ok {
+		return TypeNumber
+	}
+
+	if _, ok := toList(value); ok {
+		return TypeArray
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+// inEnum function checks if value is one of enum values. Numbers are compared by their values regardless of types.
+func inEnum(enum []interface{}, value interface{}) bool {
+	number, isNumber := toNumber(value)
+
+	for _, enumValue := range enum {
+		if enumNumber, ok := toNumber(enumValue); ok && isNumber {
+			if enumNumber == number {
+				return true
+			}
+
+			continue
+		}
+
+		if reflect.DeepEqual(enumValue, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toNumber function converts value of any numeric type to float64.
+func toNumber(value interface{}) (float64, bool) {
+	reflectValue := reflect.ValueOf(value)
+
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflectValue.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflectValue.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return reflectValue.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// toList function converts slice of any type to the list of values.
+func toList(value interface{}) ([]interface{}, bool) {
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Slice {
+		return nil, false
+	}
+
+	list := make([]interface{}, 0, reflectValue.Len())
+
+	for i := 0; i < reflectValue.Len(); i++ {
+		list = append(list, reflectValue.Index(i).Interface())
+	}
+
+	return list, true
+}
+
+// violation function creates error about value that doesn't conform to the schema.
+func violation(path string, format string, args ...interface{}) error {
+	return errors.NewFmt("schema violation at '%s' (%s)", path, fmt.Sprintf(format, args...)).
+		WithCause(ErrSchemaViolation)
+}
@@ -0,0 +1,81 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder/yaml"
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/schema"
+)
+
+func TestValidate(t *testing.T) {
+	generated, err := schema.Generate(&sampleConfig{})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(generated)
+	require.NoError(t, err)
+
+	parsed, err := schema.Parse(data)
+	require.NoError(t, err)
+
+	cfg, err := config.NewFromBytes([]byte(`
+mode: prod
+name: app
+port: 80
+ratio: 0.5
+timeout: 1m30s
+url: https://example.com
+tags: [a, b]
+limits: {cpu: 2}
+extra: [1, "2"]
+labels: {env: dev}
+next:
+  name: next
+  tags: [c]
+  limits: {memory: 512}
+`), yaml.Encoder)
+	require.NoError(t, err)
+
+	for _, s := range []*schema.Schema{generated, parsed} {
+		require.NoError(t, s.Validate(cfg.GetRaw()))
+	}
+
+	cfg, err = config.NewFromBytes([]byte(`
+mode: test
+port: 70000
+ratio: 2
+timeout: soon
+url: example
+tags: [a, b, c, d]
+limits: {cpu: two}
+labels: {env: 1}
+unknown: true
+`), yaml.Encoder)
+	require.NoError(t, err)
+
+	for _, s := range []*schema.Schema{generated, parsed} {
+		err = s.Validate(cfg.GetRaw())
+		require.ErrorIs(t, err, schema.ErrSchemaViolation)
+
+		var multiErr *i2s.MultiError
+
+		require.ErrorAs(t, err, &multiErr)
+		require.Len(t, multiErr.Errors, 10)
+
+		for _, path := range []string{
+			"'mode'", "'name'", "'port'", "'ratio'", "'timeout'", "'url'", "'tags'", "'limits.cpu'",
+			"'labels.env'", "'unknown'",
+		} {
+			require.ErrorContains(t, err, path)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := schema.Parse([]byte(`{"type": 1}`))
+	require.ErrorContains(t, err, "json error")
+}
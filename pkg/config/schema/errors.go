@@ -0,0 +1,20 @@
+package schema
+
+import (
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+var (
+	// ErrUnsupportedType error is returned when type of some value can't be described with JSON Schema.
+	ErrUnsupportedType = errors.New("unsupported type")
+
+	// ErrInvalidTag error is returned when 'default' or 'validate' tag of some field can't be described with
+	// JSON Schema.
+	ErrInvalidTag = errors.New("invalid tag")
+
+	// ErrSchemaViolation error is returned when configuration data doesn't conform to the schema.
+	ErrSchemaViolation = errors.New("schema violation")
+
+	// ErrUnsupportedEncoder error is returned when sample configuration can't be generated for provided encoder.
+	ErrUnsupportedEncoder = errors.New("unsupported encoder")
+)
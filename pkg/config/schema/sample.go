@@ -0,0 +1,277 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+const (
+	// sampleIndent is the indentation of nested values in sample configuration.
+	sampleIndent = "  "
+	// requiredComment is the comment that marks required fields in sample configuration.
+	requiredComment = "Required."
+)
+
+//nolint:gochecknoglobals // it is actually read-only, so it's ok to use it.
+var bareTOMLKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Sample method generates sample configuration in format of provided encoder, which must be one of predefined ones:
+// json.Encoder, yaml.Encoder or toml.Encoder. Each value is the default one or the simplest value satisfying
+// the schema. YAML and TOML samples have descriptions of fields in comments, JSON doesn't support comments at all.
+func (s *Schema) Sample(encoder config.Encoder) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+
+	switch encoder.Type() {
+	case "json":
+		err = writeJSON(&buf, s, "")
+		buf.WriteString("\n")
+	case "yaml":
+		err = writeYAML(&buf, s, "")
+	case "toml":
+		err = writeTOML(&buf, s, "")
+	default:
+		return nil, errors.NewFmt("can't generate sample for encoder '%s'", encoder.Type()).
+			WithCause(ErrUnsupportedEncoder)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeYAML function writes sample properties of the object in YAML format.
+func writeYAML(buf *bytes.Buffer, s *Schema, indent string) error {
+	for _, name := range s.order {
+		property := s.Properties[name]
+
+		writeComments(buf, s, name, indent)
+
+		key, err := yamlScalar(name)
+		if err != nil {
+			return err
+		}
+
+		if isStruct(property) {
+			buf.WriteString(indent + key + ":\n")
+
+			if err = writeYAML(buf, property, indent+sampleIndent); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		value, err := yamlScalar(sampleValue(property))
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(indent + key + ": " + value + "\n")
+	}
+
+	return nil
+}
+
+// writeTOML function writes sample properties of the object in TOML format. Nested objects are written as tables
+// after all other properties.
+func writeTOML(buf *bytes.Buffer, s *Schema, path string) error {
+	for _, name := range s.order {
+		property := s.Properties[name]
+		if isStruct(property) {
+			continue
+		}
+
+		writeComments(buf, s, name, "")
+
+		value := sampleValue(property)
+		if value == nil {
+			buf.WriteString("# " + tomlKey(name) + " =\n")
+			continue
+		}
+
+		valueStr, err := jsonScalar(value)
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(tomlKey(name) + " = " + valueStr + "\n")
+	}
+
+	for _, name := range s.order {
+		property := s.Properties[name]
+		if !isStruct(property) {
+			continue
+		}
+
+		tablePath := tomlKey(name)
+		if path != "" {
+			tablePath = path + "." + tablePath
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+
+		writeComments(buf, s, name, "")
+		buf.WriteString("[" + tablePath + "]\n")
+
+		if err := writeTOML(buf, property, tablePath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeJSON function writes sample value of the schema in JSON format keeping the order of properties.
+func writeJSON(buf *bytes.Buffer, s *Schema, indent string) error {
+	if !isStruct(s) {
+		value, err := jsonScalar(sampleValue(s))
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(value)
+
+		return nil
+	}
+
+	if len(s.order) == 0 {
+		buf.WriteString("{}")
+		return nil
+	}
+
+	buf.WriteString("{\n")
+
+	for i, name := range s.order {
+		key, err := jsonScalar(name)
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(indent + sampleIndent + key + ": ")
+
+		if err = writeJSON(buf, s.Properties[name], indent+sampleIndent); err != nil {
+			return err
+		}
+
+		if i+1 < len(s.order) {
+			buf.WriteString(",")
+		}
+
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString(indent + "}")
+
+	return nil
+}
+
+// writeComments function writes description of the object property and marks it if it is required.
+func writeComments(buf *bytes.Buffer, s *Schema, name string, indent string) {
+	var comments []string
+
+	if description := s.Properties[name].Description; description != "" {
+		comments = append(comments, strings.Split(description, "\n")...)
+	}
+
+	for _, required := range s.Required {
+		if required == name {
+			comments = append(comments, requiredComment)
+			break
+		}
+	}
+
+	for _, comment := range comments {
+		buf.WriteString(indent + "# " + comment + "\n")
+	}
+}
+
+// sampleValue function retrieves sample value of the schema that is not a structure: the default value, the first
+// allowed variant or the simplest value of the schema type.
+func sampleValue(s *Schema) interface{} {
+	if s.HasDefault {
+		return s.Default
+	}
+
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+
+	switch s.Type {
+	case TypeString:
+		return ""
+	case TypeInteger:
+		if s.Minimum != nil && *s.Minimum > 0 {
+			return int64(*s.Minimum)
+		}
+
+		return 0
+	case TypeNumber:
+		if s.Minimum != nil && *s.Minimum > 0 {
+			return *s.Minimum
+		}
+
+		return 0.
+	case TypeBoolean:
+		return false
+	case TypeArray:
+		return []interface{}{}
+	case TypeObject:
+		return map[string]interface{}{}
+	default:
+		return nil
+	}
+}
+
+// isStruct function checks if the schema describes a structure with known properties.
+func isStruct(s *Schema) bool {
+	return s.Type == TypeObject && s.Properties != nil
+}
+
+// yamlScalar function converts value into YAML representation that fits into a single line.
+func yamlScalar(value interface{}) (string, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", errors.NewFmt("yaml error (%s)", err.Error()).WithCause(err)
+	}
+
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+// jsonScalar function converts value into JSON representation, which is also valid for TOML scalars, empty arrays
+// and empty inline tables.
+func jsonScalar(value interface{}) (string, error) {
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return "", errors.NewFmt("json error (%s)", err.Error()).WithCause(err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// tomlKey function quotes TOML key if it can't be bare.
+func tomlKey(key string) string {
+	if bareTOMLKey.MatchString(key) {
+		return key
+	}
+
+	quoted, _ := jsonScalar(key)
+
+	return quoted
+}
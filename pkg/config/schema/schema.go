@@ -0,0 +1,385 @@
+// Package schema generates JSON Schema and commented sample configuration from structures that are filled with
+// configuration data, so configuration files can be validated by editors and CI before deploy.
+//
+// Schema is built according to the same tags that config package uses: 'config' tag defines key names and required
+// fields, 'default' tag defines default values, and 'validate' tag defines constraints. Descriptions are taken from
+// 'description' tag.
+//
+// Typical usage:
+//
+//	type AppConfig struct {
+//	    HTTP  httpserver.ConfigData `config:"http" description:"HTTP server."`
+//	    Redis redis.ConfigData      `config:"redis" description:"Redis client."`
+//	}
+//
+//	s, err := schema.Generate(AppConfig{})
+//	if err != nil {
+//	    panic(err)
+//	}
+//
+//	schemaBytes, err := json.MarshalIndent(s, "", "  ")
+//	sampleBytes, err := s.Sample(yaml.Encoder)
+package schema
+
+import (
+	"encoding"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/validate"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+const (
+	// Version is the JSON Schema dialect of generated schemas.
+	Version = "https://json-schema.org/draft/2020-12/schema"
+
+	// descriptionTag is the name of struct tag that defines description of the field.
+	descriptionTag = "description"
+	// validateTag is the name of struct tag that defines validation rules of the field.
+	validateTag = "validate"
+
+	// durationPattern is the pattern of durations in Go format, such as '1m30s'.
+	durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+	// hostPortPattern is the pattern of addresses in 'host:port' form, empty string is allowed as in validate package.
+	hostPortPattern = `^(.*:[0-9]+)?$`
+)
+
+// JSON Schema types.
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+//nolint:gochecknoglobals // these are actually read-only, so it's ok to use them.
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	unmarshalerType     = reflect.TypeOf((*i2s.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Schema structure is the JSON Schema of some value. Marshal it with encoding/json to get the schema document.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+
+	// HasDefault tells that the field has default value, so zero Default like 0, false or "" is the default one.
+	HasDefault bool `json:"-"`
+
+	// order holds property names in the order of structure fields.
+	order []string
+}
+
+// Generate function generates JSON Schema of provided value, which is usually a structure or a pointer to the
+// structure filled with configuration data.
+func Generate(value interface{}) (*Schema, error) {
+	valueType := reflect.TypeOf(value)
+	if valueType == nil {
+		return nil, ErrUnsupportedType
+	}
+
+	s, err := generate("", valueType, make(map[reflect.Type]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	s.Schema = Version
+
+	return s, nil
+}
+
+// PropertyNames method retrieves names of object properties in the order of structure fields.
+func (s *Schema) PropertyNames() []string {
+	return s.order
+}
+
+// generate function generates schema of provided type. Visited types are tracked to stop on recursive types.
+func generate(path string, valueType reflect.Type, visited map[reflect.Type]bool) (*Schema, error) {
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+
+	switch {
+	case reflect.PointerTo(valueType).Implements(unmarshalerType):
+		return &Schema{}, nil
+	case valueType == durationType:
+		return &Schema{Type: TypeString, Pattern: durationPattern}, nil
+	case reflect.PointerTo(valueType).Implements(textUnmarshalerType):
+		return &Schema{Type: TypeString}, nil
+	}
+
+	switch valueType.Kind() {
+	case reflect.String:
+		return &Schema{Type: TypeString}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intSchema(valueType), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return uintSchema(valueType), nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeNumber}, nil
+	case reflect.Bool:
+		return &Schema{Type: TypeBoolean}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := generate(path, valueType.Elem(), visited)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Schema{Type: TypeArray, Items: items}, nil
+	case reflect.Map:
+		if valueType.Key().Kind() != reflect.String {
+			return nil, errors.NewFmt("map key of type '%s' at '%s' is not a string", valueType.Key(), path).
+				WithCause(ErrUnsupportedType)
+		}
+
+		values, err := generate(path, valueType.Elem(), visited)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Schema{Type: TypeObject, AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if visited[valueType] {
+			return &Schema{Type: TypeObject}, nil
+		}
+
+		visited[valueType] = true
+		defer delete(visited, valueType)
+
+		s := &Schema{Type: TypeObject, Properties: make(map[string]*Schema), AdditionalProperties: false}
+
+		if err := addFields(path, s, valueType, visited); err != nil {
+			return nil, err
+		}
+
+		return s, nil
+	default:
+		return nil, errors.NewFmt("type '%s' at '%s' can't be described", valueType, path).
+			WithCause(ErrUnsupportedType)
+	}
+}
+
+// addFields function adds properties for all fields of the structure type to the object schema. Fields of embedded
+// structures are added as if they belonged to the outer structure.
+func addFields(path string, s *Schema, structType reflect.Type, visited map[reflect.Type]bool) error {
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+
+		if i2s.IsEmbedded(structField) {
+			if err := addFields(path, s, structField.Type, visited); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		name, ok := i2s.KeyName(structField)
+		if !ok {
+			continue
+		}
+
+		fieldPath := joinPath(path, name)
+
+		fieldSchema, err := generate(fieldPath, structField.Type, visited)
+		if err != nil {
+			return err
+		}
+
+		fieldSchema.Description = structField.Tag.Get(descriptionTag)
+
+		if defaultValue, ok := i2s.DefaultValue(structField); ok {
+			fieldSchema.Default, err = parseValue(fieldSchema, defaultValue)
+			fieldSchema.HasDefault = true
+			if err != nil {
+				return errors.NewFmt("invalid default value '%s' at '%s'", defaultValue, fieldPath).
+					WithCause(ErrInvalidTag)
+			}
+		}
+
+		if err = addRules(fieldSchema, structField.Tag.Get(validateTag)); err != nil {
+			return errors.NewFmt("%s at '%s'", err.Error(), fieldPath).WithCause(ErrInvalidTag)
+		}
+
+		if i2s.IsRequired(structField) {
+			s.Required = append(s.Required, name)
+		}
+
+		if _, ok := s.Properties[name]; !ok {
+			s.order = append(s.order, name)
+		}
+
+		s.Properties[name] = fieldSchema
+	}
+
+	return nil
+}
+
+// addRules function adds constraints described by validation rules to the schema.
+func addRules(s *Schema, tag string) error {
+	for _, rule := range validate.ParseRules(tag) {
+		switch rule.Name {
+		case "nonempty":
+			addNonEmpty(s)
+		case "min", "max":
+			if err := addBound(s, rule); err != nil {
+				return err
+			}
+		case "oneof":
+			for _, variant := range strings.Fields(rule.Arg) {
+				value, err := parseValue(s, variant)
+				if err != nil {
+					return errors.NewFmt("invalid variant '%s'", variant)
+				}
+
+				s.Enum = append(s.Enum, value)
+			}
+		case "regex":
+			s.Pattern = rule.Arg
+		case "hostport":
+			s.Pattern = hostPortPattern
+		case "url":
+			s.Format = "uri"
+		default:
+			return errors.NewFmt("unknown rule '%s'", rule.Name)
+		}
+	}
+
+	return nil
+}
+
+// addNonEmpty function adds constraint that value must not be empty.
+func addNonEmpty(s *Schema) {
+	one := 1
+
+	switch s.Type {
+	case TypeString:
+		s.MinLength = &one
+	case TypeArray:
+		s.MinItems = &one
+	case TypeObject:
+		s.MinProperties = &one
+	}
+}
+
+// addBound function adds minimum or maximum constraint. Bounds of durations can't be expressed in JSON Schema, so
+// they are skipped.
+func addBound(s *Schema, rule validate.Rule) error {
+	isMin := rule.Name == "min"
+
+	switch s.Type {
+	case TypeInteger, TypeNumber:
+		bound, err := strconv.ParseFloat(rule.Arg, 64)
+		if err != nil {
+			return errors.NewFmt("invalid bound '%s'", rule.Arg)
+		}
+
+		if isMin {
+			s.Minimum = &bound
+		} else {
+			s.Maximum = &bound
+		}
+	case TypeString, TypeArray, TypeObject:
+		if s.Pattern == durationPattern {
+			return nil
+		}
+
+		bound, err := strconv.Atoi(rule.Arg)
+		if err != nil {
+			return errors.NewFmt("invalid bound '%s'", rule.Arg)
+		}
+
+		switch {
+		case s.Type == TypeString && isMin:
+			s.MinLength = &bound
+		case s.Type == TypeString:
+			s.MaxLength = &bound
+		case s.Type == TypeArray && isMin:
+			s.MinItems = &bound
+		case s.Type == TypeArray:
+			s.MaxItems = &bound
+		case isMin:
+			s.MinProperties = &bound
+		default:
+			s.MaxProperties = &bound
+		}
+	}
+
+	return nil
+}
+
+// intSchema function generates schema of signed integer type with bounds of its size.
+func intSchema(valueType reflect.Type) *Schema {
+	s := &Schema{Type: TypeInteger}
+
+	if bits := valueType.Bits(); bits < 64 {
+		minimum := -math.Pow(2, float64(bits-1))
+		maximum := math.Pow(2, float64(bits-1)) - 1
+		s.Minimum = &minimum
+		s.Maximum = &maximum
+	}
+
+	return s
+}
+
+// uintSchema function generates schema of unsigned integer type with bounds of its size.
+func uintSchema(valueType reflect.Type) *Schema {
+	minimum := 0.
+	s := &Schema{Type: TypeInteger, Minimum: &minimum}
+
+	if bits := valueType.Bits(); bits < 64 {
+		maximum := math.Pow(2, float64(bits)) - 1
+		s.Maximum = &maximum
+	}
+
+	return s
+}
+
+// parseValue function converts string from the tag into value of the schema type.
+func parseValue(s *Schema, value string) (interface{}, error) {
+	switch s.Type {
+	case TypeInteger:
+		return strconv.ParseInt(value, 10, 64)
+	case TypeNumber:
+		return strconv.ParseFloat(value, 64)
+	case TypeBoolean:
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
+
+// joinPath function appends key to the key path.
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
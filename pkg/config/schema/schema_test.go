@@ -0,0 +1,227 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config"
+	jsonEncoder "github.com/lightstar/golib/pkg/config/encoder/json"
+	"github.com/lightstar/golib/pkg/config/encoder/toml"
+	"github.com/lightstar/golib/pkg/config/encoder/yaml"
+	"github.com/lightstar/golib/pkg/config/schema"
+	"github.com/lightstar/golib/pkg/daemon"
+	"github.com/lightstar/golib/pkg/grpc/grpcserver"
+	"github.com/lightstar/golib/pkg/http/httpserver"
+	"github.com/lightstar/golib/pkg/log"
+	"github.com/lightstar/golib/pkg/storage/mongo"
+	"github.com/lightstar/golib/pkg/storage/redis"
+	"github.com/lightstar/golib/pkg/storage/redis/rdidgen"
+)
+
+type appConfig struct {
+	HTTP    httpserver.ConfigData `config:"http" description:"HTTP server."`
+	GRPC    grpcserver.ConfigData `config:"grpc" description:"GRPC server."`
+	Daemon  daemon.ConfigData     `config:"daemon" description:"Background daemon."`
+	Log     log.ConfigData        `config:"log" description:"Logger."`
+	Redis   redis.ConfigData      `config:"redis" description:"Redis client."`
+	Mongo   mongo.ConfigData      `config:"mongo" description:"Mongo client."`
+	IDGen   rdidgen.ConfigData    `config:"idgen" description:"Identifier generator."`
+	Version string                `config:"version,required" description:"Application version."`
+}
+
+type commonConfig struct {
+	Mode string `default:"dev" validate:"oneof=dev staging prod" description:"Deployment mode."`
+}
+
+type sampleConfig struct {
+	commonConfig
+	Name     string         `config:"name,required" validate:"nonempty,regex=^[a-z]+$" description:"Name."`
+	Port     uint16         `default:"8080" validate:"min=1"`
+	Ratio    float64        `validate:"min=0,max=1"`
+	Timeout  time.Duration  `default:"5s" validate:"min=1s"`
+	Created  time.Time      `config:"created"`
+	URL      string         `config:"url" validate:"url"`
+	Tags     []string       `validate:"nonempty,max=3"`
+	Limits   map[string]int `validate:"nonempty"`
+	Extra    interface{}    `config:"extra"`
+	Next     *sampleConfig  `config:"next"`
+	Ignored  string         `config:"-"`
+	internal string
+	Labels   map[string]string `config:"labels"`
+}
+
+func TestGenerate(t *testing.T) {
+	s, err := schema.Generate(&sampleConfig{})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(data, &result))
+
+	properties := result["properties"].(map[string]interface{})
+
+	require.Equal(t, schema.Version, result["$schema"])
+	require.Equal(t, "object", result["type"])
+	require.Equal(t, false, result["additionalProperties"])
+	require.Equal(t, []interface{}{"name"}, result["required"])
+	require.Equal(t, []string{"mode", "name", "port", "ratio", "timeout", "created", "url", "tags", "limits", "extra",
+		"next", "labels"}, s.PropertyNames())
+
+	require.Equal(t, map[string]interface{}{
+		"description": "Deployment mode.",
+		"type":        "string",
+		"enum":        []interface{}{"dev", "staging", "prod"},
+		"default":     "dev",
+	}, properties["mode"])
+	require.Equal(t, map[string]interface{}{
+		"description": "Name.",
+		"type":        "string",
+		"pattern":     "^[a-z]+$",
+		"minLength":   1.,
+	}, properties["name"])
+	require.Equal(t, map[string]interface{}{
+		"type":    "integer",
+		"default": 8080.,
+		"minimum": 1.,
+		"maximum": 65535.,
+	}, properties["port"])
+	require.Equal(t, map[string]interface{}{"type": "number", "minimum": 0., "maximum": 1.}, properties["ratio"])
+	require.Equal(t, "5s", properties["timeout"].(map[string]interface{})["default"])
+	require.Equal(t, map[string]interface{}{"type": "string"}, properties["created"])
+	require.Equal(t, map[string]interface{}{"type": "string", "format": "uri"}, properties["url"])
+	require.Equal(t, map[string]interface{}{
+		"type":     "array",
+		"items":    map[string]interface{}{"type": "string"},
+		"minItems": 1.,
+		"maxItems": 3.,
+	}, properties["tags"])
+	require.Equal(t, map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{"type": "integer"},
+		"minProperties":        1.,
+	}, properties["limits"])
+	require.Equal(t, map[string]interface{}{}, properties["extra"])
+	require.Equal(t, map[string]interface{}{"type": "object"}, properties["next"])
+}
+
+func TestGenerateZeroDefaults(t *testing.T) {
+	s, err := schema.Generate(&struct {
+		Mode    string `default:"" validate:"oneof=dev prod"`
+		Retries int    `default:"0" validate:"max=5"`
+		Debug   bool   `default:"false"`
+		Name    string
+	}{})
+	require.NoError(t, err)
+
+	require.True(t, s.Properties["mode"].HasDefault)
+	require.False(t, s.Properties["name"].HasDefault)
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(data, &result))
+
+	properties := result["properties"].(map[string]interface{})
+
+	require.Equal(t, "", properties["mode"].(map[string]interface{})["default"])
+	require.Equal(t, 0., properties["retries"].(map[string]interface{})["default"])
+	require.Equal(t, false, properties["debug"].(map[string]interface{})["default"])
+	require.NotContains(t, properties["name"], "default")
+
+	sample, err := s.Sample(yaml.Encoder)
+	require.NoError(t, err)
+	require.Contains(t, string(sample), "mode: \"\"\n")
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := schema.Generate(nil)
+	require.ErrorIs(t, err, schema.ErrUnsupportedType)
+
+	_, err = schema.Generate(struct{ Events chan int }{})
+	require.ErrorIs(t, err, schema.ErrUnsupportedType)
+	require.ErrorContains(t, err, "'events'")
+
+	_, err = schema.Generate(struct{ Limits map[int]int }{})
+	require.ErrorIs(t, err, schema.ErrUnsupportedType)
+
+	_, err = schema.Generate(struct {
+		Port int `default:"port"`
+	}{})
+	require.ErrorIs(t, err, schema.ErrInvalidTag)
+	require.ErrorContains(t, err, "'port'")
+
+	_, err = schema.Generate(struct {
+		Port int `validate:"positive"`
+	}{})
+	require.ErrorIs(t, err, schema.ErrInvalidTag)
+
+	_, err = schema.Generate(struct {
+		Port int `validate:"max=big"`
+	}{})
+	require.ErrorIs(t, err, schema.ErrInvalidTag)
+
+	s, err := schema.Generate(appConfig{})
+	require.NoError(t, err)
+
+	_, err = s.Sample(unknownEncoder{})
+	require.ErrorIs(t, err, schema.ErrUnsupportedEncoder)
+}
+
+func TestSample(t *testing.T) {
+	s, err := schema.Generate(appConfig{})
+	require.NoError(t, err)
+
+	for _, encoder := range []config.Encoder{jsonEncoder.Encoder, yaml.Encoder, toml.Encoder} {
+		data, err := s.Sample(encoder)
+		require.NoError(t, err)
+
+		cfg, err := config.NewFromBytes(data, encoder)
+		require.NoError(t, err, string(data))
+
+		var app appConfig
+
+		require.NoError(t, cfg.Get(&app), string(data))
+		require.Equal(t, appConfig{
+			HTTP: httpserver.ConfigData{
+				Name:              "http-server",
+				Address:           "127.0.0.1:8080",
+				ReadHeaderTimeout: 2,
+				ReadTimeout:       3,
+				WriteTimeout:      3,
+			},
+			GRPC:   grpcserver.ConfigData{Name: "grpc-server", Address: "127.0.0.1:50051"},
+			Daemon: daemon.ConfigData{Name: "daemon", Delay: 1},
+			Redis:  redis.ConfigData{Address: "127.0.0.1:6379", MaxIdle: 3, IdleTimeout: 600},
+			Mongo:  mongo.ConfigData{Address: "127.0.0.1:27017", ConnectTimeout: 15, SocketTimeout: 30},
+			IDGen:  rdidgen.ConfigData{KeyPrefix: "entity"},
+		}, app)
+	}
+
+	data, err := s.Sample(yaml.Encoder)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "# HTTP server.\nhttp:\n  # Server name.\n  name: http-server\n")
+	require.Contains(t, string(data), "# Application version.\n# Required.\nversion: \"\"\n")
+
+	data, err = s.Sample(toml.Encoder)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "# Application version.\n# Required.\nversion = \"\"\n")
+	require.Contains(t, string(data), "# Redis client.\n[redis]\n# Redis server address.\naddress = \"127.0.0.1:6379\"\n")
+}
+
+type unknownEncoder struct{}
+
+func (unknownEncoder) Type() string {
+	return "unknown"
+}
+
+func (unknownEncoder) Encode([]byte, *map[string]interface{}) error {
+	return nil
+}
@@ -0,0 +1,163 @@
+package config
+
+// Subscriber is a callback function that is called every time configuration data is replaced with the new one.
+type Subscriber func(config *Config)
+
+// subscription structure holds registered subscriber, its address identifies the registration.
+type subscription struct {
+	subscriber Subscriber
+}
+
+// Subscribe method registers callback function that will be called after each configuration data update, and
+// retrieves function that cancels the registration.
+// Subscribers are called synchronously in order of registration, so they shouldn't block for long.
+func (config *Config) Subscribe(subscriber Subscriber) func() {
+	sub := &subscription{subscriber: subscriber}
+
+	config.mu.Lock()
+	config.subscribers = append(config.subscribers, sub)
+	config.mu.Unlock()
+
+	return func() {
+		config.unsubscribe(sub)
+	}
+}
+
+// Detach method stops this configuration service from following updates of the ones it is derived from, such as
+// layers of Merge function or the base of NewWithProfiles function, so it no longer stays referenced by them.
+// Its current data stays in place. Detaching configuration service that isn't derived from others does nothing.
+func (config *Config) Detach() {
+	config.mu.Lock()
+	detachFns := config.detachFns
+	config.detachFns = nil
+	config.mu.Unlock()
+
+	for _, detachFn := range detachFns {
+		detachFn()
+	}
+}
+
+// WithErrorFunc method applies function that will be called on any error occurred while configuration service follows
+// updates of the one it is derived from, such as with NewWithProfiles or NewWithInterpolation functions, and returns
+// the same configuration service. Errors of services this one is derived from are passed to it too, so it is enough to
+// apply the function to the last service in the chain:
+//
+//	cfg := config.Must(config.NewWithInterpolation(baseCfg, nil)).WithErrorFunc(logError)
+//
+// Data of configuration service stays in place when its update fails.
+func (config *Config) WithErrorFunc(errorFn func(error)) *Config {
+	config.mu.Lock()
+	defer config.mu.Unlock()
+
+	config.errorFn = errorFn
+
+	return config
+}
+
+// Update method atomically replaces configuration data with the new one and notifies all subscribers.
+// It is used by sources that are able to track configuration changes, you will rarely use it yourself.
+func (config *Config) Update(data map[string]interface{}) {
+	config.mu.Lock()
+	config.data = data
+	subscribers := make([]*subscription, len(config.subscribers))
+	copy(subscribers, config.subscribers)
+	config.mu.Unlock()
+
+	for _, sub := range subscribers {
+		sub.subscriber(config)
+	}
+}
+
+// UpdateFromBytes method parses source bytes with chosen encoder and replaces configuration data with the result.
+// If parsing fails, an error is returned and current data stays in place.
+func (config *Config) UpdateFromBytes(dataBytes []byte, encoder Encoder) error {
+	var data map[string]interface{}
+
+	err := encoder.Encode(dataBytes, &data)
+	if err != nil {
+		return err
+	}
+
+	config.Update(data)
+
+	return nil
+}
+
+// follow method makes derived configuration service follow updates of this one until it is detached, see Detach
+// method. Function 'derive' builds new data of the derived service from the data of this one. Its errors are reported
+// to the derived service, see WithErrorFunc method.
+//
+// Derived data is built and applied under the lock of the derived service from the data read inside that lock, so
+// concurrent updates of the services it follows can't be applied out of order. Subscribers of the derived service
+// must not update the services it follows synchronously.
+func (config *Config) follow(derived *Config,
+	derive func(data map[string]interface{}) (map[string]interface{}, error),
+) {
+	config.mu.Lock()
+	config.derived = append(config.derived, derived)
+	config.mu.Unlock()
+
+	unsubscribe := config.Subscribe(func(config *Config) {
+		derived.updateMu.Lock()
+		defer derived.updateMu.Unlock()
+
+		data, err := derive(config.GetRaw())
+		if err != nil {
+			derived.reportError(err)
+			return
+		}
+
+		derived.Update(data)
+	})
+
+	derived.mu.Lock()
+	derived.detachFns = append(derived.detachFns, func() {
+		unsubscribe()
+		config.removeDerived(derived)
+	})
+	derived.mu.Unlock()
+}
+
+// unsubscribe method cancels registration of the subscriber.
+func (config *Config) unsubscribe(sub *subscription) {
+	config.mu.Lock()
+	defer config.mu.Unlock()
+
+	for i, registered := range config.subscribers {
+		if registered == sub {
+			config.subscribers = append(config.subscribers[:i:i], config.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// removeDerived method forgets derived configuration service, so its errors are no longer reported.
+func (config *Config) removeDerived(derived *Config) {
+	config.mu.Lock()
+	defer config.mu.Unlock()
+
+	for i, cfg := range config.derived {
+		if cfg == derived {
+			config.derived = append(config.derived[:i:i], config.derived[i+1:]...)
+			return
+		}
+	}
+}
+
+// reportError method passes error to the error function of this configuration service and to all services derived
+// from it.
+func (config *Config) reportError(err error) {
+	config.mu.RLock()
+	errorFn := config.errorFn
+	derived := make([]*Config, len(config.derived))
+	copy(derived, config.derived)
+	config.mu.RUnlock()
+
+	if errorFn != nil {
+		errorFn(err)
+	}
+
+	for _, cfg := range derived {
+		cfg.reportError(err)
+	}
+}
@@ -0,0 +1,82 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder/json"
+)
+
+func TestUpdate(t *testing.T) {
+	cfg := config.NewFromRaw(map[string]interface{}{"name": "George"})
+
+	var calls []string
+
+	cfg.Subscribe(func(cfg *config.Config) {
+		name, err := cfg.GetRawByKey("name")
+		require.NoError(t, err)
+
+		calls = append(calls, "first:"+name.(string))
+	})
+
+	cfg.Subscribe(func(cfg *config.Config) {
+		calls = append(calls, "second")
+	})
+
+	unsubscribe := cfg.Subscribe(func(cfg *config.Config) {
+		calls = append(calls, "third")
+	})
+	unsubscribe()
+	unsubscribe()
+
+	require.NoError(t, cfg.UpdateFromBytes(configtest.SampleConfigDataJSON, json.Encoder))
+	configtest.TestSampleConfig(t, cfg, configtest.ExpectedSampleRawDataJSON)
+	require.Equal(t, []string{"first:Peter", "second"}, calls)
+
+	require.ErrorContains(t, cfg.UpdateFromBytes(configtest.SampleConfigDataWrongJSON, json.Encoder), "json error")
+	configtest.TestSampleConfig(t, cfg, configtest.ExpectedSampleRawDataJSON)
+	require.Len(t, calls, 2)
+}
+
+func TestUpdateErrors(t *testing.T) {
+	base := config.NewFromRaw(map[string]interface{}{
+		"name":     "Peter",
+		"profiles": map[string]interface{}{"dev": map[string]interface{}{"debug": true}},
+	})
+
+	profiled, err := config.NewWithProfiles(base, "dev")
+	require.NoError(t, err)
+
+	interpolated, err := config.NewWithInterpolation(profiled, nil)
+	require.NoError(t, err)
+
+	var profiledErrs, interpolatedErrs []error
+
+	profiled.WithErrorFunc(func(err error) {
+		profiledErrs = append(profiledErrs, err)
+	})
+
+	interpolated.WithErrorFunc(func(err error) {
+		interpolatedErrs = append(interpolatedErrs, err)
+	})
+
+	base.Update(map[string]interface{}{"name": "George"})
+
+	require.Len(t, profiledErrs, 1)
+	require.ErrorIs(t, profiledErrs[0], config.ErrUnknownProfile)
+	require.Equal(t, profiledErrs, interpolatedErrs)
+	require.Equal(t, "Peter", config.MustValue(interpolated, "name", ""))
+
+	base.Update(map[string]interface{}{
+		"name":     "${key:missing}",
+		"profiles": map[string]interface{}{"dev": map[string]interface{}{}},
+	})
+
+	require.Len(t, profiledErrs, 1)
+	require.Len(t, interpolatedErrs, 2)
+	require.ErrorIs(t, interpolatedErrs[1], config.ErrUnresolvedReference)
+	require.Equal(t, "Peter", config.MustValue(interpolated, "name", ""))
+}
@@ -0,0 +1,40 @@
+package validate
+
+import (
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+var (
+	// ErrInvalidValue error is returned when value doesn't satisfy some validation rule or its Validate method fails.
+	ErrInvalidValue = errors.New("invalid value")
+
+	// ErrInvalidRule error is returned when validation rule in tag is unknown or has malformed argument.
+	ErrInvalidRule = errors.New("invalid validation rule")
+)
+
+// FieldError structure describes validation error of the data under some key path.
+// Its cause is one of the errors defined above, so use errors.Is to check the kind of error.
+type FieldError struct {
+	*errors.Err
+	// Path is the full key path of the data, like 'http.server.address'.
+	Path string
+	// Rule is the name of failed rule, or 'validate' if Validate method failed.
+	Rule string
+}
+
+// newFieldError function creates new field error.
+func newFieldError(cause error, path string, rule string, detail string) *FieldError {
+	msg := cause.Error()
+
+	if path != "" {
+		msg += " at '" + path + "'"
+	}
+
+	msg += " (" + detail + ")"
+
+	return &FieldError{
+		Err:  errors.New(msg).WithCause(cause),
+		Path: path,
+		Rule: rule,
+	}
+}
@@ -0,0 +1,275 @@
+package validate
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// nonEmptyRule is the name of the only rule that checks empty strings and nil pointers.
+	nonEmptyRule = "nonempty"
+	// regexRule is the name of the rule that consumes the rest of the tag as its argument.
+	regexRule = "regex"
+	// maxPort is the maximum valid network port.
+	maxPort = 65535
+)
+
+// Rule structure describes one rule parsed from the tag.
+type Rule struct {
+	Name string
+	Arg  string
+}
+
+// ruleError structure describes failed rule before key path is known.
+type ruleError struct {
+	cause  error
+	detail string
+}
+
+// ruleFunc is a function that checks value against the rule with provided argument.
+type ruleFunc func(value reflect.Value, arg string) *ruleError
+
+//nolint:gochecknoglobals // these are actually read-only, so it's ok to use them.
+var (
+	ruleFuncs = map[string]ruleFunc{
+		nonEmptyRule: checkNonEmpty,
+		"min":        checkMin,
+		"max":        checkMax,
+		"oneof":      checkOneOf,
+		regexRule:    checkRegex,
+		"hostport":   checkHostPort,
+		"url":        checkURL,
+	}
+
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	regexCache sync.Map
+)
+
+// withPath method converts rule error into field error.
+func (err *ruleError) withPath(path string, rule string) *FieldError {
+	return newFieldError(err.cause, path, rule, err.detail)
+}
+
+// ParseRules function parses comma-separated rules from the 'validate' tag. It is exported for packages that need to
+// describe rules rather than check them, such as schema generators.
+func ParseRules(tag string) []Rule {
+	var rules []Rule
+
+	for tag != "" {
+		var current string
+
+		if strings.HasPrefix(tag, regexRule+"=") {
+			current, tag = tag, ""
+		} else {
+			current, tag, _ = strings.Cut(tag, ",")
+		}
+
+		current = strings.TrimSpace(current)
+		if current == "" {
+			continue
+		}
+
+		name, arg, _ := strings.Cut(current, "=")
+		rules = append(rules, Rule{Name: name, Arg: arg})
+	}
+
+	return rules
+}
+
+func checkNonEmpty(value reflect.Value, _ string) *ruleError {
+	if value.IsZero() || (isSized(value) && value.Len() == 0) {
+		return &ruleError{cause: ErrInvalidValue, detail: "must not be empty"}
+	}
+
+	return nil
+}
+
+func checkMin(value reflect.Value, arg string) *ruleError {
+	cmp, err := compare(value, arg)
+	if err != nil {
+		return err
+	}
+
+	if cmp < 0 {
+		return &ruleError{cause: ErrInvalidValue, detail: describeBound(value, "less than", arg)}
+	}
+
+	return nil
+}
+
+func checkMax(value reflect.Value, arg string) *ruleError {
+	cmp, err := compare(value, arg)
+	if err != nil {
+		return err
+	}
+
+	if cmp > 0 {
+		return &ruleError{cause: ErrInvalidValue, detail: describeBound(value, "greater than", arg)}
+	}
+
+	return nil
+}
+
+func checkOneOf(value reflect.Value, arg string) *ruleError {
+	if !value.CanInterface() {
+		return nil
+	}
+
+	str := fmt.Sprint(value.Interface())
+
+	for _, variant := range strings.Fields(arg) {
+		if str == variant {
+			return nil
+		}
+	}
+
+	return &ruleError{cause: ErrInvalidValue, detail: fmt.Sprintf("'%s' must be one of [%s]", str, arg)}
+}
+
+func checkRegex(value reflect.Value, arg string) *ruleError {
+	if value.Kind() != reflect.String {
+		return invalidRuleError(value, "regex")
+	}
+
+	var re *regexp.Regexp
+
+	if cached, ok := regexCache.Load(arg); ok {
+		re, _ = cached.(*regexp.Regexp)
+	} else {
+		var err error
+
+		re, err = regexp.Compile(arg)
+		if err != nil {
+			return &ruleError{cause: ErrInvalidRule, detail: fmt.Sprintf("malformed regex '%s'", arg)}
+		}
+
+		regexCache.Store(arg, re)
+	}
+
+	if !re.MatchString(value.String()) {
+		return &ruleError{cause: ErrInvalidValue, detail: fmt.Sprintf("'%s' must match '%s'", value.String(), arg)}
+	}
+
+	return nil
+}
+
+func checkHostPort(value reflect.Value, _ string) *ruleError {
+	if value.Kind() != reflect.String {
+		return invalidRuleError(value, "hostport")
+	}
+
+	_, port, err := net.SplitHostPort(value.String())
+	if err == nil {
+		var portNum uint64
+
+		portNum, err = strconv.ParseUint(port, 10, 16)
+		if err == nil && portNum > maxPort {
+			err = strconv.ErrRange
+		}
+	}
+
+	if err != nil {
+		return &ruleError{cause: ErrInvalidValue, detail: fmt.Sprintf("'%s' must be in 'host:port' form", value.String())}
+	}
+
+	return nil
+}
+
+func checkURL(value reflect.Value, _ string) *ruleError {
+	if value.Kind() != reflect.String {
+		return invalidRuleError(value, "url")
+	}
+
+	u, err := url.Parse(value.String())
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return &ruleError{cause: ErrInvalidValue, detail: fmt.Sprintf("'%s' must be an absolute url", value.String())}
+	}
+
+	return nil
+}
+
+// compare function compares value (or its length) with the rule argument. It returns -1, 0 or 1 like strings.Compare.
+func compare(value reflect.Value, arg string) (int, *ruleError) {
+	var cmp int
+	var err error
+
+	switch {
+	case value.Type() == durationType:
+		var bound time.Duration
+
+		bound, err = time.ParseDuration(arg)
+		cmp = compareValues(value.Int(), int64(bound))
+	case isSized(value):
+		var bound int64
+
+		bound, err = strconv.ParseInt(arg, 10, 64)
+		cmp = compareValues(int64(value.Len()), bound)
+	case value.CanInt():
+		var bound int64
+
+		bound, err = strconv.ParseInt(arg, 10, 64)
+		cmp = compareValues(value.Int(), bound)
+	case value.CanUint():
+		var bound uint64
+
+		bound, err = strconv.ParseUint(arg, 10, 64)
+		cmp = compareValues(value.Uint(), bound)
+	case value.CanFloat():
+		var bound float64
+
+		bound, err = strconv.ParseFloat(arg, 64)
+		cmp = compareValues(value.Float(), bound)
+	default:
+		return 0, invalidRuleError(value, "min/max")
+	}
+
+	if err != nil {
+		return 0, &ruleError{cause: ErrInvalidRule, detail: fmt.Sprintf("malformed bound '%s'", arg)}
+	}
+
+	return cmp, nil
+}
+
+// describeBound function creates human-readable description of violated bound.
+func describeBound(value reflect.Value, relation string, arg string) string {
+	if isSized(value) {
+		return fmt.Sprintf("length %d is %s %s", value.Len(), relation, arg)
+	}
+
+	return fmt.Sprintf("%v is %s %s", value.Interface(), relation, arg)
+}
+
+// invalidRuleError function creates error about rule that can't be applied to the value of such type.
+func invalidRuleError(value reflect.Value, rule string) *ruleError {
+	return &ruleError{cause: ErrInvalidRule, detail: fmt.Sprintf("rule '%s' can't be applied to %s", rule, value.Type())}
+}
+
+// isSized function checks if value has length.
+func isSized(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
+
+// compareValues function compares two numbers returning -1, 0 or 1.
+func compareValues[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,179 @@
+// Package validate provides declarative validation of structures filled with configuration data.
+// It is used by config package automatically after each conversion, so you will rarely use it yourself.
+//
+// Rules are defined in 'validate' tag and separated by commas:
+//
+//	type Config struct {
+//	    Address string        `validate:"nonempty,hostport"`
+//	    Timeout time.Duration `validate:"min=1s,max=1m"`
+//	    Retries int           `validate:"min=0,max=10"`
+//	    Mode    string        `validate:"oneof=dev staging prod"`
+//	    Name    string        `validate:"regex=^[a-z][a-z0-9-]*$"`
+//	}
+//
+// Supported rules:
+//
+//	nonempty - value must not be zero, strings, slices and maps must not be empty.
+//	min=N, max=N - bounds of numbers and durations, or bounds of length of strings, slices and maps.
+//	oneof=A B C - value must be one of space-separated variants.
+//	regex=R - string must match regular expression. It consumes the rest of the tag, so it must be the last rule.
+//	hostport - string must be in 'host:port' form, host may be empty.
+//	url - string must be an absolute URL.
+//
+// Rules except 'nonempty' skip empty strings, so combine them with 'nonempty' if the value is mandatory.
+// Rules of pointer fields are applied to the values they point to, while nil pointers are checked only by 'nonempty'.
+//
+// Structures can also implement Validator interface for checks that can't be expressed with tags.
+// All errors are collected and returned at once as i2s.MultiError holding FieldError values with full key paths.
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/lightstar/golib/pkg/config/i2s"
+)
+
+// validateTag is the name of struct tag that defines validation rules of the field.
+const validateTag = "validate"
+
+// Validator interface can be implemented by structures that need custom validation.
+type Validator interface {
+	Validate() error
+}
+
+// Check function validates provided value, which is usually a pointer to the structure filled with configuration
+// data lying under provided key path. Path is used only in error messages.
+func Check(path string, value interface{}) error {
+	return CheckWithMapping(path, value, i2s.FirstLetterMapping)
+}
+
+// CheckWithMapping function works like Check function, but key paths of untagged fields in error messages are built
+// with provided name mapping strategy, so they match the keys of configuration data, see i2s.WithNameMapping option.
+func CheckWithMapping(path string, value interface{}, mapping i2s.NameMapping) error {
+	errs := check(path, reflect.ValueOf(value), mapping, nil)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &i2s.MultiError{Errors: errs}
+}
+
+// check function recursively validates value and all values inside it.
+func check(path string, value reflect.Value, mapping i2s.NameMapping, errs []error) []error {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			errs = check(path, value.Elem(), mapping, errs)
+		}
+	case reflect.Struct:
+		errs = checkStruct(path, value, mapping, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			errs = check(joinPath(path, strconv.Itoa(i)), value.Index(i), mapping, errs)
+		}
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		for _, key := range keys {
+			errs = check(joinPath(path, key.String()), value.MapIndex(key), mapping, errs)
+		}
+	default:
+	}
+
+	return errs
+}
+
+// checkStruct function validates all fields of the structure and calls its Validate method if there is one.
+func checkStruct(path string, value reflect.Value, mapping i2s.NameMapping, errs []error) []error {
+	errs = checkFields(path, value, mapping, errs)
+
+	var validator Validator
+
+	if value.CanAddr() && value.Addr().CanInterface() {
+		validator, _ = value.Addr().Interface().(Validator)
+	} else if value.CanInterface() {
+		validator, _ = value.Interface().(Validator)
+	}
+
+	if validator != nil {
+		if err := validator.Validate(); err != nil {
+			errs = append(errs, newFieldError(ErrInvalidValue, path, "validate", err.Error()))
+		}
+	}
+
+	return errs
+}
+
+// checkFields function validates all fields of the structure according to their tags. Fields of embedded structures
+// are validated as if they belonged to the outer structure.
+func checkFields(path string, value reflect.Value, mapping i2s.NameMapping, errs []error) []error {
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		fieldValue := value.Field(i)
+
+		if structField.Anonymous && structField.Type.Kind() == reflect.Struct && structField.Tag.Get("config") == "" {
+			errs = checkFields(path, fieldValue, mapping, errs)
+			continue
+		}
+
+		name, ok := i2s.KeyNameWithMapping(structField, mapping)
+		if !ok {
+			continue
+		}
+
+		fieldPath := joinPath(path, name)
+
+		if tag := structField.Tag.Get(validateTag); tag != "" {
+			errs = checkRules(fieldPath, fieldValue, tag, errs)
+		}
+
+		errs = check(fieldPath, fieldValue, mapping, errs)
+	}
+
+	return errs
+}
+
+// checkRules function validates value against all rules defined in tag. Pointers are dereferenced first, and empty
+// strings and nil pointers are checked only by 'nonempty' rule.
+func checkRules(path string, value reflect.Value, tag string, errs []error) []error {
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	isAbsent := (value.Kind() == reflect.String && value.Len() == 0) || (value.Kind() == reflect.Ptr && value.IsNil())
+
+	for _, r := range ParseRules(tag) {
+		ruleFunc, ok := ruleFuncs[r.Name]
+		if !ok {
+			errs = append(errs, newFieldError(ErrInvalidRule, path, r.Name, fmt.Sprintf("unknown rule '%s'", r.Name)))
+			continue
+		}
+
+		if isAbsent && r.Name != nonEmptyRule {
+			continue
+		}
+
+		if err := ruleFunc(value, r.Arg); err != nil {
+			errs = append(errs, err.withPath(path, r.Name))
+		}
+	}
+
+	return errs
+}
+
+// joinPath function appends key to the key path.
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,215 @@
+package validate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/validate"
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+type Server struct {
+	Address string        `validate:"nonempty,hostport"`
+	Timeout time.Duration `validate:"min=1s,max=1m"`
+	Retries int           `validate:"min=0,max=10"`
+	Ratio   float64       `validate:"max=1"`
+	Workers uint          `validate:"min=1"`
+	Mode    string        `validate:"oneof=dev staging prod"`
+	Name    string        `config:"server_name" validate:"regex=^[a-z]{2,10}$"`
+	URL     string        `config:"url" validate:"url"`
+	Tags    []string      `validate:"min=1"`
+}
+
+type Service struct {
+	Servers  []Server
+	Backends map[string]*Server
+	Primary  Server
+	Internal string `config:"-" validate:"nonempty"`
+}
+
+func (s *Service) Validate() error {
+	if len(s.Servers) > 0 && s.Servers[0].Address == s.Primary.Address {
+		return errors.New("primary server is duplicated")
+	}
+
+	return nil
+}
+
+func validServer() Server {
+	return Server{
+		Address: ":8080",
+		Timeout: time.Second,
+		Retries: 3,
+		Ratio:   0.5,
+		Workers: 2,
+		Mode:    "dev",
+		Name:    "main",
+		URL:     "https://example.com/path",
+		Tags:    []string{"a"},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	service := Service{
+		Servers:  []Server{validServer()},
+		Backends: map[string]*Server{"backend": nil},
+		Primary:  validServer(),
+	}
+
+	service.Primary.Address = "127.0.0.1:9090"
+	service.Primary.URL = ""
+	service.Primary.Name = ""
+
+	require.NoError(t, validate.Check("", &service))
+}
+
+func TestCheckErrors(t *testing.T) {
+	broken := Server{
+		Address: "localhost",
+		Timeout: time.Hour,
+		Retries: -1,
+		Ratio:   1.5,
+		Workers: 0,
+		Mode:    "test",
+		Name:    "Main",
+		URL:     "/relative",
+		Tags:    nil,
+	}
+
+	service := Service{
+		Servers:  []Server{validServer()},
+		Backends: map[string]*Server{"backend": &broken},
+		Primary:  validServer(),
+	}
+
+	err := validate.Check("app", &service)
+
+	var multiErr *i2s.MultiError
+
+	require.ErrorAs(t, err, &multiErr)
+	require.ErrorIs(t, err, validate.ErrInvalidValue)
+
+	paths := make([]string, 0, len(multiErr.Errors))
+
+	for _, e := range multiErr.Errors {
+		var fieldErr *validate.FieldError
+
+		require.ErrorAs(t, e, &fieldErr)
+		paths = append(paths, fieldErr.Path+":"+fieldErr.Rule)
+	}
+
+	require.Equal(t, []string{
+		"app.backends.backend.address:hostport",
+		"app.backends.backend.timeout:max",
+		"app.backends.backend.retries:min",
+		"app.backends.backend.ratio:max",
+		"app.backends.backend.workers:min",
+		"app.backends.backend.mode:oneof",
+		"app.backends.backend.server_name:regex",
+		"app.backends.backend.url:url",
+		"app.backends.backend.tags:min",
+		"app:validate",
+	}, paths)
+
+	require.ErrorContains(t, err, "invalid value at 'app.backends.backend.address' ("+
+		"'localhost' must be in 'host:port' form)")
+	require.ErrorContains(t, err, "invalid value at 'app' (primary server is duplicated)")
+
+	err = validate.Check("", &Server{})
+	require.ErrorContains(t, err, "invalid value at 'address' (must not be empty)")
+}
+
+func TestCheckInvalidRules(t *testing.T) {
+	err := validate.Check("", &struct {
+		Name string `validate:"unknown"`
+	}{})
+	require.ErrorIs(t, err, validate.ErrInvalidRule)
+
+	err = validate.Check("", &struct {
+		Port int `validate:"min=low"`
+	}{})
+	require.ErrorIs(t, err, validate.ErrInvalidRule)
+
+	err = validate.Check("", &struct {
+		Enabled bool `validate:"max=1"`
+	}{})
+	require.ErrorIs(t, err, validate.ErrInvalidRule)
+
+	err = validate.Check("", &struct {
+		Port int `validate:"hostport"`
+	}{})
+	require.ErrorIs(t, err, validate.ErrInvalidRule)
+
+	err = validate.Check("", &struct {
+		Name string `validate:"regex=[a-z"`
+	}{Name: "name"})
+	require.ErrorIs(t, err, validate.ErrInvalidRule)
+}
+
+func TestCheckEmptyStrings(t *testing.T) {
+	type Options struct {
+		Name  string `validate:"min=3,max=10"`
+		Mode  string `validate:"oneof=dev prod"`
+		Label string `validate:"nonempty,min=3"`
+	}
+
+	err := validate.Check("", &Options{})
+	require.ErrorContains(t, err, "invalid value at 'label' (must not be empty)")
+	require.NotContains(t, err.Error(), "'name'")
+	require.NotContains(t, err.Error(), "'mode'")
+	require.NotContains(t, err.Error(), "length")
+
+	err = validate.Check("", &Options{Name: "ab", Mode: "test", Label: "abc"})
+	require.ErrorContains(t, err, "invalid value at 'name' (length 2 is less than 3)")
+	require.ErrorContains(t, err, "invalid value at 'mode' ('test' must be one of [dev prod])")
+}
+
+func TestCheckPointers(t *testing.T) {
+	type Options struct {
+		Retries *int           `validate:"min=0,max=10"`
+		Address *string        `validate:"hostport"`
+		Timeout *time.Duration `validate:"min=1s"`
+		Name    *string        `validate:"nonempty"`
+	}
+
+	retries := 3
+	address := ":8080"
+	timeout := time.Second
+	name := "main"
+
+	require.NoError(t, validate.Check("", &Options{Retries: &retries, Address: &address, Timeout: &timeout,
+		Name: &name}))
+
+	err := validate.Check("", &Options{})
+	require.EqualError(t, err, "invalid value at 'name' (must not be empty)")
+
+	retries = 11
+	address = "localhost"
+	timeout = time.Millisecond
+
+	err = validate.Check("", &Options{Retries: &retries, Address: &address, Timeout: &timeout, Name: &name})
+	require.ErrorContains(t, err, "invalid value at 'retries' (11 is greater than 10)")
+	require.ErrorContains(t, err, "invalid value at 'address' ('localhost' must be in 'host:port' form)")
+	require.ErrorContains(t, err, "invalid value at 'timeout' (1ms is less than 1s)")
+	require.NotErrorIs(t, err, validate.ErrInvalidRule)
+}
+
+func TestCheckWithMapping(t *testing.T) {
+	type Options struct {
+		MaxRetries int    `validate:"max=10"`
+		ServerName string `config:"name" validate:"nonempty"`
+	}
+
+	err := validate.CheckWithMapping("app", &Options{MaxRetries: 11}, i2s.SnakeCaseMapping)
+	require.ErrorContains(t, err, "invalid value at 'app.max_retries'")
+	require.ErrorContains(t, err, "invalid value at 'app.name'")
+
+	err = validate.CheckWithMapping("app", &Options{MaxRetries: 11}, i2s.KebabCaseMapping)
+	require.ErrorContains(t, err, "invalid value at 'app.max-retries'")
+
+	err = validate.Check("app", &Options{MaxRetries: 11})
+	require.ErrorContains(t, err, "invalid value at 'app.maxRetries'")
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/lightstar/golib/pkg/errors"
+)
+
+// ValueError structure describes an error occurred while converting configuration value under some key into the
+// requested type. It matches ErrInvalidValue with errors.Is, and its cause is the original conversion or validation
+// error, so errors.Is and errors.As can be used to inspect i2s and validate errors as well.
+type ValueError struct {
+	*errors.Err
+	// Key is the normalized key of the value, like 'redis.maxIdle'.
+	Key string
+	// Type is the Go type the value was converted into.
+	Type string
+}
+
+// Is method reports whether error matches the target, which is true for ErrInvalidValue.
+func (err *ValueError) Is(target error) bool {
+	return target == ErrInvalidValue
+}
+
+// Lookup function retrieves configuration value under the key converted into type T using the same rules as GetByKey
+// method. It returns ErrNoSuchKey if there is no such key, errors matching ErrInvalidKey if the key is malformed, and
+// ValueError if the value can't be converted or validated.
+func Lookup[T any](config *Config, key string) (T, error) {
+	var value T
+
+	err := config.GetByKey(key, &value)
+	if err == nil {
+		return value, nil
+	}
+
+	var zero T
+
+	if errors.Is(err, ErrNoSuchKey) || errors.Is(err, ErrInvalidKey) {
+		return zero, err
+	}
+
+	typeName := reflect.TypeOf(&value).Elem().String()
+
+	return zero, &ValueError{
+		Err: errors.NewFmt("invalid configuration value at '%s' for type %s (%s)", keyPath(key), typeName,
+			err.Error()).WithCause(err),
+		Key:  keyPath(key),
+		Type: typeName,
+	}
+}
+
+// Value function retrieves configuration value under the key converted into type T, or provided default value if
+// there is no such key. Errors are the same as for Lookup function except that ErrNoSuchKey is never returned. Default
+// value is returned along with any error.
+//
+//	maxIdle, err := config.Value(cfg, "redis.maxIdle", 10)
+func Value[T any](config *Config, key string, def T) (T, error) {
+	value, err := Lookup[T](config, key)
+	if err != nil {
+		if errors.Is(err, ErrNoSuchKey) {
+			return def, nil
+		}
+
+		return def, err
+	}
+
+	return value, nil
+}
+
+// MustValue function is the same as Value function, but it panics on error.
+func MustValue[T any](config *Config, key string, def T) T {
+	value, err := Value(config, key, def)
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+// DurationValue function retrieves configuration value under the key as duration parsed from strings like '1500ms',
+// or provided default value if there is no such key.
+func DurationValue(config *Config, key string, def time.Duration) (time.Duration, error) {
+	return Value(config, key, def)
+}
+
+// StringsValue function retrieves configuration value under the key as a list of strings, or provided default value
+// if there is no such key.
+func StringsValue(config *Config, key string, def []string) ([]string, error) {
+	return Value(config, key, def)
+}
+
+// MapValue function retrieves configuration value under the key as a map with string keys and values of type T, or
+// provided default value if there is no such key.
+func MapValue[T any](config *Config, key string, def map[string]T) (map[string]T, error) {
+	return Value(config, key, def)
+}
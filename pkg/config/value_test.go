@@ -0,0 +1,109 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/encoder/json"
+	"github.com/lightstar/golib/pkg/config/i2s"
+)
+
+func newValueConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	cfg, err := config.NewFromBytes([]byte(`{
+		"name": "app",
+		"redis": {"maxIdle": 5, "timeout": "1500ms", "weight": 0.5},
+		"tags": ["a", "b"],
+		"labels": {"env": "dev", "team": "core"},
+		"limits": {"cpu": 2, "memory": 512}
+	}`), json.Encoder)
+	require.NoError(t, err)
+
+	return cfg
+}
+
+func TestValue(t *testing.T) {
+	cfg := newValueConfig(t)
+
+	maxIdle, err := config.Value(cfg, "redis.maxIdle", 10)
+	require.NoError(t, err)
+	require.Equal(t, 5, maxIdle)
+
+	maxActive, err := config.Value(cfg, "redis.maxActive", 10)
+	require.NoError(t, err)
+	require.Equal(t, 10, maxActive)
+
+	name, err := config.Value(cfg, "name", "default")
+	require.NoError(t, err)
+	require.Equal(t, "app", name)
+
+	weight, err := config.Value[float32](cfg, "redis.weight", 1)
+	require.NoError(t, err)
+	require.Equal(t, float32(0.5), weight)
+
+	timeout, err := config.DurationValue(cfg, "redis.timeout", time.Second)
+	require.NoError(t, err)
+	require.Equal(t, 1500*time.Millisecond, timeout)
+
+	timeout, err = config.DurationValue(cfg, "redis.idleTimeout", time.Second)
+	require.NoError(t, err)
+	require.Equal(t, time.Second, timeout)
+
+	tags, err := config.StringsValue(cfg, "tags", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, tags)
+
+	labels, err := config.MapValue(cfg, "labels", map[string]string{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"env": "dev", "team": "core"}, labels)
+
+	limits, err := config.MapValue[int](cfg, "limits", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"cpu": 2, "memory": 512}, limits)
+
+	require.Equal(t, 5, config.MustValue(cfg, "redis.maxIdle", 10))
+	require.Equal(t, 7, config.MustValue(cfg, "unknown", 7))
+}
+
+func TestValueErrors(t *testing.T) {
+	cfg := newValueConfig(t)
+
+	_, err := config.Lookup[int](cfg, "redis.maxActive")
+	require.Same(t, config.ErrNoSuchKey, err)
+
+	maxIdle, err := config.Value(cfg, "redis.timeout", 10)
+	require.Equal(t, 10, maxIdle)
+	require.ErrorIs(t, err, config.ErrInvalidValue)
+	require.ErrorIs(t, err, i2s.ErrMismatchedTypes)
+	require.NotErrorIs(t, err, config.ErrNoSuchKey)
+
+	var valueErr *config.ValueError
+
+	require.ErrorAs(t, err, &valueErr)
+	require.Equal(t, "redis.timeout", valueErr.Key)
+	require.Equal(t, "int", valueErr.Type)
+
+	var fieldErr *i2s.FieldError
+
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, "redis.timeout", fieldErr.Path)
+
+	_, err = config.DurationValue(cfg, "name", time.Second)
+	require.ErrorIs(t, err, config.ErrInvalidValue)
+	require.ErrorIs(t, err, i2s.ErrInvalidValue)
+
+	_, err = config.Value[uint8](cfg, "limits.memory", 0)
+	require.ErrorIs(t, err, i2s.ErrOutOfRange)
+
+	_, err = config.Value(cfg, `redis."maxIdle`, 0)
+	require.ErrorIs(t, err, config.ErrInvalidKey)
+	require.NotErrorIs(t, err, config.ErrInvalidValue)
+
+	require.Panics(t, func() {
+		_ = config.MustValue(cfg, "tags", 0)
+	})
+}
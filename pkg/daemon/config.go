@@ -3,6 +3,8 @@ package daemon
 import (
 	"time"
 
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/validate"
 	"github.com/lightstar/golib/pkg/log"
 )
 
@@ -30,9 +32,18 @@ type ConfigService interface {
 // Option function that is fed to New and MustNew. Obtain them using 'With' functions down below.
 type Option func(*Config) error
 
-// WithConfig option retrieves configuration from provided configuration service.
+// ConfigData structure describes configuration data of the daemon that WithConfig option retrieves.
+// It can be used to generate schema or sample of configuration, see config/schema package.
+type ConfigData struct {
+	Name  string `default:"daemon" validate:"nonempty" description:"Daemon name."`
+	Delay int    `default:"1" validate:"min=0" description:"Process delay in milliseconds."`
+}
+
+// WithConfig option retrieves configuration from provided configuration service and validates it according to
+// 'validate' tags of ConfigData, whatever configuration service is used.
 //
-// Example JSON configuration with all possible fields (if some are not present, defaults will be used):
+// Example JSON configuration with all possible fields (if some are not present, defaults from 'default' tags
+// of ConfigData will be used):
 //
 //	{
 //	    "name": "daemon-name",
@@ -40,16 +51,20 @@ type Option func(*Config) error
 //	}
 func WithConfig(service ConfigService, key string) Option {
 	return func(cfg *Config) error {
-		data := struct {
-			Name  string
-			Delay int
-		}{
-			Name:  DefName,
-			Delay: DefDelay,
-		}
+		var data ConfigData
 
 		err := service.GetByKey(key, &data)
-		if err != nil && !service.IsNoSuchKeyError(err) {
+		if err != nil {
+			if !service.IsNoSuchKeyError(err) {
+				return err
+			}
+
+			if err = i2s.Instance().Convert(map[string]interface{}{}, &data); err != nil {
+				return err
+			}
+		}
+
+		if err = validate.Check(key, &data); err != nil {
 			return err
 		}
 
@@ -60,7 +75,7 @@ func WithConfig(service ConfigService, key string) Option {
 	}
 }
 
-// WithName option applies provided daemon name. Default: "daemon".
+// WithName option applies provided daemon name. Default: 'default' tag of ConfigData.Name field.
 func WithName(name string) Option {
 	return func(cfg *Config) error {
 		cfg.name = name
@@ -68,7 +83,7 @@ func WithName(name string) Option {
 	}
 }
 
-// WithDelay option applies provided process delay in milliseconds. Default: 1.
+// WithDelay option applies provided process delay in milliseconds. Default: 'default' tag of ConfigData.Delay field.
 func WithDelay(delay int) Option {
 	return func(cfg *Config) error {
 		cfg.delay = time.Duration(delay) * time.Millisecond
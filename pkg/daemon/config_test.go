@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
 	"github.com/lightstar/golib/pkg/daemon"
 )
 
@@ -76,3 +77,13 @@ func TestConfigServiceError(t *testing.T) {
 		_ = daemon.MustNew(daemon.WithConfig(configService, "key"))
 	})
 }
+
+func TestConfigDataDefaults(t *testing.T) {
+	var data daemon.ConfigData
+
+	require.NoError(t, config.NewFromRaw(map[string]interface{}{}).Get(&data))
+	require.Equal(t, daemon.ConfigData{
+		Name:  daemon.DefName,
+		Delay: daemon.DefDelay,
+	}, data)
+}
@@ -3,6 +3,8 @@ package grpcserver
 import (
 	"google.golang.org/grpc"
 
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/validate"
 	"github.com/lightstar/golib/pkg/log"
 )
 
@@ -33,9 +35,18 @@ type Option func(*Config) error
 // RegisterFn callback function that is called before listening in order to register any user-defined grpc service.
 type RegisterFn func(*grpc.Server)
 
-// WithConfig option retrieves configuration from provided configuration service.
+// ConfigData structure describes configuration data of the server that WithConfig option retrieves.
+// It can be used to generate schema or sample of configuration, see config/schema package.
+type ConfigData struct {
+	Name    string `default:"grpc-server" validate:"nonempty" description:"Server name."`
+	Address string `default:"127.0.0.1:50051" validate:"nonempty,hostport" description:"Address to listen to."`
+}
+
+// WithConfig option retrieves configuration from provided configuration service and validates it according to
+// 'validate' tags of ConfigData, whatever configuration service is used.
 //
-// Example JSON configuration with all possible fields (if some are not present, defaults will be used):
+// Example JSON configuration with all possible fields (if some are not present, defaults from 'default' tags
+// of ConfigData will be used):
 //
 //	{
 //	    "name": "server-name",
@@ -43,16 +54,20 @@ type RegisterFn func(*grpc.Server)
 //	}
 func WithConfig(service ConfigService, key string) Option {
 	return func(cfg *Config) error {
-		data := struct {
-			Name    string
-			Address string
-		}{
-			Name:    DefName,
-			Address: DefAddress,
-		}
+		var data ConfigData
 
 		err := service.GetByKey(key, &data)
-		if err != nil && !service.IsNoSuchKeyError(err) {
+		if err != nil {
+			if !service.IsNoSuchKeyError(err) {
+				return err
+			}
+
+			if err = i2s.Instance().Convert(map[string]interface{}{}, &data); err != nil {
+				return err
+			}
+		}
+
+		if err = validate.Check(key, &data); err != nil {
 			return err
 		}
 
@@ -63,7 +78,7 @@ func WithConfig(service ConfigService, key string) Option {
 	}
 }
 
-// WithName option applies provided server name. Default: "grpc-server".
+// WithName option applies provided server name. Default: 'default' tag of ConfigData.Name field.
 func WithName(name string) Option {
 	return func(cfg *Config) error {
 		cfg.name = name
@@ -71,7 +86,8 @@ func WithName(name string) Option {
 	}
 }
 
-// WithAddress option applies provided address that will be listened to. Default: "127.0.0.1:50051".
+// WithAddress option applies provided address that will be listened to.
+// Default: 'default' tag of ConfigData.Address field.
 func WithAddress(address string) Option {
 	return func(cfg *Config) error {
 		cfg.address = address
@@ -7,6 +7,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
 	"github.com/lightstar/golib/pkg/grpc/grpcserver"
 )
 
@@ -45,7 +46,7 @@ func TestConfigService(t *testing.T) {
 			Address string
 		}{
 			Name:    "test-server",
-			Address: "test-address",
+			Address: "test-host:1234",
 		},
 	})
 
@@ -59,7 +60,7 @@ func TestConfigService(t *testing.T) {
 	})
 
 	require.Equal(t, "test-server", server.Name())
-	require.Equal(t, "test-address", server.Address())
+	require.Equal(t, "test-host:1234", server.Address())
 }
 
 func TestConfigServiceDefault(t *testing.T) {
@@ -96,3 +97,13 @@ func TestConfigWithoutRegisterFn(t *testing.T) {
 		_ = grpcserver.MustNew()
 	})
 }
+
+func TestConfigDataDefaults(t *testing.T) {
+	var data grpcserver.ConfigData
+
+	require.NoError(t, config.NewFromRaw(map[string]interface{}{}).Get(&data))
+	require.Equal(t, grpcserver.ConfigData{
+		Name:    grpcserver.DefName,
+		Address: grpcserver.DefAddress,
+	}, data)
+}
@@ -3,6 +3,8 @@ package httpserver
 import (
 	"net/http"
 
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/validate"
 	"github.com/lightstar/golib/pkg/log"
 )
 
@@ -40,9 +42,21 @@ type ConfigService interface {
 // Option function that is fed to New and MustNew. Obtain them using 'With' functions down below.
 type Option func(*Config) error
 
-// WithConfig option retrieves configuration from provided configuration service.
+// ConfigData structure describes configuration data of the server that WithConfig option retrieves.
+// It can be used to generate schema or sample of configuration, see config/schema package.
+type ConfigData struct {
+	Name              string `default:"http-server" validate:"nonempty" description:"Server name."`
+	Address           string `default:"127.0.0.1:8080" validate:"nonempty,hostport" description:"Address to listen to."`
+	ReadHeaderTimeout int64  `default:"2" validate:"min=0" description:"Maximum time in seconds to read http header."`
+	ReadTimeout       int64  `default:"3" validate:"min=0" description:"Maximum time in seconds to read request."`
+	WriteTimeout      int64  `default:"3" validate:"min=0" description:"Maximum time in seconds to write response."`
+}
+
+// WithConfig option retrieves configuration from provided configuration service and validates it according to
+// 'validate' tags of ConfigData, whatever configuration service is used.
 //
-// Example JSON configuration with all possible fields (if some are not present, defaults will be used):
+// Example JSON configuration with all possible fields (if some are not present, defaults from 'default' tags
+// of ConfigData will be used):
 //
 //	{
 //	    "name": "server-name",
@@ -50,22 +64,20 @@ type Option func(*Config) error
 //	}
 func WithConfig(service ConfigService, key string) Option {
 	return func(cfg *Config) error {
-		data := struct {
-			Name              string
-			Address           string
-			ReadHeaderTimeout int64
-			ReadTimeout       int64
-			WriteTimeout      int64
-		}{
-			Name:              DefName,
-			Address:           DefAddress,
-			ReadHeaderTimeout: DefReadHeaderTimeout,
-			ReadTimeout:       DefReadTimeout,
-			WriteTimeout:      DefWriteTimeout,
-		}
+		var data ConfigData
 
 		err := service.GetByKey(key, &data)
-		if err != nil && !service.IsNoSuchKeyError(err) {
+		if err != nil {
+			if !service.IsNoSuchKeyError(err) {
+				return err
+			}
+
+			if err = i2s.Instance().Convert(map[string]interface{}{}, &data); err != nil {
+				return err
+			}
+		}
+
+		if err = validate.Check(key, &data); err != nil {
 			return err
 		}
 
@@ -79,7 +91,7 @@ func WithConfig(service ConfigService, key string) Option {
 	}
 }
 
-// WithName option applies provided server name. Default: "http-server".
+// WithName option applies provided server name. Default: 'default' tag of ConfigData.Name field.
 func WithName(name string) Option {
 	return func(cfg *Config) error {
 		cfg.name = name
@@ -87,7 +99,8 @@ func WithName(name string) Option {
 	}
 }
 
-// WithAddress option applies provided address that will be listened to. Default: "127.0.0.1:8080".
+// WithAddress option applies provided address that will be listened to.
+// Default: 'default' tag of ConfigData.Address field.
 func WithAddress(address string) Option {
 	return func(cfg *Config) error {
 		cfg.address = address
@@ -95,7 +108,8 @@ func WithAddress(address string) Option {
 	}
 }
 
-// WithReadHeaderTimeout option applies provided maximum time in seconds to read http header. Default: 2.
+// WithReadHeaderTimeout option applies provided maximum time in seconds to read http header.
+// Default: 'default' tag of ConfigData.ReadHeaderTimeout field.
 func WithReadHeaderTimeout(readHeaderTimeout int64) Option {
 	return func(cfg *Config) error {
 		cfg.readHeaderTimeout = readHeaderTimeout
@@ -103,7 +117,8 @@ func WithReadHeaderTimeout(readHeaderTimeout int64) Option {
 	}
 }
 
-// WithReadTimeout option applies provided maximum time in seconds to read request. Default: 3.
+// WithReadTimeout option applies provided maximum time in seconds to read request.
+// Default: 'default' tag of ConfigData.ReadTimeout field.
 func WithReadTimeout(readTimeout int64) Option {
 	return func(cfg *Config) error {
 		cfg.readTimeout = readTimeout
@@ -111,7 +126,8 @@ func WithReadTimeout(readTimeout int64) Option {
 	}
 }
 
-// WithWriteTimeout option applies provided maximum time in seconds to write response. Default: 3.
+// WithWriteTimeout option applies provided maximum time in seconds to write response.
+// Default: 'default' tag of ConfigData.WriteTimeout field.
 func WithWriteTimeout(writeTimeout int64) Option {
 	return func(cfg *Config) error {
 		cfg.writeTimeout = writeTimeout
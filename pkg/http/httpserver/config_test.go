@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/validate"
 	"github.com/lightstar/golib/pkg/http/httpserver"
 )
 
@@ -54,7 +56,7 @@ func TestConfigService(t *testing.T) {
 			WriteTimeout      int64
 		}{
 			Name:              "test-server",
-			Address:           "test-address",
+			Address:           "test-host:1234",
 			ReadHeaderTimeout: 10,
 			ReadTimeout:       4,
 			WriteTimeout:      5,
@@ -68,7 +70,7 @@ func TestConfigService(t *testing.T) {
 	})
 
 	require.Equal(t, "test-server", server.Name())
-	require.Equal(t, "test-address", server.Address())
+	require.Equal(t, "test-host:1234", server.Address())
 	require.Equal(t, 10*time.Second, server.ReadHeaderTimeout())
 	require.Equal(t, 4*time.Second, server.ReadTimeout())
 	require.Equal(t, 5*time.Second, server.WriteTimeout())
@@ -92,6 +94,22 @@ func TestConfigServiceDefault(t *testing.T) {
 	require.Equal(t, httpserver.DefWriteTimeout*time.Second, server.WriteTimeout())
 }
 
+func TestConfigServiceNoKey(t *testing.T) {
+	configService := config.NewFromRaw(map[string]interface{}{})
+
+	var server *httpserver.Server
+
+	require.NotPanics(t, func() {
+		server = httpserver.MustNew(
+			httpserver.WithName("test-server"),
+			httpserver.WithConfig(configService, "key"),
+		)
+	})
+
+	require.Equal(t, httpserver.DefName, server.Name())
+	require.Equal(t, httpserver.DefAddress, server.Address())
+}
+
 func TestConfigServiceError(t *testing.T) {
 	configService := configtest.New(nil)
 
@@ -99,3 +117,58 @@ func TestConfigServiceError(t *testing.T) {
 		_ = httpserver.MustNew(httpserver.WithConfig(configService, "key"))
 	})
 }
+
+func TestConfigServiceTagDefaults(t *testing.T) {
+	configService := config.NewFromRaw(map[string]interface{}{
+		"key": map[string]interface{}{
+			"name": "test-server",
+		},
+	})
+
+	var server *httpserver.Server
+
+	require.NotPanics(t, func() {
+		server = httpserver.MustNew(httpserver.WithConfig(configService, "key"))
+	})
+
+	require.Equal(t, "test-server", server.Name())
+	require.Equal(t, httpserver.DefAddress, server.Address())
+	require.Equal(t, httpserver.DefReadHeaderTimeout*time.Second, server.ReadHeaderTimeout())
+	require.Equal(t, httpserver.DefReadTimeout*time.Second, server.ReadTimeout())
+	require.Equal(t, httpserver.DefWriteTimeout*time.Second, server.WriteTimeout())
+}
+
+func TestConfigServiceInvalid(t *testing.T) {
+	configService := config.NewFromRaw(map[string]interface{}{
+		"key": map[string]interface{}{
+			"address":     "localhost",
+			"readTimeout": -1,
+		},
+	})
+
+	_, err := httpserver.New(httpserver.WithConfig(configService, "key"))
+	require.ErrorIs(t, err, validate.ErrInvalidValue)
+	require.ErrorContains(t, err, "'key.address'")
+	require.ErrorContains(t, err, "'key.readTimeout'")
+
+	mockService := configtest.New(map[string]interface{}{
+		"key": httpserver.ConfigData{Name: "test-server", Address: "localhost"},
+	})
+
+	_, err = httpserver.New(httpserver.WithConfig(mockService, "key"))
+	require.ErrorIs(t, err, validate.ErrInvalidValue)
+	require.ErrorContains(t, err, "'key.address'")
+}
+
+func TestConfigDataDefaults(t *testing.T) {
+	var data httpserver.ConfigData
+
+	require.NoError(t, config.NewFromRaw(map[string]interface{}{}).Get(&data))
+	require.Equal(t, httpserver.ConfigData{
+		Name:              httpserver.DefName,
+		Address:           httpserver.DefAddress,
+		ReadHeaderTimeout: httpserver.DefReadHeaderTimeout,
+		ReadTimeout:       httpserver.DefReadTimeout,
+		WriteTimeout:      httpserver.DefWriteTimeout,
+	}, data)
+}
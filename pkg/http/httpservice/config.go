@@ -1,6 +1,8 @@
 package httpservice
 
 import (
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/validate"
 	"github.com/lightstar/golib/pkg/log"
 )
 
@@ -23,9 +25,18 @@ type ConfigService interface {
 // Option function that is fed to New and MustNew. Obtain them using 'With' functions down below.
 type Option func(*Config) error
 
-// WithConfig option retrieves configuration from provided configuration service.
+// ConfigData structure describes configuration data of the service that WithConfig option retrieves.
+// It can be used to generate schema or sample of configuration, see config/schema package.
+type ConfigData struct {
+	Name  string `default:"http-service" validate:"nonempty" description:"Service name."`
+	Debug bool   `description:"Debug mode."`
+}
+
+// WithConfig option retrieves configuration from provided configuration service and validates it according to
+// 'validate' tags of ConfigData, whatever configuration service is used.
 //
-// Example JSON configuration with all possible fields (if some are not present, defaults will be used):
+// Example JSON configuration with all possible fields (if some are not present, defaults from 'default' tags
+// of ConfigData will be used):
 //
 //	{
 //	    "name": "service-name",
@@ -33,15 +44,20 @@ type Option func(*Config) error
 //	}
 func WithConfig(service ConfigService, key string) Option {
 	return func(cfg *Config) error {
-		data := struct {
-			Name  string
-			Debug bool
-		}{
-			Name: DefName,
-		}
+		var data ConfigData
 
 		err := service.GetByKey(key, &data)
-		if err != nil && !service.IsNoSuchKeyError(err) {
+		if err != nil {
+			if !service.IsNoSuchKeyError(err) {
+				return err
+			}
+
+			if err = i2s.Instance().Convert(map[string]interface{}{}, &data); err != nil {
+				return err
+			}
+		}
+
+		if err = validate.Check(key, &data); err != nil {
 			return err
 		}
 
@@ -52,7 +68,7 @@ func WithConfig(service ConfigService, key string) Option {
 	}
 }
 
-// WithName option applies provided service name. Default: "http-service".
+// WithName option applies provided service name. Default: 'default' tag of ConfigData.Name field.
 func WithName(name string) Option {
 	return func(cfg *Config) error {
 		cfg.name = name
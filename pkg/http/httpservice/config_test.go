@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
 	"github.com/lightstar/golib/pkg/http/httpservice"
 )
 
@@ -77,3 +78,12 @@ func TestConfigServiceError(t *testing.T) {
 		_ = httpservice.MustNew(httpservice.WithConfig(configService, "key"))
 	})
 }
+
+func TestConfigDataDefaults(t *testing.T) {
+	var data httpservice.ConfigData
+
+	require.NoError(t, config.NewFromRaw(map[string]interface{}{}).Get(&data))
+	require.Equal(t, httpservice.ConfigData{
+		Name: httpservice.DefName,
+	}, data)
+}
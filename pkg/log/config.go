@@ -2,6 +2,8 @@ package log
 
 import (
 	"go.uber.org/zap/zapcore"
+
+	"github.com/lightstar/golib/pkg/config/validate"
 )
 
 // Config structure with logger configuration. Shouldn't be created manually.
@@ -21,6 +23,13 @@ type ConfigService interface {
 // Option function that is fed to New and MustNew. Obtain them using 'With' functions down below.
 type Option func(*Config) error
 
+// ConfigData structure describes configuration data of the logger that WithConfig option retrieves.
+// It can be used to generate schema or sample of configuration, see config/schema package.
+type ConfigData struct {
+	Name  string `description:"Logger name."`
+	Debug bool   `description:"Debug mode."`
+}
+
 // WithConfig option retrieves configuration from provided configuration service.
 //
 // Example JSON configuration with all possible fields (if some are not present, defaults will be used):
@@ -31,16 +40,17 @@ type Option func(*Config) error
 //	}
 func WithConfig(service ConfigService, key string) Option {
 	return func(cfg *Config) error {
-		data := struct {
-			Name  string
-			Debug bool
-		}{}
+		var data ConfigData
 
 		err := service.GetByKey(key, &data)
 		if err != nil && !service.IsNoSuchKeyError(err) {
 			return err
 		}
 
+		if err = validate.Check(key, &data); err != nil {
+			return err
+		}
+
 		cfg.name = data.Name
 		cfg.debug = data.Debug
 
@@ -2,6 +2,9 @@ package mongo
 
 import (
 	"time"
+
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/validate"
 )
 
 const (
@@ -29,9 +32,19 @@ type ConfigService interface {
 // Option function that is fed to NewClient and MustNewClient. Obtain them using 'With' functions down below.
 type Option func(*Config) error
 
-// WithConfig option retrieves configuration from provided configuration service.
+// ConfigData structure describes configuration data of the mongo client that WithConfig option retrieves.
+// It can be used to generate schema or sample of configuration, see config/schema package.
+type ConfigData struct {
+	Address        string `default:"127.0.0.1:27017" validate:"nonempty,hostport" description:"Mongo server address."`
+	ConnectTimeout int    `default:"15" validate:"min=0" description:"Mongo client connect timeout in seconds."`
+	SocketTimeout  int    `default:"30" validate:"min=0" description:"Mongo client socket timeout in seconds."`
+}
+
+// WithConfig option retrieves configuration from provided configuration service and validates it according to
+// 'validate' tags of ConfigData, whatever configuration service is used.
 //
-// Example JSON configuration with all possible fields (if some are not present, defaults will be used):
+// Example JSON configuration with all possible fields (if some are not present, defaults from 'default' tags
+// of ConfigData will be used):
 //
 //	{
 //	    "address": "127.0.0.1:27017",
@@ -40,18 +53,20 @@ type Option func(*Config) error
 //	}
 func WithConfig(service ConfigService, key string) Option {
 	return func(cfg *Config) error {
-		data := struct {
-			Address        string
-			ConnectTimeout int
-			SocketTimeout  int
-		}{
-			Address:        DefAddress,
-			ConnectTimeout: DefConnectTimeout,
-			SocketTimeout:  DefSocketTimeout,
-		}
+		var data ConfigData
 
 		err := service.GetByKey(key, &data)
-		if err != nil && !service.IsNoSuchKeyError(err) {
+		if err != nil {
+			if !service.IsNoSuchKeyError(err) {
+				return err
+			}
+
+			if err = i2s.Instance().Convert(map[string]interface{}{}, &data); err != nil {
+				return err
+			}
+		}
+
+		if err = validate.Check(key, &data); err != nil {
 			return err
 		}
 
@@ -63,7 +78,7 @@ func WithConfig(service ConfigService, key string) Option {
 	}
 }
 
-// WithAddress option applies provided mongo server address. Default: "127.0.0.1:27017".
+// WithAddress option applies provided mongo server address. Default: 'default' tag of ConfigData.Address field.
 func WithAddress(address string) Option {
 	return func(cfg *Config) error {
 		cfg.address = address
@@ -71,7 +86,8 @@ func WithAddress(address string) Option {
 	}
 }
 
-// WithConnectTimeout option applies provided mongo client connect timeout in seconds. Default: 15.
+// WithConnectTimeout option applies provided mongo client connect timeout in seconds.
+// Default: 'default' tag of ConfigData.ConnectTimeout field.
 func WithConnectTimeout(idleTimeout int) Option {
 	return func(cfg *Config) error {
 		cfg.connectTimeout = time.Duration(idleTimeout) * time.Second
@@ -79,7 +95,8 @@ func WithConnectTimeout(idleTimeout int) Option {
 	}
 }
 
-// WithSocketTimeout option applies provided mongo client socket timeout in seconds. Default: 30.
+// WithSocketTimeout option applies provided mongo client socket timeout in seconds.
+// Default: 'default' tag of ConfigData.SocketTimeout field.
 func WithSocketTimeout(socketTimeout int) Option {
 	return func(cfg *Config) error {
 		cfg.socketTimeout = time.Duration(socketTimeout) * time.Second
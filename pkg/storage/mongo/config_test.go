@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
+	"github.com/lightstar/golib/pkg/config/validate"
 	"github.com/lightstar/golib/pkg/storage/mongo"
 )
 
@@ -49,7 +51,7 @@ func TestConfigService(t *testing.T) {
 			ConnectTimeout int
 			SocketTimeout  int
 		}{
-			Address:        "test_address",
+			Address:        "test-host:1234",
 			ConnectTimeout: 120,
 			SocketTimeout:  180,
 		},
@@ -63,7 +65,7 @@ func TestConfigService(t *testing.T) {
 
 	defer client.Close()
 
-	require.Equal(t, "test_address", client.Address())
+	require.Equal(t, "test-host:1234", client.Address())
 	require.Equal(t, 120*time.Second, client.ConnectTimeout())
 	require.Equal(t, 180*time.Second, client.SocketTimeout())
 }
@@ -93,3 +95,24 @@ func TestConfigError(t *testing.T) {
 		_ = mongo.MustNewClient(mongo.WithConfig(configService, "key"))
 	})
 }
+
+func TestConfigServiceInvalid(t *testing.T) {
+	configService := configtest.New(map[string]interface{}{
+		"key": mongo.ConfigData{Address: "localhost"},
+	})
+
+	_, err := mongo.NewClient(mongo.WithConfig(configService, "key"))
+	require.ErrorIs(t, err, validate.ErrInvalidValue)
+	require.ErrorContains(t, err, "'key.address'")
+}
+
+func TestConfigDataDefaults(t *testing.T) {
+	var data mongo.ConfigData
+
+	require.NoError(t, config.NewFromRaw(map[string]interface{}{}).Get(&data))
+	require.Equal(t, mongo.ConfigData{
+		Address:        mongo.DefAddress,
+		ConnectTimeout: mongo.DefConnectTimeout,
+		SocketTimeout:  mongo.DefSocketTimeout,
+	}, data)
+}
@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"github.com/gomodule/redigo/redis"
+
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/validate"
 )
 
 const (
@@ -32,9 +35,19 @@ type ConfigService interface {
 // Option function that is fed to NewClient and MustNewClient. Obtain them using 'With' functions down below.
 type Option func(*Config) error
 
-// WithConfig option retrieves configuration from provided configuration service.
+// ConfigData structure describes configuration data of the redis client that WithConfig option retrieves.
+// It can be used to generate schema or sample of configuration, see config/schema package.
+type ConfigData struct {
+	Address     string `default:"127.0.0.1:6379" validate:"nonempty,hostport" description:"Redis server address."`
+	MaxIdle     int    `default:"3" validate:"min=0" description:"Maximum number of idle redis connections in the pool."`
+	IdleTimeout int    `default:"600" validate:"min=0" description:"Timeout in seconds to drop idle connections."`
+}
+
+// WithConfig option retrieves configuration from provided configuration service and validates it according to
+// 'validate' tags of ConfigData, whatever configuration service is used.
 //
-// Example JSON configuration with all possible fields (if some are not present, defaults will be used):
+// Example JSON configuration with all possible fields (if some are not present, defaults from 'default' tags
+// of ConfigData will be used):
 //
 //	{
 //	    "address": "127.0.0.1:6379",
@@ -43,18 +56,20 @@ type Option func(*Config) error
 //	}
 func WithConfig(service ConfigService, key string) Option {
 	return func(cfg *Config) error {
-		data := struct {
-			Address     string
-			MaxIdle     int
-			IdleTimeout int
-		}{
-			Address:     DefAddress,
-			MaxIdle:     DefMaxIdle,
-			IdleTimeout: DefIdleTimeout,
-		}
+		var data ConfigData
 
 		err := service.GetByKey(key, &data)
-		if err != nil && !service.IsNoSuchKeyError(err) {
+		if err != nil {
+			if !service.IsNoSuchKeyError(err) {
+				return err
+			}
+
+			if err = i2s.Instance().Convert(map[string]interface{}{}, &data); err != nil {
+				return err
+			}
+		}
+
+		if err = validate.Check(key, &data); err != nil {
 			return err
 		}
 
@@ -66,7 +81,7 @@ func WithConfig(service ConfigService, key string) Option {
 	}
 }
 
-// WithAddress option applies provided redis server address. Default: "127.0.0.1:6379".
+// WithAddress option applies provided redis server address. Default: 'default' tag of ConfigData.Address field.
 func WithAddress(address string) Option {
 	return func(cfg *Config) error {
 		cfg.address = address
@@ -74,7 +89,8 @@ func WithAddress(address string) Option {
 	}
 }
 
-// WithMaxIdle option applies provided maximum number of idle redis connections in the pool. Default: 3.
+// WithMaxIdle option applies provided maximum number of idle redis connections in the pool.
+// Default: 'default' tag of ConfigData.MaxIdle field.
 func WithMaxIdle(maxIdle int) Option {
 	return func(cfg *Config) error {
 		cfg.maxIdle = maxIdle
@@ -83,7 +99,7 @@ func WithMaxIdle(maxIdle int) Option {
 }
 
 // WithIdleTimeout option applies provided timeout in seconds after which idle connections will be dropped away.
-// Default: 600.
+// Default: 'default' tag of ConfigData.IdleTimeout field.
 func WithIdleTimeout(idleTimeout int) Option {
 	return func(cfg *Config) error {
 		cfg.idleTimeout = time.Duration(idleTimeout) * time.Second
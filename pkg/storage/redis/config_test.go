@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
 	"github.com/lightstar/golib/pkg/storage/redis"
 )
 
@@ -49,7 +50,7 @@ func TestConfigService(t *testing.T) {
 			MaxIdle     int
 			IdleTimeout int
 		}{
-			Address:     "test_address",
+			Address:     "test-host:1234",
 			MaxIdle:     10,
 			IdleTimeout: 1800,
 		},
@@ -63,7 +64,7 @@ func TestConfigService(t *testing.T) {
 
 	defer client.Close()
 
-	require.Equal(t, "test_address", client.Address())
+	require.Equal(t, "test-host:1234", client.Address())
 	require.Equal(t, 10, client.MaxIdle())
 	require.Equal(t, 1800*time.Second, client.IdleTimeout())
 }
@@ -93,3 +94,14 @@ func TestConfigServiceError(t *testing.T) {
 		_ = redis.MustNewClient(redis.WithConfig(configService, "key"))
 	})
 }
+
+func TestConfigDataDefaults(t *testing.T) {
+	var data redis.ConfigData
+
+	require.NoError(t, config.NewFromRaw(map[string]interface{}{}).Get(&data))
+	require.Equal(t, redis.ConfigData{
+		Address:     redis.DefAddress,
+		MaxIdle:     redis.DefMaxIdle,
+		IdleTimeout: redis.DefIdleTimeout,
+	}, data)
+}
@@ -1,6 +1,8 @@
 package rdidgen
 
 import (
+	"github.com/lightstar/golib/pkg/config/i2s"
+	"github.com/lightstar/golib/pkg/config/validate"
 	"github.com/lightstar/golib/pkg/storage/redis"
 )
 
@@ -22,23 +24,37 @@ type ConfigService interface {
 // Option function that is fed to New and MustNew. Obtain them using 'With' functions down below.
 type Option func(*Config) error
 
-// WithConfig option retrieves configuration from provided configuration service.
+// ConfigData structure describes configuration data of the generator that WithConfig option retrieves.
+// It can be used to generate schema or sample of configuration, see config/schema package.
+type ConfigData struct {
+	KeyPrefix string `default:"entity" validate:"nonempty" description:"Redis key prefix that stores next id."`
+}
+
+// WithConfig option retrieves configuration from provided configuration service and validates it according to
+// 'validate' tags of ConfigData, whatever configuration service is used.
 //
-// Example JSON configuration with all possible fields (if some are not present, defaults will be used):
+// Example JSON configuration with all possible fields (if some are not present, defaults from 'default' tags
+// of ConfigData will be used):
 //
 //	{
 //	    "keyPrefix": "user"
 //	}
 func WithConfig(service ConfigService, key string) Option {
 	return func(cfg *Config) error {
-		data := struct {
-			KeyPrefix string
-		}{
-			KeyPrefix: DefKeyPrefix,
-		}
+		var data ConfigData
 
 		err := service.GetByKey(key, &data)
-		if err != nil && !service.IsNoSuchKeyError(err) {
+		if err != nil {
+			if !service.IsNoSuchKeyError(err) {
+				return err
+			}
+
+			if err = i2s.Instance().Convert(map[string]interface{}{}, &data); err != nil {
+				return err
+			}
+		}
+
+		if err = validate.Check(key, &data); err != nil {
 			return err
 		}
 
@@ -56,7 +72,8 @@ func WithRedisClient(redisClient *redis.Client) Option {
 	}
 }
 
-// WithKeyPrefix option applies provided redis key prefix that stores next id. Default: "entity".
+// WithKeyPrefix option applies provided redis key prefix that stores next id.
+// Default: 'default' tag of ConfigData.KeyPrefix field.
 func WithKeyPrefix(keyPrefix string) Option {
 	return func(cfg *Config) error {
 		cfg.keyPrefix = keyPrefix
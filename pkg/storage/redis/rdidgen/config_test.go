@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/lightstar/golib/internal/test/configtest"
+	"github.com/lightstar/golib/pkg/config"
 	"github.com/lightstar/golib/pkg/storage/redis/rdidgen"
 )
 
@@ -70,3 +71,12 @@ func TestConfigServiceError(t *testing.T) {
 		_ = rdidgen.MustNew(rdidgen.WithConfig(configService, "key"))
 	})
 }
+
+func TestConfigDataDefaults(t *testing.T) {
+	var data rdidgen.ConfigData
+
+	require.NoError(t, config.NewFromRaw(map[string]interface{}{}).Get(&data))
+	require.Equal(t, rdidgen.ConfigData{
+		KeyPrefix: rdidgen.DefKeyPrefix,
+	}, data)
+}